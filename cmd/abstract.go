@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/spf13/cobra"
+)
+
+// used for flags.
+var abstractFormat string
+
+func getAbstractFormat() abstract.Format {
+	f, err := abstract.ParseFormat(abstractFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return f
+}
+
+// addAbstractFormatFlag adds --abstract-format, which leaves abstracts as the source wrote them by default
+func addAbstractFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&abstractFormat, "abstract-format", string(abstract.Raw), "format to convert abstracts to (html, text, markdown or raw)")
+}
@@ -3,10 +3,7 @@ package cmd
 import (
 	"encoding/csv"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -14,17 +11,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
 	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
 	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/latex"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// used for flags.
-	start   int
-	results int
-	ids     string
-	query   string
+	start     int
+	results   int
+	ids       string
+	query     string
+	latexMode string
 
 	arxivCmd = &cobra.Command{
 		Use:   "arxiv",
@@ -75,45 +79,33 @@ Thank you to arXiv for use of its open access interoperability.`,
 				log.Fatal("--query or --ids required.")
 			}
 
+			checkLayout()
+			url, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if planOnly {
+				planArxivHarvest(url, queries)
+				return
+			}
 			wr := csv.NewWriter(os.Stdout)
 
+			header := arxivHeader
+			if updateVersions {
+				header = versionHeader
+			}
 			// CSV header
-			err = wr.Write([]string{
-				"id",
-				"field_edtf_date_issued",
-				"title",
-				"field_full_title",
-				"field_abstract",
-				"field_linked_agent",
-				"field_publisher",
-				"field_identifier",
-				"field_related_item",
-				"field_rights",
-				"field_subject",
-				"file",
-				"arXiv search query",
-			})
+			err = wr.Write(header)
 			if err != nil {
 				log.Fatalf("Unable to write to CSV: %v", err)
 			}
-			categoryNames := arxiv.GetCategoryLabels()
+			opts := loadArxivOptions(cmd)
+			opts.download = true
+			defer writeUnmapped(opts.crosswalk)
+			tracker := newTracker("search arxiv")
+			defer writeSummary(tracker)
 			for _, query := range queries {
-
-				params := url.Values{}
-				if ids != "" {
-					params.Set("id_list", ids)
-
-				} else {
-					params.Set("search_query", query)
-				}
-
-				params.Set("start", strconv.Itoa(start))
-				params.Set("max_results", strconv.Itoa(results))
-
-				url, err := cmd.Flags().GetString("url")
-				if err != nil {
-					log.Fatal(err)
-				}
+				params := arxivQueryParams(query)
 				apiURL := fmt.Sprintf("%s?%s", url, params.Encode())
 
 				log.Printf("Accessing %s\n", apiURL)
@@ -122,8 +114,7 @@ Thank you to arXiv for use of its open access interoperability.`,
 				if err != nil {
 					log.Fatal(err)
 				}
-				pattern := `/abs/([0-9a-z\-]+(\/|\.)\d+)(?:v\d+)?$`
-				re := regexp.MustCompile(pattern)
+				tracker.AddTotal(result.TotalResults - result.StartIndex)
 
 				for {
 					for _, e := range result.Entries {
@@ -131,118 +122,28 @@ Thank you to arXiv for use of its open access interoperability.`,
 						log.Println("Pausing between requests. arXiv requests a three second delay between API requests...")
 						time.Sleep(3 * time.Second)
 
-						matches := re.FindStringSubmatch(e.ID)
-						if len(matches) <= 1 {
+						id, version, ok := splitArxivID(e.ID)
+						if !ok {
 							log.Fatal(e.ID)
 						}
+						e.ID = id
 
-						e.ID = matches[1]
+						if updateVersions {
+							tracker.Done("arxiv", updateArxivVersions(wr, e.ID))
+							continue
+						}
 
 						log.Println("Fetching", e.ID)
-						url := fmt.Sprintf("https://export.arxiv.org/oai2?verb=GetRecord&identifier=oai:arXiv.org:%s&metadataPrefix=arXiv", e.ID)
-						oai := arxiv.GetOaiRecord(url)
-						if e.JournalRef != "" {
-							e.JournalRef = fmt.Sprintf(`{"title": "%s"}`, e.JournalRef)
-						}
-						var categories = []string{}
-						for _, c := range e.Categories {
-							term := strings.Split(c.Term, ".")
-							group := "Physics"
-							switch term[0] {
-							case "cs":
-								group = "Computer Science"
-							case "econ":
-								group = "Economics"
-							case "eess":
-								group = "Electrical Engineering and Systems Science"
-							case "math":
-								group = "Mathematics"
-							case "astro-ph":
-								group = "Physics--Astrophysics"
-							case "cond-mat":
-								group = "Physics--Condensed Matter"
-							case "nlin":
-								group = "Physics--Nonliner Sciences"
-							case "q-bio":
-								group = "Quantitative Biology"
-							case "q-fin":
-								group = "Quantitative Finance"
-							case "stat":
-								group = "Statistics"
-							}
-							if categoryName, ok := categoryNames[c.Term]; ok {
-								c.Term = fmt.Sprintf("%s--%s", group, categoryName)
-							}
-							categories = append(categories, c.Term)
-						}
-						var identifiers = []string{
-							fmt.Sprintf(`{"attr0":"arxiv","value":"%s"}`, e.ID),
-						}
-						if e.DOI != "" {
-							doi := fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, e.DOI)
-							identifiers = append(identifiers, doi)
-						}
-						err = wr.Write([]string{
-							e.ID,
-							strings.Split(e.Published.String(), " ")[0],
-							utils.TrimToMaxLen(e.Title, 255),
-							e.Title,
-							e.Summary,
-							oai["field_linked_agent"],
-							"arXiv",
-							strings.Join(identifiers, "|"),
-							e.JournalRef,
-							oai["field_rights"],
-							strings.Join(categories, "|"),
-							e.PDF,
-							query,
-						})
+						row, pdf := arxivRow(e, version, query, opts)
+						err = wr.Write(storeLayout(header, row))
 						if err != nil {
 							log.Fatalf("Unable to write to CSV: %v", err)
 						}
 						wr.Flush()
 
-						if e.PDF != "" {
-							downloadDirectory := "papers"
-							if err := os.MkdirAll(downloadDirectory, 0755); err != nil {
-								fmt.Println("Error creating directory:", err)
-								return
-							}
-
-							_, filename := filepath.Split(e.PDF)
-							// Ensure the filename has a .pdf extension
-							if !strings.HasSuffix(filename, ".pdf") {
-								filename = fmt.Sprintf("%s.pdf", filename)
-							}
-							filePath := filepath.Join(downloadDirectory, filename)
-
-							if _, err := os.Stat(filePath); os.IsNotExist(err) {
-
-								file, err := os.Create(filePath)
-								if err != nil {
-									fmt.Println("Error creating file:", err)
-									return
-								}
-								defer file.Close()
-
-								response, err := http.Get(e.PDF)
-								if err != nil {
-									fmt.Println("Error downloading PDF:", err)
-									return
-								}
-								defer response.Body.Close()
-
-								if response.StatusCode != http.StatusOK {
-									fmt.Printf("Error: HTTP status %d\n", response.StatusCode)
-									return
-								}
-								_, err = io.Copy(file, response.Body)
-								if err != nil {
-									fmt.Println("Error copying PDF content to file:", err)
-									return
-								}
-							}
-						}
+						tracker.PDF(pdf != e.PDF)
+						tracker.License(record.New(header, row).Get("field_rights") != "")
+						tracker.Done("arxiv", progress.Harvested)
 					}
 
 					log.Println("Pausing between requests. arXiv requests a three second delay between API requests...")
@@ -266,6 +167,181 @@ Thank you to arXiv for use of its open access interoperability.`,
 	}
 )
 
+var arxivHeader = []string{
+	"id",
+	"field_edtf_date_issued",
+	"title",
+	"field_full_title",
+	"field_abstract",
+	"field_linked_agent",
+	"field_affiliation",
+	"field_publisher",
+	"field_identifier",
+	"field_related_item",
+	"field_rights",
+	"field_subject",
+	"field_subject_uri",
+	"file",
+	"arXiv version",
+	"arXiv search query",
+}
+
+// arxivOptions are the settings entries are turned into rows with
+type arxivOptions struct {
+	format    abstract.Format
+	convert   bool
+	mathMode  latex.MathMode
+	taxonomy  *arxiv.Taxonomy
+	crosswalk *subject.Crosswalk
+	// download the PDF (and any source or ancillary files) and parse it with GROBID
+	download bool
+	// limiter spaces out the OAI requests that aren't cached
+	limiter *ratelimit.Limiter
+}
+
+// loadArxivOptions reads the flags arxivRow uses
+func loadArxivOptions(cmd *cobra.Command) arxivOptions {
+	o := arxivOptions{
+		format:   getAbstractFormat(),
+		convert:  latexMode != "none",
+		mathMode: latex.Unicode,
+	}
+	var err error
+	if o.convert {
+		o.mathMode, err = latex.ParseMathMode(latexMode)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	taxonomyPath, err := cmd.Flags().GetString("taxonomy")
+	if err != nil {
+		log.Fatal(err)
+	}
+	o.taxonomy, err = arxiv.LoadTaxonomy(taxonomyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	o.crosswalk = loadCrosswalk()
+
+	return o
+}
+
+var arxivIDPattern = regexp.MustCompile(`/abs/([0-9a-z\-]+(\/|\.)\d+)(v\d+)?$`)
+
+// splitArxivID gets the arXiv ID and version from an entry's abs URL
+// e.g. http://arxiv.org/abs/2101.00001v2 is 2101.00001 version 2
+func splitArxivID(absURL string) (string, int, bool) {
+	matches := arxivIDPattern.FindStringSubmatch(absURL)
+	if len(matches) <= 1 {
+		return "", 0, false
+	}
+
+	version := 1
+	if matches[3] != "" {
+		version, _ = strconv.Atoi(strings.TrimPrefix(matches[3], "v"))
+	}
+
+	return matches[1], version, true
+}
+
+// arxivOai fetches the OAI record of an arXiv paper, caching it as oai.xml
+// limiter, if there is one, spaces out the requests that aren't cached
+func arxivOai(id string, limiter *ratelimit.Limiter) map[string]string {
+	cacheDir, err := utils.MkTmpDir(filepath.Join("arxiv", id))
+	if err != nil {
+		log.Fatal("Unable to write to tmp filesystem")
+	}
+	url := fmt.Sprintf("https://export.arxiv.org/oai2?verb=GetRecord&identifier=oai:arXiv.org:%s&metadataPrefix=arXiv", id)
+	oaiFile := filepath.Join(cacheDir, "oai.xml")
+	if limiter != nil && !fileExists(oaiFile) {
+		limiter.Wait()
+	}
+
+	return arxiv.ParseOaiResponse(utils.GetResult(oaiFile, url, "application/xml"))
+}
+
+// arxivRow turns an entry into a row of arxivHeader
+// returning the local path of its PDF, or its URL if it wasn't downloaded
+func arxivRow(e arxiv.Entry, version int, query string, o arxivOptions) ([]string, string) {
+	oai := arxivOai(e.ID, o.limiter)
+	if o.convert {
+		e.ConvertLatex(o.mathMode)
+		if oai != nil {
+			oai["field_linked_agent"] = latex.ToUnicode(oai["field_linked_agent"], o.mathMode)
+		}
+	}
+	if e.JournalRef != "" {
+		e.JournalRef = fmt.Sprintf(`{"title": "%s"}`, e.JournalRef)
+	}
+	// aliases of the same category map to the same subject, which Add only keeps once
+	categories := subject.Terms{}
+	for _, c := range e.Categories {
+		term := o.taxonomy.Canonical(c.Term)
+		subject, ok := o.taxonomy.Subject(term)
+		if !ok {
+			subject = c.Term
+		}
+		categories = categories.Add(mapSubject(o.crosswalk, "arxiv", term, subject)...)
+	}
+	var identifiers = []string{
+		fmt.Sprintf(`{"attr0":"arxiv","value":"%s"}`, e.ID),
+	}
+	if e.DOI != "" {
+		doi := fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, e.DOI)
+		identifiers = append(identifiers, doi)
+	}
+	pdf := e.PDF
+	if o.download {
+		if e.PDF != "" {
+			filePath := heldArxivPdf(e.ID, version)
+			if err := utils.DownloadPdf(e.PDF, filePath); err == nil {
+				pdf = filePath
+			}
+		}
+		downloadArxivBundles(e.ID, version)
+	}
+	if doc := parsePdf(pdf, filepath.Join(os.TempDir(), "arxiv", e.ID)); doc != nil {
+		// the entry's authors are shared with the feed so update a copy
+		e.Authors = append([]arxiv.Author{}, e.Authors...)
+		for i, author := range e.Authors {
+			if author.Affiliation != "" {
+				continue
+			}
+			names := strings.Fields(author.Name)
+			if len(names) == 0 {
+				continue
+			}
+			affiliations := doc.AffiliationsFor(names[len(names)-1])
+			e.Authors[i].Affiliation = strings.Join(affiliations, "; ")
+		}
+	}
+	var affiliations = []string{}
+	for _, author := range e.Authors {
+		if author.Affiliation != "" && !utils.StrInSlice(author.Affiliation, affiliations) {
+			affiliations = append(affiliations, author.Affiliation)
+		}
+	}
+
+	return []string{
+		e.ID,
+		strings.Split(e.Published.String(), " ")[0],
+		utils.TrimToMaxLen(e.Title, 255),
+		e.Title,
+		abstract.FromArxiv(e.Summary, o.format),
+		oai["field_linked_agent"],
+		strings.Join(affiliations, "|"),
+		"arXiv",
+		strings.Join(identifiers, "|"),
+		e.JournalRef,
+		oai["field_rights"],
+		categories.Labels(),
+		categories.URIs(),
+		e.PDF,
+		fmt.Sprintf("v%d", version),
+		query,
+	}, pdf
+}
+
 func init() {
 	searchCmd.AddCommand(arxivCmd)
 
@@ -276,4 +352,17 @@ func init() {
 	arxivCmd.Flags().IntVarP(&results, "results", "r", 10, "The number of results to return in a response")
 	arxivCmd.Flags().String("directory-listing", "", "URL to a web page listing faculty email addresses")
 	arxivCmd.Flags().String("emails", "", "List of emails to search for")
+	addAbstractFormatFlag(arxivCmd)
+	arxivCmd.Flags().String("taxonomy", "", "path to an arXiv taxonomy JSON file created by \"papercut taxonomy refresh\" (defaults to the taxonomy built into papercut)")
+	arxivCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping arXiv categories to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	arxivCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	arxivCmd.Flags().StringVar(&latexMode, "latex", "unicode", "how to convert LaTeX in titles, abstracts and names: unicode, tex (keep $...$ math), mathml or none")
+	arxivCmd.Flags().BoolVar(&updateVersions, "update-versions", false, "only download new versions of papers already in the papers directory and report them")
+	arxivCmd.Flags().BoolVar(&withSource, "with-source", false, "also download and unpack the LaTeX source of each paper into papers/<id>v<version>/source")
+	arxivCmd.Flags().BoolVar(&withAncillary, "with-ancillary", false, "also download each paper's ancillary files into papers/<id>v<version>/anc")
+	arxivCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each paper with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	arxivCmd.Flags().BoolVar(&planOnly, "plan", false, "only fetch the first page of each query and print how many results, requests and PDFs the harvest would need and how long it would take")
+	arxivCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	arxivCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+	arxivCmd.Flags().StringVar(&grobidURL, "grobid-url", "", "URL of a GROBID server to parse downloaded PDFs with (e.g. http://localhost:8070)")
 }
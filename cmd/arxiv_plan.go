@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+)
+
+// used for flags.
+var planOnly bool
+
+// arxivQueryParams are the arXiv API parameters for a query
+// or the --ids list if one was given
+func arxivQueryParams(query string) url.Values {
+	params := url.Values{}
+	if ids != "" {
+		params.Set("id_list", ids)
+	} else {
+		params.Set("search_query", query)
+	}
+
+	params.Set("start", strconv.Itoa(start))
+	params.Set("max_results", strconv.Itoa(results))
+
+	return params
+}
+
+// arxivHarvestOptions counts the requests search arxiv makes for each result with the current flags
+func arxivHarvestOptions() arxiv.HarvestOptions {
+	opts := arxiv.HarvestOptions{
+		PageSize: results,
+		// the OAI record and the PDF
+		RequestsPerEntry: 2,
+		DelaysPerEntry:   1,
+	}
+	if updateVersions {
+		// the arXivRaw record, the PDF count is an upper bound as only new versions are downloaded
+		opts.RequestsPerEntry = 1
+	}
+	if withSource {
+		opts.RequestsPerEntry++
+		opts.DelaysPerEntry++
+	}
+	if withAncillary {
+		// the abstract page, ancillary files themselves can't be known in advance
+		opts.RequestsPerEntry++
+		opts.DelaysPerEntry++
+	}
+
+	return opts
+}
+
+// planArxivHarvest fetches the first page of every query
+// and prints an estimate of the work needed to harvest all of their results
+func planArxivHarvest(apiURL string, queries []string) {
+	opts := arxivHarvestOptions()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUERY\tRESULTS\tPAGES\tREQUESTS\tPDFS\tEST. SIZE\tEST. TIME")
+
+	var total arxiv.Estimate
+	for i, query := range queries {
+		if i > 0 {
+			log.Println("Pausing between requests. arXiv requests a three second delay between API requests...")
+			time.Sleep(arxiv.RequestDelay)
+		}
+
+		params := arxivQueryParams(query)
+		u := fmt.Sprintf("%s?%s", apiURL, params.Encode())
+		log.Printf("Accessing %s\n", u)
+		result, err := arxiv.GetResults(u)
+		if err != nil {
+			log.Printf("Unable to plan %q: %v", query, err)
+			continue
+		}
+
+		e := arxiv.EstimateHarvest(result, opts)
+		total = total.Add(e)
+		writePlanRow(w, query, e)
+	}
+	writePlanRow(w, "TOTAL", total)
+	w.Flush()
+}
+
+func writePlanRow(w *tabwriter.Writer, query string, e arxiv.Estimate) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f MB\t%s\n",
+		query,
+		e.Results,
+		e.Pages,
+		e.Requests,
+		e.PDFs,
+		float64(e.Bytes)/(1<<20),
+		e.Duration.Round(time.Second),
+	)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/bundle"
+)
+
+// used for flags.
+var (
+	withSource    bool
+	withAncillary bool
+)
+
+// arxivPaperDirectory is where the source and ancillary files for a version of an arXiv paper are stored
+// e.g. papers/2101.00001v2/source and papers/2101.00001v2/anc
+func arxivPaperDirectory(id string, version int) string {
+	return strings.TrimSuffix(arxivPdfPath(id, version), ".pdf")
+}
+
+// downloadArxivBundles fetches the source and/or ancillary files for a paper
+// unpacks them into its paper directory and writes the directory's checksum manifest
+func downloadArxivBundles(id string, version int) {
+	if !withSource && !withAncillary {
+		return
+	}
+
+	// the manifest is written once the files are in place, so a directory with one is done
+	dir := arxivPaperDirectory(id, version)
+	bundled := fileExists(filepath.Join(dir, bundle.ManifestFile))
+	changed := false
+	if withSource && !(bundled && fileExists(filepath.Join(dir, "source"))) {
+		changed = true
+		if err := downloadArxivSource(id, version, filepath.Join(dir, "source")); err != nil {
+			log.Printf("Unable to download the source for %sv%d: %v", id, version, err)
+		}
+	}
+	if withAncillary && !(bundled && fileExists(filepath.Join(dir, "anc"))) {
+		changed = true
+		if err := downloadArxivAncillary(id, version, filepath.Join(dir, "anc")); err != nil {
+			log.Printf("Unable to download the ancillary files for %sv%d: %v", id, version, err)
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := bundle.WriteManifest(dir); err != nil {
+		log.Printf("Unable to write the manifest for %s: %v", dir, err)
+	}
+}
+
+func downloadArxivSource(id string, version int, dest string) error {
+	cacheDir, err := utils.MkTmpDir(filepath.Join("arxiv", id))
+	if err != nil {
+		return err
+	}
+	src := filepath.Join(cacheDir, fmt.Sprintf("e-print-v%d", version))
+
+	if !pdfHeld(src) {
+		url := arxiv.SourceURL(id, version)
+		log.Println("Pausing between requests. arXiv requests a three second delay between API requests...")
+		time.Sleep(3 * time.Second)
+		log.Println("Downloading", url)
+		if err := utils.DownloadFile(url, src, "*/*"); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%sv%d", strings.ReplaceAll(id, "/", "_"), version)
+	format, err := bundle.Unpack(src, dest, name)
+	if err != nil {
+		return err
+	}
+	log.Printf("Unpacked %s source into %s", format, dest)
+
+	return nil
+}
+
+func downloadArxivAncillary(id string, version int, dest string) error {
+	log.Println("Pausing between requests. arXiv requests a three second delay between API requests...")
+	time.Sleep(3 * time.Second)
+	files, err := arxiv.GetAncillaryFiles(fmt.Sprintf("https://arxiv.org/abs/%sv%d", id, version))
+	if err != nil {
+		return err
+	}
+
+	for _, url := range files {
+		f := filepath.Join(dest, filepath.FromSlash(arxiv.AncillaryPath(url)))
+		if pdfHeld(f) {
+			continue
+		}
+		time.Sleep(3 * time.Second)
+		log.Println("Downloading", url)
+		if err := utils.DownloadFile(url, f, "*/*"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
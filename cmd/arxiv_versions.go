@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+)
+
+const arxivPdfDirectory = "papers"
+
+// used for flags.
+var updateVersions bool
+
+// arxivPdfPath is where the PDF for a version of an arXiv paper is stored
+// e.g. papers/2101.00001v2.pdf or papers/hep-th_9901001v1.pdf
+func arxivPdfPath(id string, version int) string {
+	filename := fmt.Sprintf("%sv%d.pdf", strings.ReplaceAll(id, "/", "_"), version)
+	return filepath.Join(arxivPdfDirectory, filename)
+}
+
+// legacyArxivPdfPath is where PDFs used to be stored, named after the end of their URL
+// which leaves out the archive of old-style IDs e.g. papers/9901001v1.pdf for hep-th/9901001
+func legacyArxivPdfPath(id string, version int) string {
+	return filepath.Join(arxivPdfDirectory, fmt.Sprintf("%sv%d.pdf", path.Base(id), version))
+}
+
+// heldArxivPdf returns the PDF we have for a version of an arXiv paper, looking under its legacy name too
+// or arxivPdfPath if we don't have it yet
+func heldArxivPdf(id string, version int) string {
+	p := arxivPdfPath(id, version)
+	if legacy := legacyArxivPdfPath(id, version); !pdfHeld(p) && pdfHeld(legacy) {
+		return legacy
+	}
+
+	return p
+}
+
+// pdfHeld reports whether a PDF has been downloaded, ignoring empty ones left by failed downloads
+func pdfHeld(f string) bool {
+	info, err := os.Stat(f)
+	return err == nil && info.Size() > 0
+}
+
+// heldVersion returns the latest version of an arXiv paper we have a PDF for
+// or 0 if we don't have the paper
+func heldVersion(id string) int {
+	held := 0
+	for _, p := range []string{arxivPdfPath(id, 0), legacyArxivPdfPath(id, 0)} {
+		files, err := filepath.Glob(strings.TrimSuffix(p, "0.pdf") + "*.pdf")
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if !pdfHeld(f) {
+				continue
+			}
+			_, v := arxiv.SplitVersion(strings.TrimSuffix(filepath.Base(f), ".pdf"))
+			if v > held {
+				held = v
+			}
+		}
+	}
+
+	return held
+}
+
+var versionHeader = []string{
+	"id",
+	"held_version",
+	"new_version",
+	"submitted",
+	"file",
+}
+
+// updateArxivVersions downloads the versions of a paper newer than the one we hold
+// writing a row to the report for each of them and returning the outcome for the run summary
+func updateArxivVersions(wr *csv.Writer, id string) string {
+	held := heldVersion(id)
+	if held == 0 {
+		log.Println("Skipping", id, "as we do not have a PDF for it")
+		return progress.Skipped
+	}
+
+	url := fmt.Sprintf("https://export.arxiv.org/oai2?verb=GetRecord&identifier=oai:arXiv.org:%s&metadataPrefix=arXivRaw", id)
+	versions, err := arxiv.GetVersions(url)
+	if err != nil {
+		log.Printf("Unable to get versions for %s: %v", id, err)
+		return progress.Failed
+	}
+
+	outcome := progress.Unchanged
+
+	for _, v := range versions {
+		n := v.Number()
+		if n <= held {
+			continue
+		}
+
+		file := arxivPdfPath(id, n)
+		err := utils.DownloadPdf(fmt.Sprintf("https://arxiv.org/pdf/%s%s", id, v.Version), file)
+		if err != nil {
+			log.Printf("Unable to download %s%s: %v", id, v.Version, err)
+			outcome = progress.Failed
+			continue
+		}
+		if outcome == progress.Unchanged {
+			outcome = progress.Updated
+		}
+
+		submitted := v.Date
+		if t, err := v.Submitted(); err == nil {
+			submitted = t.Format("2006-01-02")
+		}
+		err = wr.Write([]string{
+			id,
+			"v" + strconv.Itoa(held),
+			v.Version,
+			submitted,
+			file,
+		})
+		if err != nil {
+			log.Fatalf("Unable to write to CSV: %v", err)
+		}
+		wr.Flush()
+	}
+
+	return outcome
+}
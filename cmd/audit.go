@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Re-check harvested DOIs for retractions",
+		Long: `Re-check the DOIs in a CSV for retractions, corrections and expressions of concern.
+
+Each DOI's metadata is fetched from Crossref again, ignoring the cache, and checked
+along with the Retraction Watch dataset if one is given. DOIs whose status differs
+from the CSV's retraction_status column are written to stdout as a CSV with the columns
+id,previous_status,retraction_status,retraction_notice
+
+The CSV can be one written by papercut get doi or any CSV with an id or doi column, or the column given with --column.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			url, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			all, err := cmd.Flags().GetBool("all")
+			if err != nil {
+				log.Fatal(err)
+			}
+			records := readCSVRecords(cmd)
+			retractions := loadRetractions()
+
+			wr := csv.NewWriter(os.Stdout)
+			err = wr.Write([]string{"id", "previous_status", "retraction_status", "retraction_notice"})
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+
+			checked, changed := 0, 0
+			for _, rec := range records {
+				d := csvDoi(rec)
+				if d == "" {
+					continue
+				}
+
+				// the cached metadata is what we harvested so fetch it again
+				crossrefLimiter.Wait()
+				a, err := doi.RefreshDoi(d, url)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				checked++
+
+				previous := rec.Get("retraction_status")
+				status, notices := retractionStatus(a, retractions)
+				if status != previous {
+					changed++
+				} else if !all {
+					continue
+				}
+				err = wr.Write([]string{d, previous, status, notices})
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+			}
+
+			log.Printf("Checked %d DOIs, %d have a new status", checked, changed)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	addCSVInputFlags(auditCmd, "CSV of DOIs to check, e.g. one written by papercut get doi")
+	auditCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
+	auditCmd.Flags().StringVar(&retractionWatchPath, "retraction-watch", "", "Retraction Watch dataset CSV to check for retractions, corrections and expressions of concern as well as Crossref")
+	auditCmd.Flags().Bool("all", false, "write every DOI, not just the ones whose status changed")
+}
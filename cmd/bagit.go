@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/bagit"
+	"github.com/lehigh-university-libraries/papercut/pkg/bundle"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bagitCmd = &cobra.Command{
+		Use:   "bagit",
+		Short: "Package articles as BagIt bags",
+		Long: `Package the articles in a papercut CSV as BagIt bags.
+
+Each bag holds the article's PDF, the metadata papercut cached from its source
+(e.g. doi.json or the arXiv OAI record), the article's row from the CSV as record.json,
+and any arXiv source or ancillary files. Payload and tag manifests use SHA-256 and SHA-512.
+
+One bag is made per article unless --batch is given.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			csvPath, err := cmd.Flags().GetString("csv")
+			if err != nil {
+				log.Fatal(err)
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				log.Fatal(err)
+			}
+			batch, err := cmd.Flags().GetString("batch")
+			if err != nil {
+				log.Fatal(err)
+			}
+			organization, err := cmd.Flags().GetString("organization")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if csvPath == "" {
+				log.Fatal("--csv is required")
+			}
+
+			f, err := os.Open(csvPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			records, err := record.ReadCSV(f)
+			f.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if batch != "" {
+				bag, err := bagit.New(filepath.Join(output, safeName(batch)))
+				if err != nil {
+					log.Fatal(err)
+				}
+				bag.AddInfo("Source-Organization", organization)
+				bag.AddInfo("External-Identifier", batch)
+				bag.AddInfo("Internal-Sender-Description", fmt.Sprintf("%d articles from %s", len(records), filepath.Base(csvPath)))
+				if err := bag.AddFile(csvPath, filepath.Base(csvPath)); err != nil {
+					discardBag(bag)
+					log.Fatal(err)
+				}
+				for _, rec := range records {
+					if err := addItem(bag, newItem(rec), safeName(rec.Get("id"))+"/"); err != nil {
+						discardBag(bag)
+						log.Fatalf("Unable to add %s to the bag: %v", rec.Get("id"), err)
+					}
+				}
+				if err := bag.Close(); err != nil {
+					discardBag(bag)
+					log.Fatal(err)
+				}
+				fmt.Println(bag.Path)
+				return
+			}
+
+			for _, rec := range records {
+				i := newItem(rec)
+				bag, err := bagit.New(filepath.Join(output, safeName(i.ID)))
+				if err != nil {
+					log.Printf("Skipping %s: %v", i.ID, err)
+					continue
+				}
+				bag.AddInfo("Source-Organization", organization)
+				bag.AddInfo("External-Identifier", i.ID)
+				bag.AddInfo("External-Description", rec.Get("title"))
+				if err := addItem(bag, i, ""); err != nil {
+					discardBag(bag)
+					log.Fatalf("Unable to add %s to the bag: %v", i.ID, err)
+				}
+				if err := bag.Close(); err != nil {
+					discardBag(bag)
+					log.Fatal(err)
+				}
+				fmt.Println(bag.Path)
+			}
+		},
+	}
+
+	bagitValidateCmd = &cobra.Command{
+		Use:   "validate BAG...",
+		Short: "Validate BagIt bags",
+		Long: `Validate BagIt bags.
+
+Every file in each bag is checked against the bag's manifests and tag manifests
+and the payload is compared with the Payload-Oxum in bag-info.txt.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			invalid := 0
+			for _, path := range args {
+				if err := bagit.Validate(path); err != nil {
+					invalid++
+					fmt.Printf("%s is invalid:\n%v\n", path, err)
+					continue
+				}
+				fmt.Printf("%s is valid\n", path)
+			}
+			if invalid > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// discardBag removes a bag that couldn't be finished so it can be made again
+func discardBag(bag *bagit.Bag) {
+	if err := bag.Discard(); err != nil {
+		log.Printf("Unable to remove the unfinished bag for %s: %v", bag.Path, err)
+	}
+}
+
+// addItem adds an article's files to a bag's payload under prefix
+func addItem(bag *bagit.Bag, i item, prefix string) error {
+	if i.PDF != "" {
+		if err := bag.AddFile(i.PDF, prefix+safeName(i.ID)+".pdf"); err != nil {
+			return err
+		}
+	} else {
+		log.Printf("No PDF found for %s", i.ID)
+	}
+
+	for _, f := range i.metadataFiles() {
+		if err := bag.AddFile(f, prefix+"metadata/"+filepath.Base(f)); err != nil {
+			return err
+		}
+	}
+
+	if i.BundleDir != "" {
+		if err := bag.AddDir(i.BundleDir, prefix+"bundle", bundle.ManifestFile); err != nil {
+			return err
+		}
+	}
+
+	r, err := json.MarshalIndent(i.Record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return bag.AddBytes(prefix+"record.json", r)
+}
+
+func init() {
+	packageCmd.AddCommand(bagitCmd)
+	bagitCmd.AddCommand(bagitValidateCmd)
+
+	bagitCmd.Flags().String("csv", "", "path to a CSV created by papercut search or papercut get")
+	bagitCmd.Flags().StringP("output", "o", "bags", "directory to create the bags in")
+	bagitCmd.Flags().String("batch", "", "put every article in a single bag with this name instead of one bag per article")
+	bagitCmd.Flags().String("organization", "", "Source-Organization to record in bag-info.txt")
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/lehigh-university-libraries/papercut/pkg/biorxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+	"github.com/spf13/cobra"
+)
+
+var (
+	biorxivCmd = &cobra.Command{
+		Use:   "biorxiv",
+		Short: "Search bioRxiv or medRxiv for preprints",
+		Long: `Search bioRxiv or medRxiv for the preprints posted between two dates.
+
+Each preprint is written once, at the latest version posted in the interval.
+Preprints that have been published in a journal have the journal version's DOI
+in the published_doi column and its journal, from Crossref, in field_related_item.
+
+Categories become subjects, which --crosswalk can map with the source biorxiv or medrxiv
+and the category e.g. neuroscience.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			server, err := cmd.Flags().GetString("server")
+			if err != nil {
+				log.Fatal(err)
+			}
+			from, err := cmd.Flags().GetString("from")
+			if err != nil {
+				log.Fatal(err)
+			}
+			to, err := cmd.Flags().GetString("to")
+			if err != nil {
+				log.Fatal(err)
+			}
+			doiURL, err := cmd.Flags().GetString("doi-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			server = strings.ToLower(server)
+			if server != biorxiv.Biorxiv && server != biorxiv.Medrxiv {
+				log.Fatalf("--server must be %s or %s", biorxiv.Biorxiv, biorxiv.Medrxiv)
+			}
+			if from == "" {
+				log.Fatal("--from is required")
+			}
+			if to == "" {
+				to = time.Now().Format("2006-01-02")
+			}
+			checkLayout()
+			format := getAbstractFormat()
+			crosswalk := loadCrosswalk()
+			defer writeUnmapped(crosswalk)
+
+			// versions of a preprint can be on different pages so gather the whole interval first
+			limiter := ratelimit.New(biorxiv.RequestDelay)
+			preprints := []biorxiv.Preprint{}
+			cursor := 0
+			for {
+				limiter.Wait()
+				log.Printf("Fetching %s preprints posted from %s to %s starting at %d\n", server, from, to, cursor)
+				r, err := biorxiv.GetDetails(server, from, to, cursor)
+				if err != nil {
+					log.Fatal(err)
+				}
+				preprints = append(preprints, r.Collection...)
+				next, ok := r.Next()
+				if !ok {
+					break
+				}
+				cursor = next
+			}
+			preprints = biorxiv.Latest(preprints)
+
+			tracker := newTracker("search biorxiv")
+			tracker.AddTotal(len(preprints))
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			header := biorxivHeader
+			err = wr.Write(header)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+			for _, p := range preprints {
+				cacheDir, err := utils.MkTmpDir(filepath.Join("dois", p.DOI))
+				if err != nil {
+					log.Fatal("Unable to write to tmp filesystem")
+				}
+				if details, err := json.Marshal(p); err == nil {
+					utils.WriteCachedFile(filepath.Join(cacheDir, server+".json"), string(details))
+				}
+
+				pdf := ""
+				if downloadPdfs {
+					pdf = filepath.Join(arxivPdfDirectory, fmt.Sprintf("%sv%d.pdf", safeName(p.DOI), p.VersionNumber()))
+					// the PDFs come from the same servers as the API
+					if !pdfHeld(pdf) {
+						limiter.Wait()
+					}
+					if err := utils.DownloadPdf(p.PDFURL(), pdf); err != nil {
+						if err := os.Remove(pdf); err != nil && !os.IsNotExist(err) {
+							log.Println("Error deleting file:", err)
+						}
+						pdf = ""
+					}
+				}
+
+				var published *doi.Article
+				if d := p.PublishedDOI(); d != "" {
+					a, err := doi.GetDoi(d, doiURL)
+					if err != nil {
+						log.Printf("Unable to get the journal version of %s: %v", p.DOI, err)
+					} else {
+						published = &a
+					}
+				}
+
+				row := biorxivRow(p, server, pdf, published, format, crosswalk)
+				err = wr.Write(storeLayout(header, row))
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+
+				if downloadPdfs {
+					tracker.PDF(pdf != "")
+				}
+				tracker.License(record.New(header, row).Get("field_rights") != "")
+				tracker.Done(server, progress.Harvested)
+			}
+		},
+	}
+)
+
+var biorxivHeader = []string{
+	"id",
+	"field_edtf_date_issued",
+	"title",
+	"field_full_title",
+	"field_abstract",
+	"field_linked_agent",
+	"field_affiliation",
+	"field_publisher",
+	"field_identifier",
+	"field_related_item",
+	"field_rights",
+	"rights_source",
+	"field_subject",
+	"field_subject_uri",
+	"file",
+	"preprint_server",
+	"preprint_version",
+	"preprint_category",
+	"published_doi",
+}
+
+// biorxivRow turns a preprint into a row of biorxivHeader
+// returning the PDF's URL in the file column if it wasn't downloaded
+// published is the Crossref record of the preprint's journal version, if it has one
+func biorxivRow(p biorxiv.Preprint, server, pdf string, published *doi.Article, format abstract.Format, crosswalk *subject.Crosswalk) []string {
+	linkedAgent := []string{}
+	for _, name := range p.AuthorNames() {
+		linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:person:%s", name))
+	}
+
+	relatedItem := ""
+	if published != nil && published.ContainerTitle != "" {
+		relatedItem = fmt.Sprintf(`{"title": "%s"}`, published.ContainerTitle)
+	}
+
+	rights := license.Rights{}
+	if uri := license.CreativeCommonsURI(p.License); uri != "" {
+		rights = license.Rights{URI: uri, Source: license.Biorxiv}
+	}
+
+	subjects := subject.Terms{}
+	if p.Category != "" {
+		subjects = subjects.Add(mapSubject(crosswalk, server, p.Category, p.Category)...)
+	}
+
+	publisher := "bioRxiv"
+	if server == biorxiv.Medrxiv {
+		publisher = "medRxiv"
+	}
+	if pdf == "" {
+		pdf = p.PDFURL()
+	}
+
+	fullTitle := ""
+	if len(p.Title) > 255 {
+		fullTitle = p.Title
+	}
+	return []string{
+		p.DOI,
+		p.Date,
+		utils.TrimToMaxLen(p.Title, 255),
+		fullTitle,
+		abstract.FromArxiv(p.Abstract, format),
+		strings.Join(linkedAgent, "|"),
+		p.AuthorCorrespondingInstitution,
+		publisher,
+		fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, p.DOI),
+		relatedItem,
+		rights.URI,
+		rights.Source,
+		subjects.Labels(),
+		subjects.URIs(),
+		pdf,
+		server,
+		fmt.Sprintf("v%d", p.VersionNumber()),
+		p.Category,
+		p.PublishedDOI(),
+	}
+}
+
+func init() {
+	searchCmd.AddCommand(biorxivCmd)
+
+	biorxivCmd.Flags().StringVarP(&biorxiv.URL, "url", "u", biorxiv.URL, "The bioRxiv API url")
+	biorxivCmd.Flags().String("server", biorxiv.Biorxiv, "the preprint server to search: biorxiv or medrxiv")
+	biorxivCmd.Flags().String("from", "", "find preprints posted on or after this date e.g. 2024-01-01")
+	biorxivCmd.Flags().String("to", "", "find preprints posted on or before this date e.g. 2024-01-31 (defaults to today)")
+	biorxivCmd.Flags().String("doi-url", "https://dx.doi.org", "The DOI API url to look up the journal versions of preprints with")
+	biorxivCmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the PDFs")
+	addAbstractFormatFlag(biorxivCmd)
+	biorxivCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping bioRxiv and medRxiv categories to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	biorxivCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	biorxivCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each preprint with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	biorxivCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	biorxivCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
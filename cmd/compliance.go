@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/compliance"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/lehigh-university-libraries/papercut/pkg/unpaywall"
+	"github.com/spf13/cobra"
+)
+
+var (
+	complianceCmd = &cobra.Command{
+		Use:   "compliance",
+		Short: "Check articles against funder public access policies",
+		Long: `Check the articles in a CSV against funder public access policies.
+
+Articles are matched to rules by the Crossref Funder IDs in their metadata
+and their publication date. Open access copies are found with Unpaywall
+(--unpaywall-email) and journal policies with Sherpa Romeo (SHERPA_ROMEO_API_KEY).
+
+Each article is reported once per rule that applies to it as compliant,
+action-needed or unknown, with the reason, when it's due and the compliant copy.
+The rules for NIH, NSF and the 2022 OSTP memo are built in; pass --rules to use your own.
+
+The CSV can be one written by papercut get doi or any CSV with an id or doi column, or the column given with --column.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			url, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			rulesPath, err := cmd.Flags().GetString("rules")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			rules, err := compliance.LoadRules(rulesPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			records := readCSVRecords(cmd)
+			if unpaywallEmail == "" {
+				log.Println("Without --unpaywall-email open access copies can't be found so articles will be reported as unknown")
+			}
+			if os.Getenv("SHERPA_ROMEO_API_KEY") == "" {
+				log.Println("Without SHERPA_ROMEO_API_KEY journal policies won't be included in the reasons")
+			}
+
+			wr := csv.NewWriter(os.Stdout)
+			err = wr.Write([]string{"id", "title", "authors", "rule", "status", "reason", "due", "copy"})
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+
+			statuses := map[string]int{}
+			now := time.Now()
+			for _, rec := range records {
+				d := csvDoi(rec)
+				if d == "" {
+					continue
+				}
+				a, err := doi.GetDoi(d, url)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+
+				results := rules.Check(a, complianceEvidence(a), now)
+				authors := []string{}
+				for _, author := range a.Authors {
+					authors = append(authors, fmt.Sprintf("%s, %s", author.Family, author.Given))
+				}
+				for _, r := range results {
+					statuses[r.Status]++
+					err = wr.Write([]string{d, a.Title, strings.Join(authors, "|"), r.Rule, r.Status, r.Reason, r.Due, r.Copy})
+					if err != nil {
+						log.Fatalf("Unable to write to CSV: %v", err)
+					}
+				}
+				wr.Flush()
+			}
+
+			log.Printf("%d compliant, %d action needed, %d unknown", statuses[compliance.Compliant], statuses[compliance.ActionNeeded], statuses[compliance.Unknown])
+		},
+	}
+)
+
+// complianceEvidence finds an article's open access copies and journal policy
+// copies are looked up again every time since they're what changes as articles are deposited
+func complianceEvidence(a doi.Article) compliance.Evidence {
+	e := compliance.Evidence{}
+	if unpaywallEmail != "" {
+		cached := filepath.Join(os.TempDir(), "dois", a.DOI, "unpaywall.json")
+		if err := os.Remove(cached); err != nil && !os.IsNotExist(err) {
+			log.Printf("Unable to clear the cached Unpaywall record for %s: %v", a.DOI, err)
+		}
+		r, err := unpaywall.GetDoi(a.DOI, unpaywallEmail)
+		if err != nil {
+			log.Println(err)
+		} else {
+			e.Copies = append([]unpaywall.Location{}, r.OALocations...)
+		}
+	}
+
+	if os.Getenv("SHERPA_ROMEO_API_KEY") != "" {
+		for _, issn := range a.ISSN {
+			p, err := romeo.GetIssnPublication(issn)
+			if err == nil {
+				e.Policy = p
+				break
+			}
+		}
+	}
+
+	return e
+}
+
+func init() {
+	rootCmd.AddCommand(complianceCmd)
+
+	addCSVInputFlags(complianceCmd, "CSV of DOIs to check, e.g. one written by papercut get doi")
+	complianceCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
+	complianceCmd.Flags().String("rules", "", "path to a JSON file of funder rules (defaults to the rules built into papercut)")
+	complianceCmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to find open access copies in Unpaywall with")
+	complianceCmd.Flags().StringVar(&romeo.RetrieveURL, "sherpa-url", romeo.RetrieveURL, "The Sherpa Romeo retrieve API url")
+}
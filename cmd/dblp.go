@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/pkg/dblp"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dblpCmd = &cobra.Command{
+		Use:   "dblp",
+		Short: "Search DBLP for articles",
+		Long: `Search DBLP for the publications of a person or the ones matching a query.
+
+Publications with a DOI are filled in from Crossref and arXiv preprints from arXiv,
+the same as get doi and search arxiv would, and anything else is written with what DBLP knows.
+The dblp_key column keeps each publication's DBLP key e.g. conf/sigir/SmithD20.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			person, err := cmd.Flags().GetString("person")
+			if err != nil {
+				log.Fatal(err)
+			}
+			doiURL, err := cmd.Flags().GetString("doi-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			arxivURL, err := cmd.Flags().GetString("arxiv-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			start, err := cmd.Flags().GetInt("start")
+			if err != nil {
+				log.Fatal(err)
+			}
+			results, err := cmd.Flags().GetInt("results")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if (person == "") == (query == "") {
+				log.Fatal("one of --person or --query is required")
+			}
+			if results < 1 || results > dblp.MaxHits {
+				log.Fatalf("--results must be between 1 and %d", dblp.MaxHits)
+			}
+			checkLayout()
+			r := newResolver(cmd, doiURL, arxivURL)
+			defer writeUnmapped(r.crosswalk)
+
+			limiter := ratelimit.New(dblp.RequestDelay)
+			publications := []dblp.Publication{}
+			if person != "" {
+				log.Printf("Fetching the publications of %s from DBLP\n", person)
+				name, p, err := dblp.GetPerson(person)
+				if err != nil {
+					log.Fatal(err)
+				}
+				log.Printf("Found %d publications by %s\n", len(p), name)
+				publications = p
+			} else {
+				first := start
+				for {
+					limiter.Wait()
+					log.Printf("Searching DBLP for %s starting at %d\n", query, first)
+					p, total, err := dblp.Search(query, first, results)
+					if err != nil {
+						log.Fatal(err)
+					}
+					publications = append(publications, p...)
+					first += len(p)
+					if len(p) == 0 || first >= total {
+						break
+					}
+				}
+			}
+
+			tracker := newTracker("search dblp")
+			tracker.AddTotal(len(publications))
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			header := dblpHeader
+			err = wr.Write(header)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+			for _, p := range publications {
+				row, source := r.resolve(dblpPaper(p), "dblp")
+				row = append(row, p.ArxivID(), p.Key)
+				err = wr.Write(storeLayout(header, row))
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+
+				rec := record.New(header, row)
+				if downloadPdfs {
+					tracker.PDF(rec.Get("file") != "" && fileExists(rec.Get("file")))
+				}
+				tracker.License(rec.Get("field_rights") != "")
+				tracker.Done(source, progress.Harvested)
+			}
+		},
+	}
+)
+
+// dblpHeader is doiHeader with the publication's arXiv ID and DBLP key
+var dblpHeader = append(append([]string{}, doiHeader...), "arxiv_id", "dblp_key")
+
+// dblpPaper is what DBLP knows about a publication
+func dblpPaper(p dblp.Publication) indexedPaper {
+	identifiers := []string{fmt.Sprintf(`{"attr0":"dblp","value":"%s"}`, p.Key)}
+	if p.DOI() != "" {
+		identifiers = append([]string{fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, p.DOI())}, identifiers...)
+	}
+	if p.ArxivID() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"arxiv","value":"%s"}`, p.ArxivID()))
+	}
+	id := p.DOI()
+	if id == "" {
+		id = p.Key
+	}
+
+	return indexedPaper{
+		ID:          id,
+		DOI:         p.DOI(),
+		ArxivID:     p.ArxivID(),
+		Date:        p.Year,
+		Title:       p.Title,
+		Authors:     p.Authors,
+		Venue:       p.Venue,
+		Volume:      p.Volume,
+		Issue:       p.Number,
+		Pages:       p.Pages,
+		Identifiers: identifiers,
+	}
+}
+
+func init() {
+	searchCmd.AddCommand(dblpCmd)
+
+	dblpCmd.Flags().StringVarP(&dblp.URL, "url", "u", dblp.URL, "The DBLP url")
+	dblpCmd.Flags().String("person", "", "DBLP person key to fetch the publications of e.g. 123/4567 or https://dblp.org/pid/123/4567")
+	dblpCmd.Flags().StringVarP(&query, "query", "q", "", "DBLP search query to perform")
+	dblpCmd.Flags().IntP("start", "s", 0, "The offset of the first search result")
+	dblpCmd.Flags().IntP("results", "r", 100, "The number of search results to fetch in a request")
+	dblpCmd.Flags().String("doi-url", "https://dx.doi.org", "The DOI API url to look up publications with DOIs in")
+	dblpCmd.Flags().String("arxiv-url", "https://export.arxiv.org/api/query", "The arXiv API url to look up preprints in")
+	dblpCmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+	dblpCmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the PDFs")
+	addAbstractFormatFlag(dblpCmd)
+	dblpCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping Crossref subjects and arXiv categories to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	dblpCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	dblpCmd.Flags().String("taxonomy", "", "path to an arXiv taxonomy JSON file created by \"papercut taxonomy refresh\" (defaults to the taxonomy built into papercut)")
+	dblpCmd.Flags().StringVar(&latexMode, "latex", "unicode", "how to convert LaTeX in arXiv titles, abstracts and names: unicode, tex (keep $...$ math), mathml or none")
+	dblpCmd.Flags().StringVar(&retractionWatchPath, "retraction-watch", "", "Retraction Watch dataset CSV to check for retractions, corrections and expressions of concern as well as Crossref")
+	dblpCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each publication with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	dblpCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	dblpCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
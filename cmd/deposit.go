@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// depositCmd represents the deposit command
+	depositCmd = &cobra.Command{
+		Use:   "deposit",
+		Short: "Deposit harvested articles in a repository.",
+		Long: `Deposit the PDFs of the articles in a papercut CSV in a repository.
+
+Articles are deposited when their PDF has been downloaded and they're under a Creative Commons license.
+Rows without a license in field_rights have it looked up the same way as papercut get license.
+Rows that already have a deposit_id are skipped so a CSV can be deposited again after a failure.
+deposit_state is draft, submitted or published, or failed when a deposit was created but couldn't be finished
+e.g. its PDF wasn't uploaded. Failed deposits keep their deposit_id so they aren't deposited twice;
+finish or delete them in the repository, and clear deposit_id to deposit the row again.
+
+A subcommand is required in order to choose the repository.`,
+	}
+)
+
+// depositColumns are the columns deposit adds to a CSV
+var depositColumns = []string{
+	"deposit_id",
+	"deposit_url",
+	"deposit_state",
+}
+
+// depositResult is what a repository returns for a deposit
+type depositResult struct {
+	ID  string
+	URL string
+	// State is draft, submitted or published
+	State string
+}
+
+// depositArticle is the metadata of a row in a papercut CSV that repositories need
+type depositArticle struct {
+	ID       string
+	Title    string
+	Abstract string
+	// Authors are Family, Given or the name as the source wrote it
+	Authors   []string
+	Date      string
+	DOI       string
+	Publisher string
+	Journal   string
+	Volume    string
+	Issue     string
+	Pages     string
+	Subjects  []string
+	// Rights is the license's URI
+	Rights string
+	PDF    string
+}
+
+// newDepositArticle reads the Islandora Workbench columns of a row back into an article
+func newDepositArticle(rec record.Record) depositArticle {
+	a := depositArticle{
+		ID:       rec.Get("id"),
+		Title:    rec.Get("field_full_title"),
+		Abstract: rec.Get("field_abstract"),
+		Date:     rec.Get("field_edtf_date_issued"),
+		Rights:   rec.Get("field_rights"),
+		PDF:      rec.Get("file"),
+	}
+	if a.Title == "" {
+		a.Title = rec.Get("title")
+	}
+	for _, agent := range splitValues(rec.Get("field_linked_agent")) {
+		if name, ok := strings.CutPrefix(agent, "relators:aut:person:"); ok {
+			a.Authors = append(a.Authors, name)
+		}
+	}
+	a.Subjects = splitValues(rec.Get("field_subject"))
+	for _, publisher := range splitValues(rec.Get("field_publisher")) {
+		a.Publisher = strings.TrimPrefix(publisher, "relators:pbl:corporate_body:")
+		break
+	}
+
+	for _, v := range splitValues(rec.Get("field_identifier")) {
+		var i struct {
+			Type  string `json:"attr0"`
+			Value string `json:"value"`
+		}
+		if json.Unmarshal([]byte(v), &i) == nil && i.Type == "doi" && a.DOI == "" {
+			a.DOI = i.Value
+		}
+	}
+	if d, err := doi.Normalize(a.ID); a.DOI == "" && err == nil {
+		a.DOI = d
+	}
+	for _, v := range splitValues(rec.Get("field_related_item")) {
+		var i struct {
+			Title string `json:"title"`
+		}
+		if json.Unmarshal([]byte(v), &i) == nil && i.Title != "" {
+			a.Journal = i.Title
+			break
+		}
+	}
+	for _, v := range splitValues(rec.Get("field_part_detail")) {
+		var p struct {
+			Type   string `json:"type"`
+			Number string `json:"number"`
+		}
+		if json.Unmarshal([]byte(v), &p) != nil {
+			continue
+		}
+		switch {
+		case p.Type == "volume" && a.Volume == "":
+			a.Volume = p.Number
+		case p.Type == "issue" && a.Issue == "":
+			a.Issue = p.Number
+		}
+	}
+	var extent struct {
+		Number string `json:"number"`
+	}
+	if json.Unmarshal([]byte(rec.Get("field_extent")), &extent) == nil {
+		a.Pages = extent.Number
+	}
+
+	return a
+}
+
+// splitValues splits a multi-valued Islandora Workbench column
+func splitValues(s string) []string {
+	values := []string{}
+	for _, v := range strings.Split(s, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// eligible reports whether an article can be deposited and if not why
+func (a depositArticle) eligible() (bool, string) {
+	if a.PDF == "" || !fileExists(a.PDF) {
+		return false, "its PDF hasn't been downloaded"
+	}
+	if !strings.Contains(a.Rights, "creativecommons.org/") {
+		return false, "it isn't under a Creative Commons license"
+	}
+
+	return true, ""
+}
+
+// runDeposit deposits the eligible articles in a CSV with deposit
+// writing the CSV to stdout with the IDs, URLs and states of the deposits added
+// deposit returns the deposit it created along with its error when it failed partway
+// so the row is recorded as failed instead of being deposited again
+func runDeposit(cmd *cobra.Command, deposit func(a depositArticle) (depositResult, error)) {
+	doiURL, err := cmd.Flags().GetString("doi-url")
+	if err != nil {
+		log.Fatal(err)
+	}
+	records := readCSVRecords(cmd)
+
+	wr := csv.NewWriter(os.Stdout)
+	deposited, skipped, failed := 0, 0, 0
+	for i, rec := range records {
+		for _, column := range depositColumns {
+			if !rec.Has(column) {
+				rec.Set(column, "")
+			}
+		}
+		a := newDepositArticle(rec)
+		if a.Rights == "" && a.DOI != "" && rec.Get("deposit_id") == "" {
+			if article, err := doi.GetDoi(a.DOI, doiURL); err == nil {
+				a.Rights = articleRights(article).URI
+			} else {
+				log.Println(err)
+			}
+		}
+
+		switch ok, reason := a.eligible(); {
+		case rec.Get("deposit_id") != "":
+			log.Printf("Skipping %s, which was deposited as %s %s", a.ID, rec.Get("deposit_id"), rec.Get("deposit_state"))
+			skipped++
+		case !ok:
+			log.Printf("Skipping %s because %s", a.ID, reason)
+			skipped++
+		default:
+			d, err := deposit(a)
+			if d.ID != "" {
+				rec.Set("deposit_id", d.ID)
+				rec.Set("deposit_url", d.URL)
+				rec.Set("deposit_state", d.State)
+			}
+			if err != nil {
+				if d.ID != "" {
+					rec.Set("deposit_state", "failed")
+				}
+				log.Printf("Unable to deposit %s: %v", a.ID, err)
+				failed++
+				break
+			}
+			log.Printf("Deposited %s as %s", a.ID, d.ID)
+			deposited++
+		}
+
+		if i == 0 {
+			err = wr.Write(rec.Columns)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+		}
+		err = wr.Write(rec.Row())
+		if err != nil {
+			log.Fatalf("Unable to write to CSV: %v", err)
+		}
+		wr.Flush()
+	}
+
+	log.Printf("%d deposited, %d skipped, %d failed", deposited, skipped, failed)
+}
+
+// addDepositFlags adds the flags every deposit subcommand has
+func addDepositFlags(cmd *cobra.Command) {
+	cmd.Flags().String("csv", "", "papercut CSV of the articles to deposit, e.g. one written by papercut get doi")
+	cmd.Flags().String("doi-url", "https://dx.doi.org", "The DOI API url to look up the licenses of rows without one in")
+	cmd.Flags().StringVar(&romeo.RetrieveURL, "sherpa-url", romeo.RetrieveURL, "The Sherpa Romeo retrieve API url")
+	cmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+}
+
+func init() {
+	rootCmd.AddCommand(depositCmd)
+}
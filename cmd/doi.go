@@ -1,16 +1,21 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/csv"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
 	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/retraction"
 	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
 	"github.com/spf13/cobra"
 )
 
@@ -18,136 +23,212 @@ var (
 	// used for flags.
 	filePath     string
 	downloadPdfs bool
+	funderIDs    []string
 	doiCmd       = &cobra.Command{
 		Use:   "doi",
 		Short: "Get DOI metadata and PDF",
 		Run: func(cmd *cobra.Command, args []string) {
-			file, err := os.Open(filePath)
+			// read every DOI first so we know how many there are
+			dois, err := readDois(filePath)
 			if err != nil {
-				fmt.Println("Error opening file:", err)
+				fmt.Println("Error reading DOIs:", err)
 				return
 			}
-			defer file.Close()
+			tracker := newTracker("get doi")
+			tracker.AddTotal(len(dois))
+			defer writeSummary(tracker)
 
-			// Create a scanner to read the file line by line
-			scanner := bufio.NewScanner(file)
 			url, err := cmd.Flags().GetString("url")
 			if err != nil {
 				log.Fatal(err)
 			}
+			format := getAbstractFormat()
+			checkLayout()
+			crosswalk := loadCrosswalk()
+			defer writeUnmapped(crosswalk)
+			retractions := loadRetractions()
 			wr := csv.NewWriter(os.Stdout)
 
+			header := doiHeader
 			// CSV header
-			err = wr.Write([]string{
-				"id",
-				"field_edtf_date_issued",
-				"title",
-				"field_full_title",
-				"field_abstract",
-				"field_model",
-				"field_linked_agent",
-				"field_identifier",
-				"field_part_detail",
-				"field_related_item",
-				"field_extent",
-				"field_language",
-				"field_rights",
-				"field_subject",
-				"file",
-			})
+			err = wr.Write(header)
 			if err != nil {
 				log.Fatalf("Unable to write to CSV: %v", err)
 			}
-			for scanner.Scan() {
-				var doiObject doi.Article
-				doiStr := strings.TrimSpace(scanner.Text())
+			for _, doiStr := range dois {
 				doiObject, err := doi.GetDoi(doiStr, url)
 				if err != nil {
 					log.Println(err)
+					tracker.Done("crossref", progress.Failed)
 					continue
 				}
-
-				var linkedAgent []string
-				for _, author := range doiObject.Authors {
-					linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:person:%s, %s", author.Family, author.Given))
-				}
-				var publisher []string
-				if doiObject.Publisher != "" {
-					publisher = append(publisher, fmt.Sprintf("relators:pbl:corporate_body:%s", doiObject.Publisher))
-				}
-				identifiers := []string{
-					fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, doiObject.DOI),
-				}
-				fieldRights := ""
-				for _, i := range doiObject.ISSN {
-					identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"issn","value":"%s"}`, i))
-					if fieldRights == "" {
-						fieldRights = romeo.FindIssnLicense(i)
-					}
-				}
-
-				partDetail := []string{}
-				if doiObject.Volume != "" {
-					partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, doiObject.Volume))
-				}
-				if doiObject.Issue != "" {
-					partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, doiObject.Issue))
-				}
-
-				relatedItem := []string{}
-				if doiObject.ContainerTitle != "" {
-					relatedItem = append(relatedItem, fmt.Sprintf(`{"title": "%s"}`, doiObject.ContainerTitle))
-				}
-				extent := ""
-				if doiObject.Page != "" {
-					extent = fmt.Sprintf(`{"attr0": "page", "number": "%s"}`, doiObject.Page)
+				if len(funderIDs) > 0 && !doiObject.FundedBy(funderIDs...) {
+					log.Printf("Skipping %s, which isn't funded by %s", doiStr, strings.Join(funderIDs, " or "))
+					tracker.Done("crossref", progress.Skipped)
+					continue
 				}
 
 				pdf := ""
 				if downloadPdfs {
 					pdf = doiObject.DownloadPdf()
 				}
-
-				fullTitle := ""
-				if len(doiObject.Title) > 255 {
-					fullTitle = doiObject.Title
+				cacheDir, err := utils.MkTmpDir(filepath.Join("dois", doiStr))
+				if err != nil {
+					log.Fatal("Unable to write to tmp filesystem")
+				}
+				if doc := parsePdf(pdf, cacheDir); doc != nil {
+					if doiObject.Abstract == "" {
+						doiObject.Abstract = doc.Abstract
+					}
+					for i, author := range doiObject.Authors {
+						if len(author.Affiliation) > 0 {
+							continue
+						}
+						for _, name := range doc.AffiliationsFor(author.Family) {
+							doiObject.Authors[i].Affiliation = append(doiObject.Authors[i].Affiliation, doi.Affiliation{Name: name})
+						}
+					}
 				}
-				err = wr.Write([]string{
-					doiStr,
-					doi.JoinDate(doiObject.Issued),
-					utils.TrimToMaxLen(doiObject.Title, 255),
-					fullTitle,
-					doiObject.Abstract,
-					"Digital Document",
-					strings.Join(linkedAgent, "|"),
-					strings.Join(publisher, "|"),
-					strings.Join(identifiers, "|"),
-					strings.Join(partDetail, "|"),
-					strings.Join(relatedItem, "|"),
-					extent,
-					doiObject.Language,
-					fieldRights,
-					strings.Join(doiObject.Subject, "|"),
-					pdf,
-				})
+
+				row := doiRow(doiStr, doiObject, pdf, format, crosswalk, retractions)
+				err = wr.Write(storeLayout(header, row))
 				if err != nil {
 					log.Fatalf("Unable to write to CSV: %v", err)
 				}
 				wr.Flush()
-			}
 
-			if err := scanner.Err(); err != nil {
-				fmt.Println("Error scanning file:", err)
-				return
+				if downloadPdfs {
+					tracker.PDF(pdf != "" && fileExists(pdf))
+				}
+				tracker.License(record.New(header, row).Get("field_rights") != "")
+				tracker.Done("crossref", progress.Harvested)
 			}
 		},
 	}
 )
 
+var doiHeader = []string{
+	"id",
+	"field_edtf_date_issued",
+	"title",
+	"field_full_title",
+	"field_abstract",
+	"field_model",
+	"field_linked_agent",
+	"field_affiliation",
+	"field_publisher",
+	"field_identifier",
+	"field_part_detail",
+	"field_related_item",
+	"field_extent",
+	"field_language",
+	"field_rights",
+	"rights_source",
+	"field_subject",
+	"field_subject_uri",
+	"field_funder",
+	"funder_id",
+	"award_number",
+	"clinical_trial_number",
+	"retraction_status",
+	"retraction_notice",
+	"file",
+}
+
+// doiRow turns an article into a row of doiHeader
+func doiRow(id string, a doi.Article, pdf string, format abstract.Format, crosswalk *subject.Crosswalk, retractions retraction.Dataset) []string {
+	var linkedAgent []string
+	for _, author := range a.Authors {
+		linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:person:%s, %s", author.Family, author.Given))
+	}
+	var publisher []string
+	if a.Publisher != "" {
+		publisher = append(publisher, fmt.Sprintf("relators:pbl:corporate_body:%s", a.Publisher))
+	}
+	identifiers := []string{
+		fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, a.DOI),
+	}
+	for _, i := range a.ISSN {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"issn","value":"%s"}`, i))
+	}
+	for _, i := range a.ISBN {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"isbn","value":"%s"}`, i))
+	}
+	rights := articleRights(a)
+
+	partDetail := []string{}
+	if a.Volume != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, a.Volume))
+	}
+	if a.Issue != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, a.Issue))
+	}
+
+	relatedItem := []string{}
+	if a.ContainerTitle != "" {
+		relatedItem = append(relatedItem, fmt.Sprintf(`{"title": "%s"}`, a.ContainerTitle))
+	}
+	extent := ""
+	if a.Page != "" {
+		extent = fmt.Sprintf(`{"attr0": "page", "number": "%s"}`, a.Page)
+	}
+
+	subjects := subject.Terms{}
+	for _, s := range a.Subject {
+		subjects = subjects.Add(mapSubject(crosswalk, "crossref", s, s)...)
+	}
+
+	funders, funderRegistryIDs, awards := a.Funding()
+	status, notices := retractionStatus(a, retractions)
+
+	fullTitle := ""
+	if len(a.Title) > 255 {
+		fullTitle = a.Title
+	}
+	return []string{
+		id,
+		doi.JoinDate(a.Issued),
+		utils.TrimToMaxLen(a.Title, 255),
+		fullTitle,
+		abstract.FromJATS(a.Abstract, format),
+		"Digital Document",
+		strings.Join(linkedAgent, "|"),
+		strings.Join(a.Affiliations(), "|"),
+		strings.Join(publisher, "|"),
+		strings.Join(identifiers, "|"),
+		strings.Join(partDetail, "|"),
+		strings.Join(relatedItem, "|"),
+		extent,
+		a.Language,
+		rights.URI,
+		rights.Source,
+		subjects.Labels(),
+		subjects.URIs(),
+		strings.Join(funders, "|"),
+		strings.Join(funderRegistryIDs, "|"),
+		strings.Join(awards, "|"),
+		strings.Join(a.ClinicalTrialNumbers(), "|"),
+		status,
+		notices,
+		pdf,
+	}
+}
+
 func init() {
 	getCmd.AddCommand(doiCmd)
 
 	doiCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
-	doiCmd.Flags().StringVarP(&filePath, "file", "f", "", "path to file containing one DOI per line")
+	addDoiInputFlags(doiCmd, &filePath)
+	doiCmd.Flags().StringVar(&romeo.RetrieveURL, "sherpa-url", romeo.RetrieveURL, "The Sherpa Romeo retrieve API url")
+	doiCmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+	doiCmd.Flags().StringSliceVar(&funderIDs, "funder", nil, "only harvest articles funded by this Crossref Funder ID e.g. 10.13039/100000001 for NSF (can be repeated)")
 	doiCmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the PDFs")
+	addAbstractFormatFlag(doiCmd)
+	doiCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping Crossref subjects to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	doiCmd.Flags().StringVar(&retractionWatchPath, "retraction-watch", "", "Retraction Watch dataset CSV to check for retractions, corrections and expressions of concern as well as Crossref")
+	doiCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	doiCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each article with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	doiCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	doiCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+	doiCmd.Flags().StringVar(&grobidURL, "grobid-url", "", "URL of a GROBID server to parse downloaded PDFs with (e.g. http://localhost:8070)")
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// used for flags.
+	doiInputFormat string
+	doiInputColumn string
+	csvDoiColumn   string
+)
+
+// readDois reads the DOIs to look up from a file, or stdin when the path is - or empty
+// DOIs are normalized and de-duplicated, and entries without one are logged
+func readDois(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path == "" {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return nil, fmt.Errorf("--file is required unless DOIs are piped to stdin")
+		}
+	} else if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	format := doi.FormatOf(path)
+	if doiInputFormat != "" {
+		var err error
+		format, err = doi.ParseFormat(doiInputFormat)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dois, skipped, err := doi.Read(r, format, doiInputColumn)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range skipped {
+		log.Printf("Skipping %s, which doesn't have a DOI", s)
+	}
+
+	return dois, nil
+}
+
+// addDoiInputFlags adds the flags readDois uses, with --file bound to path
+func addDoiInputFlags(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVarP(path, "file", "f", "", "path to a file of DOIs: one DOI, DOI URL or citation per line, a CSV, or a BibTeX or RIS export (- or empty to read stdin)")
+	cmd.Flags().StringVar(&doiInputFormat, "format", "", "format of --file: lines, csv, bibtex or ris (defaults to the file's extension, or lines)")
+	cmd.Flags().StringVar(&doiInputColumn, "column", "", "the column of a CSV --file that holds the DOIs (defaults to id, or doi for rows whose id isn't a DOI)")
+}
+
+// readCSVRecords reads the rows of the CSV a command was given with --csv, which can't be empty
+func readCSVRecords(cmd *cobra.Command) []record.Record {
+	csvPath, err := cmd.Flags().GetString("csv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if csvPath == "" {
+		log.Fatal("--csv is required")
+	}
+
+	f, err := os.Open(csvPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	records, err := record.ReadCSV(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(records) == 0 {
+		log.Fatalf("%s has no rows", csvPath)
+	}
+
+	return records
+}
+
+// csvDoi returns the normalized DOI in a row of a --csv, or "" if it doesn't have one
+// the DOI is read from --column, or when it isn't given the id column
+// falling back to the doi column for rows whose id isn't a DOI e.g. an arXiv ID
+func csvDoi(rec record.Record) string {
+	if csvDoiColumn != "" {
+		d, _ := doi.Normalize(rec.Get(csvDoiColumn))
+		return d
+	}
+	for _, column := range []string{"id", "doi"} {
+		if d, err := doi.Normalize(rec.Get(column)); err == nil {
+			return d
+		}
+	}
+
+	return ""
+}
+
+// addCSVInputFlags adds the flags readCSVRecords and csvDoi use
+func addCSVInputFlags(cmd *cobra.Command, usage string) {
+	cmd.Flags().String("csv", "", usage)
+	cmd.Flags().StringVar(&csvDoiColumn, "column", "", "the column of --csv that holds the DOIs, as a DOI, DOI URL or doi: URI (defaults to id, or doi for rows whose id isn't a DOI)")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/openalex"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// enrichCmd represents the enrich command
+	enrichCmd = &cobra.Command{
+		Use:   "enrich",
+		Short: "Add metadata from other sources to a papercut CSV.",
+		Long: `Add metadata from other sources to a papercut CSV.
+
+A subcommand is required in order to enrich the CSV from a specific source.`,
+	}
+
+	enrichOpenalexCmd = &cobra.Command{
+		Use:   "openalex",
+		Short: "Add OpenAlex topics, OA status, citation counts and institutions to a CSV",
+		Long: `Add OpenAlex topics, open access status, cited by counts and institutions to the DOIs in a CSV.
+
+The CSV is written to stdout with these columns added, or updated if it already has them
+openalex_id,openalex_topics,oa_status,cited_by_count,institution,institution_ror
+
+institution and institution_ror list the authors' institutions and their ROR IDs in the same order.
+Rows without a DOI, or whose DOI OpenAlex doesn't have, are written with the columns empty.
+
+The CSV can be one written by papercut get doi or any CSV with an id or doi column, or the column given with --column.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			records := readCSVRecords(cmd)
+
+			wr := csv.NewWriter(os.Stdout)
+			found := 0
+			for i, rec := range records {
+				values := make([]string, len(openalexColumns))
+				if w := enrichOpenalex(rec); w != nil {
+					values = openalexValues(*w)
+					found++
+				}
+				for j, column := range openalexColumns {
+					rec.Set(column, values[j])
+				}
+
+				if i == 0 {
+					err := wr.Write(rec.Columns)
+					if err != nil {
+						log.Fatalf("Unable to write to CSV: %v", err)
+					}
+				}
+				err := wr.Write(rec.Row())
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+			}
+
+			log.Printf("Found %d of %d rows in OpenAlex", found, len(records))
+		},
+	}
+)
+
+// enrichOpenalex looks up the DOI of a row in OpenAlex
+// cited by counts and OA status change so works are fetched again rather than read from the cache
+func enrichOpenalex(rec record.Record) *openalex.Work {
+	d := csvDoi(rec)
+	if d == "" {
+		return nil
+	}
+
+	cached := filepath.Join(os.TempDir(), "dois", d, "openalex.json")
+	if err := os.Remove(cached); err != nil && !os.IsNotExist(err) {
+		log.Printf("Unable to clear the cached OpenAlex record for %s: %v", d, err)
+	}
+	openalexLimiter.Wait()
+	w, err := openalex.GetDoi(d, utils.Mailto)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
+	return w
+}
+
+func init() {
+	rootCmd.AddCommand(enrichCmd)
+	enrichCmd.AddCommand(enrichOpenalexCmd)
+
+	addCSVInputFlags(enrichOpenalexCmd, "CSV of DOIs to enrich, e.g. one written by papercut get doi")
+	enrichOpenalexCmd.Flags().StringVarP(&openalex.URL, "url", "u", openalex.URL, "The OpenAlex API url")
+	enrichOpenalexCmd.Flags().StringVar(&utils.Mailto, "mailto", "", "email address to send with requests to use OpenAlex's polite pool")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// used for flags.
+	graphFilePath string
+	graphOutput   string
+	graphFormats  []string
+	graphCmd      = &cobra.Command{
+		Use:   "graph",
+		Short: "Build a citation graph for a list of DOIs",
+		Long: `Build a citation graph for a list of DOIs.
+
+Each DOI is linked to the DOIs in its Crossref reference list
+and to the works Crossref (or OpenCitations, if --citations-url is set) lists as citing it.
+
+The graph is written as a CSV edge list (plus a CSV of nodes), GraphML and/or JSON.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dois, err := readDois(graphFilePath)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			url, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			citationsURL, err := cmd.Flags().GetString("citations-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			g := graph.New()
+			for _, doiStr := range dois {
+				doiObject, err := doi.GetDoi(doiStr, url)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
+				if doiObject.DOI == "" {
+					doiObject.DOI = doiStr
+				}
+
+				g.AddNode(doiObject.DOI, doiObject.Title, graph.Faculty)
+				for _, cited := range doiObject.CitedDois() {
+					g.AddCitation(doiObject.DOI, cited)
+				}
+				for _, cited := range doiObject.RelatedDois("cites") {
+					g.AddCitation(doiObject.DOI, cited)
+				}
+				for _, citing := range doiObject.RelatedDois("is-cited-by") {
+					g.AddCitation(citing, doiObject.DOI)
+				}
+				if citationsURL != "" {
+					citing, err := doi.GetCitations(doiObject.DOI, citationsURL)
+					if err != nil {
+						log.Println(err)
+					}
+					for _, c := range citing {
+						g.AddCitation(c, doiObject.DOI)
+					}
+				}
+			}
+
+			for _, format := range graphFormats {
+				switch format {
+				case "csv":
+					writeGraph(graphOutput+"-edges.csv", g.WriteEdges)
+					writeGraph(graphOutput+"-nodes.csv", g.WriteNodes)
+				case "graphml":
+					writeGraph(graphOutput+".graphml", g.WriteGraphML)
+				case "json":
+					writeGraph(graphOutput+".json", g.WriteJSON)
+				default:
+					log.Fatalf("Unknown graph format %q", format)
+				}
+			}
+			log.Printf("Wrote %d nodes and %d edges\n", len(g.Nodes), len(g.Edges))
+		},
+	}
+)
+
+func writeGraph(path string, write func(io.Writer) error) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Unable to create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	err = write(f)
+	if err != nil {
+		log.Fatalf("Unable to write %s: %v", path, err)
+	}
+	log.Println("Wrote", path)
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
+	graphCmd.Flags().String("citations-url", "", "An OpenCitations COCI citations API url to find citing works with (e.g. https://opencitations.net/index/coci/api/v1/citations)")
+	graphCmd.Flags().StringVarP(&graphFilePath, "file", "f", "", "path to a file of DOIs: one DOI, DOI URL or citation per line, a CSV with an id or doi column, or a BibTeX or RIS export (- or empty to read stdin)")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "citations", "path prefix for the files the graph is written to")
+	graphCmd.Flags().StringSliceVar(&graphFormats, "format", []string{"csv", "graphml", "json"}, "comma separated list of formats to write (csv, graphml, json)")
+}
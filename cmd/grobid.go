@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/grobid"
+)
+
+// used for flags.
+var grobidURL string
+
+// parsePdf sends a downloaded PDF to GROBID and exports the bibliography it found
+// to a references.json file next to the PDF.
+// nil is returned if no GROBID server was configured or the PDF could not be parsed
+func parsePdf(pdf, cacheDir string) *grobid.Document {
+	if grobidURL == "" || !strings.HasSuffix(pdf, ".pdf") {
+		return nil
+	}
+	// if the download failed the PDF is a URL instead of a local file
+	if _, err := os.Stat(pdf); err != nil {
+		return nil
+	}
+
+	doc, err := grobid.ProcessPdf(grobidURL, pdf, filepath.Join(cacheDir, "grobid.tei.xml"))
+	if err != nil {
+		log.Printf("Unable to parse %s with GROBID: %v", pdf, err)
+		return nil
+	}
+
+	references, err := json.MarshalIndent(doc.References, "", "  ")
+	if err != nil {
+		log.Printf("Unable to encode references for %s: %v", pdf, err)
+		return &doc
+	}
+	referencesFile := strings.TrimSuffix(pdf, ".pdf") + ".references.json"
+	err = os.WriteFile(referencesFile, references, 0644)
+	if err != nil {
+		log.Printf("Unable to write %s: %v", referencesFile, err)
+	}
+
+	return &doc
+}
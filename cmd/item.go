@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/openalex"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+)
+
+// item is an article from a papercut CSV along with the files papercut saved for it
+type item struct {
+	// Source is where the article was harvested from: arxiv, pubmed, openalex, biorxiv, medrxiv, dblp, s2 or doi
+	Source string
+	ID     string
+	Record record.Record
+	// PDF is the local path of the article's PDF, if we downloaded it
+	PDF string
+	// CacheDir holds the upstream responses cached in the tmp directory
+	// e.g. doi.json or oai.xml
+	CacheDir string
+	// BundleDir holds the arXiv source and ancillary files or the PMC OA package, if they were downloaded
+	BundleDir string
+}
+
+func newItem(rec record.Record) item {
+	i := item{
+		Source: "doi",
+		ID:     rec.Get("id"),
+		Record: rec,
+	}
+	if rec.Has("arXiv version") {
+		i.Source = "arxiv"
+	} else if rec.Has("pmcid") {
+		i.Source = "pubmed"
+	} else if rec.Has("openalex_filter") {
+		i.Source = "openalex"
+	} else if rec.Has("preprint_server") {
+		// bioRxiv and medRxiv preprints have DOIs so they're cached like other DOIs
+		i.Source = rec.Get("preprint_server")
+	} else if rec.Has("dblp_key") {
+		i.Source = "dblp"
+	} else if rec.Has("s2_paper_id") {
+		i.Source = "s2"
+	}
+
+	if f := rec.Get("file"); f != "" && fileExists(f) {
+		i.PDF = f
+	}
+
+	switch i.Source {
+	case "arxiv":
+		version, _ := strconv.Atoi(strings.TrimPrefix(rec.Get("arXiv version"), "v"))
+		if version == 0 {
+			version = 1
+		}
+		if i.PDF == "" && pdfHeld(heldArxivPdf(i.ID, version)) {
+			i.PDF = heldArxivPdf(i.ID, version)
+		}
+		i.CacheDir = filepath.Join(os.TempDir(), "arxiv", i.ID)
+		if dir := arxivPaperDirectory(i.ID, version); fileExists(dir) {
+			i.BundleDir = dir
+		}
+	case "pubmed":
+		i.CacheDir = filepath.Join(os.TempDir(), "pubmed", i.ID)
+		if pmcid := rec.Get("pmcid"); pmcid != "" && fileExists(pmcPath(pmcid, "")) {
+			i.BundleDir = pmcPath(pmcid, "")
+		}
+	case "openalex":
+		i.CacheDir = filepath.Join(os.TempDir(), "openalex", openalex.ShortID(rec.Get("openalex_id")))
+	case "dblp", "s2":
+		// papers from DBLP and Semantic Scholar are cached like the DOI or arXiv paper they resolved to
+		i.CacheDir = filepath.Join(os.TempDir(), "dois", i.ID)
+		if i.ID != "" && i.ID == rec.Get("arxiv_id") {
+			i.CacheDir = filepath.Join(os.TempDir(), "arxiv", i.ID)
+		}
+	default:
+		i.CacheDir = filepath.Join(os.TempDir(), "dois", i.ID)
+	}
+
+	return i
+}
+
+// metadataFiles lists the cached upstream responses for the item
+func (i item) metadataFiles() []string {
+	entries, err := os.ReadDir(i.CacheDir)
+	if err != nil {
+		return nil
+	}
+
+	files := []string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			files = append(files, filepath.Join(i.CacheDir, e.Name()))
+		}
+	}
+
+	return files
+}
+
+var unsafeFilename = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// safeName turns an identifier like a DOI into something usable as a file or directory name
+// e.g. 10.1000/xyz:123 becomes 10.1000_xyz_123
+func safeName(id string) string {
+	return strings.Trim(unsafeFilename.ReplaceAllString(id, "_"), "_.")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+)
+
+// used for flags.
+var layoutTemplate string
+
+var layoutPlaceholder = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// layoutPlaceholders are the values that can be used in a --layout template
+var layoutPlaceholders = map[string]func(i item) string{
+	"source": func(i item) string { return i.Source },
+	"id":     func(i item) string { return safeName(i.ID) },
+	"year": func(i item) string {
+		date := i.Record.Get("field_edtf_date_issued")
+		if len(date) < 4 {
+			return "undated"
+		}
+		return date[:4]
+	},
+}
+
+// checkLayout makes sure every placeholder in the --layout template is one we know
+func checkLayout() {
+	for _, m := range layoutPlaceholder.FindAllStringSubmatch(layoutTemplate, -1) {
+		if _, ok := layoutPlaceholders[m[1]]; !ok {
+			log.Fatalf("unknown placeholder %s in --layout (expected {source}, {year} or {id})", m[0])
+		}
+	}
+}
+
+// layoutPath expands the --layout template for an item
+// e.g. {source}/{year}/{id} becomes doi/2024/10.1000_xyz123
+func layoutPath(i item) string {
+	path := layoutPlaceholder.ReplaceAllStringFunc(layoutTemplate, func(p string) string {
+		return layoutPlaceholders[strings.Trim(p, "{}")](i)
+	})
+
+	return filepath.Clean(path)
+}
+
+// storeLayout copies an article's PDF, cached upstream responses and any arXiv source files
+// into the directory the --layout template gives it along with a metadata.json of its row.
+// The row is returned with the file column pointing at the stored PDF.
+func storeLayout(header, row []string) []string {
+	if layoutTemplate == "" {
+		return row
+	}
+
+	rec := record.New(header, row)
+	i := newItem(rec)
+	dir := layoutPath(i)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Printf("Unable to create %s: %v", dir, err)
+		return row
+	}
+
+	if i.PDF != "" {
+		pdf := filepath.Join(dir, safeName(i.ID)+".pdf")
+		if err := utils.CopyFile(i.PDF, pdf); err != nil {
+			log.Printf("Unable to copy %s to %s: %v", i.PDF, pdf, err)
+		} else {
+			rec.Set("file", pdf)
+		}
+	}
+
+	for _, f := range i.metadataFiles() {
+		if err := utils.CopyFile(f, filepath.Join(dir, filepath.Base(f))); err != nil {
+			log.Printf("Unable to copy %s to %s: %v", f, dir, err)
+		}
+	}
+
+	if i.BundleDir != "" {
+		err := filepath.WalkDir(i.BundleDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(i.BundleDir, path)
+			if err != nil {
+				return err
+			}
+			return utils.CopyFile(path, filepath.Join(dir, rel))
+		})
+		if err != nil {
+			log.Printf("Unable to copy %s to %s: %v", i.BundleDir, dir, err)
+		}
+	}
+
+	metadata, err := json.MarshalIndent(rec, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, "metadata.json"), metadata, 0644)
+	}
+	if err != nil {
+		log.Printf("Unable to write %s: %v", filepath.Join(dir, "metadata.json"), err)
+	}
+
+	return rec.Row()
+}
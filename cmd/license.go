@@ -1,12 +1,10 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/csv"
 	"fmt"
 	"log"
 	"os"
-	"strings"
 
 	"github.com/lehigh-university-libraries/papercut/pkg/doi"
 	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
@@ -20,15 +18,11 @@ var (
 		Use:   "license",
 		Short: "Get license for a DOI",
 		Run: func(cmd *cobra.Command, args []string) {
-			file, err := os.Open(licenseFilePath)
+			dois, err := readDois(licenseFilePath)
 			if err != nil {
-				fmt.Println("Error opening file:", err)
+				fmt.Println("Error reading DOIs:", err)
 				return
 			}
-			defer file.Close()
-
-			// Create a scanner to read the file line by line
-			scanner := bufio.NewScanner(file)
 			url, err := cmd.Flags().GetString("url")
 			if err != nil {
 				log.Fatal(err)
@@ -39,40 +33,29 @@ var (
 			err = wr.Write([]string{
 				"id",
 				"field_rights",
+				"rights_source",
 			})
 			if err != nil {
 				log.Fatalf("Unable to write to CSV: %v", err)
 			}
-			for scanner.Scan() {
-				doiStr := strings.TrimSpace(scanner.Text())
+			for _, doiStr := range dois {
 				doiObject, err := doi.GetDoi(doiStr, url)
 				if err != nil {
 					log.Println(err)
 					continue
 				}
 
-				fieldRights := ""
-				for _, i := range doiObject.ISSN {
-					fieldRights = romeo.FindIssnLicense(i)
-					if fieldRights != "" {
-						break
-					}
-				}
-
+				rights := articleRights(doiObject)
 				err = wr.Write([]string{
 					doiStr,
-					fieldRights,
+					rights.URI,
+					rights.Source,
 				})
 				if err != nil {
 					log.Fatalf("Unable to write to CSV: %v", err)
 				}
 				wr.Flush()
 			}
-
-			if err := scanner.Err(); err != nil {
-				fmt.Println("Error scanning file:", err)
-				return
-			}
 		},
 	}
 )
@@ -81,5 +64,7 @@ func init() {
 	getCmd.AddCommand(licenseCmd)
 
 	licenseCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
-	licenseCmd.Flags().StringVarP(&licenseFilePath, "file", "f", "", "path to file containing one DOI per line")
+	licenseCmd.Flags().StringVar(&romeo.RetrieveURL, "sherpa-url", romeo.RetrieveURL, "The Sherpa Romeo retrieve API url")
+	licenseCmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+	addDoiInputFlags(licenseCmd, &licenseFilePath)
 }
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+	"github.com/lehigh-university-libraries/papercut/pkg/openalex"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// used for flags.
+	openalexFilter openalex.Filter
+
+	// openalexLimiter keeps us under OpenAlex's limit of ten requests a second
+	openalexLimiter = ratelimit.New(100 * time.Millisecond)
+
+	openalexCmd = &cobra.Command{
+		Use:   "openalex",
+		Short: "Search OpenAlex for articles",
+		Long: `Search OpenAlex for works by institution, author, concept, topic and publication date.
+
+Filters of the same kind are ORed together and different kinds are ANDed, e.g.
+--institution 012afjb06 --from 2024-01-01 finds everything published since 2024 by authors at that institution.
+Authors can be OpenAlex author IDs or ORCID iDs, but not both in one search.
+Results are paged through with OpenAlex's cursor so there's no limit to how many can be harvested.
+
+OpenAlex topics become subjects, which --crosswalk can map with the source openalex
+and the topic's ID e.g. T10102. Pass --mailto to use OpenAlex's polite pool.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			filter, err := openalexFilter.Encode()
+			if err != nil {
+				log.Fatal(err)
+			}
+			results, err := cmd.Flags().GetInt("results")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if results < 1 || results > openalex.MaxPerPage {
+				log.Fatalf("--results must be between 1 and %d", openalex.MaxPerPage)
+			}
+			checkLayout()
+			format := getAbstractFormat()
+			crosswalk := loadCrosswalk()
+			defer writeUnmapped(crosswalk)
+			tracker := newTracker("search openalex")
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			header := openalexHeader
+			err = wr.Write(header)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+
+			cursor := "*"
+			for cursor != "" {
+				openalexLimiter.Wait()
+				log.Printf("Searching OpenAlex for %s\n", filter)
+				page, err := openalex.Search(filter, cursor, results, utils.Mailto)
+				if err != nil {
+					log.Fatal(err)
+				}
+				if cursor == "*" {
+					tracker.AddTotal(page.Meta.Count)
+				}
+				if len(page.Results) == 0 {
+					break
+				}
+				cursor = page.Meta.NextCursor
+
+				for _, w := range page.Results {
+					cacheDir, err := utils.MkTmpDir(filepath.Join("openalex", openalex.ShortID(w.ID)))
+					if err != nil {
+						log.Fatal("Unable to write to tmp filesystem")
+					}
+					utils.WriteCachedFile(filepath.Join(cacheDir, "work.json"), string(w.Raw))
+
+					pdf := ""
+					if downloadPdfs && w.PDFURL() != "" {
+						pdf = filepath.Join("papers", "openalex", openalex.ShortID(w.ID)+".pdf")
+						if err := utils.DownloadPdf(w.PDFURL(), pdf); err != nil {
+							if err := os.Remove(pdf); err != nil && !os.IsNotExist(err) {
+								log.Println("Error deleting file:", err)
+							}
+							pdf = w.PDFURL()
+						}
+					}
+
+					row := openalexRow(w, pdf, filter, format, crosswalk)
+					err = wr.Write(storeLayout(header, row))
+					if err != nil {
+						log.Fatalf("Unable to write to CSV: %v", err)
+					}
+					wr.Flush()
+
+					if downloadPdfs {
+						tracker.PDF(pdf != "" && fileExists(pdf))
+					}
+					tracker.License(record.New(header, row).Get("field_rights") != "")
+					tracker.Done("openalex", progress.Harvested)
+				}
+			}
+		},
+	}
+)
+
+var openalexHeader = append([]string{
+	"id",
+	"field_edtf_date_issued",
+	"title",
+	"field_full_title",
+	"field_abstract",
+	"field_model",
+	"field_linked_agent",
+	"field_affiliation",
+	"field_publisher",
+	"field_identifier",
+	"field_part_detail",
+	"field_related_item",
+	"field_extent",
+	"field_language",
+	"field_rights",
+	"rights_source",
+	"field_subject",
+	"field_subject_uri",
+	"file",
+}, append(openalexColumns, "openalex_filter")...)
+
+// openalexColumns are the columns enrich openalex adds to a CSV
+var openalexColumns = []string{
+	"openalex_id",
+	"openalex_topics",
+	"oa_status",
+	"cited_by_count",
+	"institution",
+	"institution_ror",
+}
+
+// openalexValues returns a work's values for openalexColumns
+// an institution without a ROR ID has an empty entry in institution_ror to keep the two aligned
+func openalexValues(w openalex.Work) []string {
+	topics := []string{}
+	for _, t := range w.Topics {
+		topics = append(topics, t.DisplayName)
+	}
+	names, rors := []string{}, []string{}
+	for _, i := range w.Institutions() {
+		names = append(names, i.DisplayName)
+		rors = append(rors, i.ROR)
+	}
+
+	return []string{
+		w.ID,
+		strings.Join(topics, "|"),
+		w.OpenAccess.OAStatus,
+		strconv.Itoa(w.CitedByCount),
+		strings.Join(names, "|"),
+		strings.Join(rors, "|"),
+	}
+}
+
+// openalexRow turns a work into a row of openalexHeader
+// works are identified by their DOI, or their OpenAlex ID if they don't have one
+func openalexRow(w openalex.Work, pdf, filter string, format abstract.Format, crosswalk *subject.Crosswalk) []string {
+	id := w.DOIName()
+	if id == "" {
+		id = openalex.ShortID(w.ID)
+	}
+
+	linkedAgent := []string{}
+	affiliations := []string{}
+	for _, a := range w.Authorships {
+		linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:person:%s", a.Author.Name()))
+		names := a.RawAffiliationStrings
+		if len(a.Institutions) > 0 {
+			names = []string{}
+			for _, i := range a.Institutions {
+				names = append(names, i.DisplayName)
+			}
+		}
+		for _, name := range names {
+			if !utils.StrInSlice(name, affiliations) {
+				affiliations = append(affiliations, name)
+			}
+		}
+	}
+
+	identifiers := []string{}
+	if w.DOIName() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, w.DOIName()))
+	}
+	identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"openalex","value":"%s"}`, openalex.ShortID(w.ID)))
+	if w.IDs.PMID != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"pmid","value":"%s"}`, filepath.Base(w.IDs.PMID)))
+	}
+	if w.IDs.PMCID != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"pmcid","value":"%s"}`, strings.ToUpper(filepath.Base(w.IDs.PMCID))))
+	}
+
+	publisher := ""
+	relatedItem := ""
+	if l := w.PrimaryLocation; l != nil {
+		if l.Source.HostOrganizationName != "" {
+			publisher = fmt.Sprintf("relators:pbl:corporate_body:%s", l.Source.HostOrganizationName)
+		}
+		if l.Source.DisplayName != "" {
+			relatedItem = fmt.Sprintf(`{"title": "%s"}`, l.Source.DisplayName)
+		}
+		for _, issn := range l.Source.ISSN {
+			identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"issn","value":"%s"}`, issn))
+		}
+	}
+
+	partDetail := []string{}
+	if w.Biblio.Volume != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, w.Biblio.Volume))
+	}
+	if w.Biblio.Issue != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "issue", "number": "%s"}`, w.Biblio.Issue))
+	}
+	extent := ""
+	if pages := w.Biblio.FirstPage; pages != "" {
+		if w.Biblio.LastPage != "" && w.Biblio.LastPage != pages {
+			pages = fmt.Sprintf("%s-%s", pages, w.Biblio.LastPage)
+		}
+		extent = fmt.Sprintf(`{"attr0": "page", "number": "%s"}`, pages)
+	}
+
+	subjects := subject.Terms{}
+	for _, t := range w.Topics {
+		subjects = subjects.Add(mapSubject(crosswalk, "openalex", openalex.ShortID(t.ID), t.DisplayName)...)
+	}
+
+	rights := license.Rights{}
+	if uri := license.CreativeCommonsURI(w.License()); uri != "" {
+		rights = license.Rights{URI: uri, Source: license.OpenAlex}
+	}
+
+	fullTitle := ""
+	if len(w.Title) > 255 {
+		fullTitle = w.Title
+	}
+	row := []string{
+		id,
+		w.PublicationDate,
+		utils.TrimToMaxLen(w.Title, 255),
+		fullTitle,
+		abstract.FromArxiv(w.Abstract(), format),
+		"Digital Document",
+		strings.Join(linkedAgent, "|"),
+		strings.Join(affiliations, "|"),
+		publisher,
+		strings.Join(identifiers, "|"),
+		strings.Join(partDetail, "|"),
+		relatedItem,
+		extent,
+		w.Language,
+		rights.URI,
+		rights.Source,
+		subjects.Labels(),
+		subjects.URIs(),
+		pdf,
+	}
+	row = append(row, openalexValues(w)...)
+
+	return append(row, filter)
+}
+
+func init() {
+	searchCmd.AddCommand(openalexCmd)
+
+	openalexCmd.Flags().StringVarP(&openalex.URL, "url", "u", openalex.URL, "The OpenAlex API url")
+	openalexCmd.Flags().StringSliceVar(&openalexFilter.Institutions, "institution", nil, "only works by authors at this institution's ROR ID e.g. 012afjb06 (can be repeated)")
+	openalexCmd.Flags().StringSliceVar(&openalexFilter.Authors, "author", nil, "only works by this OpenAlex author ID or ORCID iD (can be repeated with IDs of the same kind)")
+	openalexCmd.Flags().StringSliceVar(&openalexFilter.Concepts, "concept", nil, "only works tagged with this OpenAlex concept ID e.g. C41008148 (can be repeated)")
+	openalexCmd.Flags().StringSliceVar(&openalexFilter.Topics, "topic", nil, "only works about this OpenAlex topic ID e.g. T10102 (can be repeated)")
+	openalexCmd.Flags().StringVar(&openalexFilter.From, "from", "", "only works published on or after this date e.g. 2024-01-01")
+	openalexCmd.Flags().StringVar(&openalexFilter.To, "to", "", "only works published on or before this date e.g. 2024-12-31")
+	openalexCmd.Flags().IntP("results", "r", openalex.MaxPerPage, "The number of works to fetch in a request")
+	openalexCmd.Flags().StringVar(&utils.Mailto, "mailto", "", "email address to send with requests to use OpenAlex's polite pool")
+	openalexCmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the open access PDFs")
+	addAbstractFormatFlag(openalexCmd)
+	openalexCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping OpenAlex topic IDs to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	openalexCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	openalexCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each work with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	openalexCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	openalexCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// packageCmd represents the package command
+var packageCmd = &cobra.Command{
+	Use:   "package",
+	Short: "Package harvested articles.",
+	Long: `Package the articles listed in a papercut CSV for preservation.
+
+A subcommand is required in order to choose the package format.`,
+}
+
+func init() {
+	rootCmd.AddCommand(packageCmd)
+}
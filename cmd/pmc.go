@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/bundle"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/pubmed"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// used for flags.
+	ncbiCredentials pubmed.Credentials
+	pmcOAURL        string
+
+	pmcCmd = &cobra.Command{
+		Use:   "pmc",
+		Short: "Get open access PDFs and JATS XML from PubMed Central",
+		Long: `Get the PDFs and JATS XML of articles in the PubMed Central Open Access Subset.
+
+The file lists one PMCID per line e.g. PMC1234567. Files are saved in the papers
+directory as <PMCID>.pdf and <PMCID>.xml, with the article's OA package unpacked
+into papers/<PMCID> when PMC has one.
+
+Set NCBI_API_KEY (or pass --api-key) to be allowed ten requests a second instead of three.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			file, err := os.Open(filePath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer file.Close()
+
+			scanner := bufio.NewScanner(file)
+			pmcids := []string{}
+			for scanner.Scan() {
+				if id := strings.TrimSpace(scanner.Text()); id != "" {
+					pmcids = append(pmcids, pubmed.NormalizePMCID(id))
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				log.Fatal(err)
+			}
+
+			eutilsURL, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			limiter := ncbiLimiter()
+			tracker := newTracker("get pmc")
+			tracker.AddTotal(len(pmcids))
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			err = wr.Write(pmcHeader)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+			for _, id := range pmcids {
+				f, err := downloadPMC(id, eutilsURL, limiter)
+				if err != nil {
+					log.Printf("Unable to get %s from PMC: %v", id, err)
+					tracker.Done("pmc", progress.Failed)
+					continue
+				}
+
+				err = wr.Write([]string{
+					id,
+					f.Rights(),
+					f.PDF,
+					f.JATS,
+					f.Record.Retracted,
+				})
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+
+				tracker.PDF(f.PDF != "")
+				tracker.License(f.Rights() != "")
+				tracker.Done("pmc", progress.Harvested)
+			}
+		},
+	}
+)
+
+var pmcHeader = []string{
+	"id",
+	"field_rights",
+	"file",
+	"jats",
+	"retracted",
+}
+
+// pmcFiles are what we saved of an article in the PMC Open Access Subset
+type pmcFiles struct {
+	Record *pubmed.OARecord
+	// PDF and JATS are local paths, "" if PMC doesn't have the file
+	PDF  string
+	JATS string
+}
+
+// Rights is the URI of the article's Creative Commons license, if it has one
+func (f pmcFiles) Rights() string {
+	return license.CreativeCommonsURI(f.Record.License)
+}
+
+// ncbiLimiter reads NCBI_API_KEY when --api-key isn't set
+// and returns a limiter spacing requests out as far as NCBI asks
+func ncbiLimiter() *ratelimit.Limiter {
+	if ncbiCredentials.APIKey == "" {
+		ncbiCredentials.APIKey = os.Getenv("NCBI_API_KEY")
+	}
+
+	return ratelimit.New(ncbiCredentials.Delay())
+}
+
+// pmcPath is where a file of a PMC article is saved e.g. papers/PMC1234567.pdf
+func pmcPath(pmcid, ext string) string {
+	return filepath.Join(arxivPdfDirectory, pmcid+ext)
+}
+
+// downloadPMC fetches an article's OA record, caching it as oa.xml
+// then downloads its PDF and JATS XML
+// the PDF comes from the record's pdf link or else its OA package, and the JATS
+// from the OA package or else efetch
+func downloadPMC(pmcid, eutilsURL string, limiter *ratelimit.Limiter) (pmcFiles, error) {
+	f := pmcFiles{}
+	cacheDir, err := utils.MkTmpDir(filepath.Join("pmc", pmcid))
+	if err != nil {
+		log.Fatal("Unable to write to tmp filesystem")
+	}
+	oaFile := filepath.Join(cacheDir, "oa.xml")
+	if !fileExists(oaFile) {
+		limiter.Wait()
+	}
+	body := utils.GetResult(oaFile, pubmed.OAQueryURL(pmcOAURL, pmcid), "application/xml")
+	if body == nil {
+		return f, fmt.Errorf("no response from the PMC OA service")
+	}
+	f.Record, err = pubmed.ParseOAResponse(body)
+	if err != nil {
+		// articles can join the Open Access Subset later so don't keep the answer
+		os.Remove(oaFile)
+		return f, err
+	}
+
+	if link := f.Record.Link("pdf"); link != "" {
+		if err := utils.DownloadPdf(link, pmcPath(pmcid, ".pdf")); err == nil {
+			f.PDF = pmcPath(pmcid, ".pdf")
+		}
+	}
+
+	if link := f.Record.Link("tgz"); link != "" {
+		pkg := filepath.Join(cacheDir, "package.tar.gz")
+		dir := pmcPath(pmcid, "")
+		if err := utils.DownloadFile(link, pkg, "*/*"); err != nil {
+			log.Printf("Unable to download the OA package for %s: %v", pmcid, err)
+		} else if _, err := bundle.Unpack(pkg, dir, pmcid); err != nil {
+			log.Printf("Unable to unpack the OA package for %s: %v", pmcid, err)
+		} else {
+			pdf, nxml := pmcPackageFiles(dir)
+			if f.PDF == "" && pdf != "" {
+				if err := utils.CopyFile(pdf, pmcPath(pmcid, ".pdf")); err == nil {
+					f.PDF = pmcPath(pmcid, ".pdf")
+				}
+			}
+			if nxml != "" {
+				if err := utils.CopyFile(nxml, pmcPath(pmcid, ".xml")); err == nil {
+					f.JATS = pmcPath(pmcid, ".xml")
+				}
+			}
+		}
+	}
+
+	if f.JATS == "" {
+		jats := pmcPath(pmcid, ".xml")
+		if !fileExists(jats) {
+			limiter.Wait()
+			log.Println("Fetching the JATS XML for", pmcid)
+			body, err := pubmed.FetchPMC(eutilsURL, pmcid, ncbiCredentials)
+			if err != nil {
+				log.Printf("Unable to fetch the JATS XML for %s: %v", pmcid, err)
+				return f, nil
+			}
+			if err := os.WriteFile(jats, body, 0644); err != nil {
+				log.Printf("Unable to write %s: %v", jats, err)
+				return f, nil
+			}
+		}
+		f.JATS = jats
+	}
+
+	return f, nil
+}
+
+// pmcPackageFiles finds the PDF and JATS XML (.nxml) in an unpacked OA package
+func pmcPackageFiles(dir string) (pdf, nxml string) {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".pdf":
+			if pdf == "" {
+				pdf = path
+			}
+		case ".nxml":
+			if nxml == "" {
+				nxml = path
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Unable to read %s: %v", dir, err)
+	}
+
+	return pdf, nxml
+}
+
+func init() {
+	getCmd.AddCommand(pmcCmd)
+
+	pmcCmd.Flags().StringP("url", "u", pubmed.EutilsURL, "The NCBI E-utilities url")
+	pmcCmd.Flags().StringVar(&pmcOAURL, "oa-url", pubmed.OAURL, "The PMC Open Access Web Service url")
+	pmcCmd.Flags().StringVarP(&filePath, "file", "f", "", "path to file containing one PMCID per line")
+	pmcCmd.Flags().StringVar(&ncbiCredentials.APIKey, "api-key", "", "NCBI API key (defaults to NCBI_API_KEY)")
+	pmcCmd.Flags().StringVar(&ncbiCredentials.Email, "email", "", "email address NCBI can contact about your requests")
+	pmcCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	pmcCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+)
+
+// used for flags.
+var (
+	summaryPath  string
+	showProgress bool
+)
+
+// newTracker starts tracking the progress of command on stderr
+func newTracker(command string) *progress.Tracker {
+	var out io.Writer = os.Stderr
+	if !showProgress {
+		out = nil
+	}
+
+	return progress.New(command, out)
+}
+
+// writeSummary writes the run summary to --summary
+func writeSummary(t *progress.Tracker) {
+	if summaryPath == "" {
+		return
+	}
+	if err := t.WriteSummary(summaryPath); err != nil {
+		log.Printf("Unable to write the run summary to %s: %v", summaryPath, err)
+	}
+}
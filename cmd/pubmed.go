@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/pubmed"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pubmedCmd = &cobra.Command{
+		Use:   "pubmed",
+		Short: "Search PubMed for articles",
+		Long: `Search PubMed for articles with NCBI's E-utilities.
+
+The query uses PubMed's search syntax e.g. "Lehigh University[ad] AND 2024[dp]".
+Results are kept on NCBI's history server and fetched --results at a time.
+MeSH headings become subjects, which --crosswalk can map with the source mesh
+and the heading's MeSH ID e.g. D006801.
+
+Articles in the PubMed Central Open Access Subset have their PDF and JATS XML
+downloaded like papercut get pmc does.
+
+Set NCBI_API_KEY (or pass --api-key) to be allowed ten requests a second instead of three.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if query == "" {
+				log.Fatal("--query required.")
+			}
+			eutilsURL, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			start, err := cmd.Flags().GetInt("start")
+			if err != nil {
+				log.Fatal(err)
+			}
+			results, err := cmd.Flags().GetInt("results")
+			if err != nil {
+				log.Fatal(err)
+			}
+			checkLayout()
+			format := getAbstractFormat()
+			crosswalk := loadCrosswalk()
+			defer writeUnmapped(crosswalk)
+			limiter := ncbiLimiter()
+
+			limiter.Wait()
+			log.Printf("Searching PubMed for %s\n", query)
+			search, err := pubmed.NewSearch(eutilsURL, query, ncbiCredentials)
+			if err != nil {
+				log.Fatal(err)
+			}
+			tracker := newTracker("search pubmed")
+			tracker.AddTotal(search.Count - start)
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			header := pubmedHeader
+			err = wr.Write(header)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+			for offset := start; offset < search.Count; offset += results {
+				limiter.Wait()
+				log.Printf("Fetching PubMed results %d to %d of %d\n", offset+1, min(offset+results, search.Count), search.Count)
+				set, err := search.Fetch(eutilsURL, offset, results, ncbiCredentials)
+				if err != nil {
+					log.Fatal(err)
+				}
+
+				for _, a := range set.Articles {
+					cacheDir, err := utils.MkTmpDir(filepath.Join("pubmed", a.PMID))
+					if err != nil {
+						log.Fatal("Unable to write to tmp filesystem")
+					}
+					utils.WriteCachedFile(filepath.Join(cacheDir, "pubmed.xml"), fmt.Sprintf("<PubmedArticle>%s</PubmedArticle>", a.Raw))
+
+					var files *pmcFiles
+					if downloadPdfs && a.PMCID() != "" {
+						f, err := downloadPMC(a.PMCID(), eutilsURL, limiter)
+						if err != nil {
+							log.Printf("Unable to get %s from PMC: %v", a.PMCID(), err)
+						} else {
+							files = &f
+						}
+					}
+
+					row := pubmedRow(a, files, format, crosswalk)
+					err = wr.Write(storeLayout(header, row))
+					if err != nil {
+						log.Fatalf("Unable to write to CSV: %v", err)
+					}
+					wr.Flush()
+
+					if downloadPdfs {
+						tracker.PDF(files != nil && files.PDF != "")
+					}
+					tracker.License(files != nil && files.Rights() != "")
+					tracker.Done("pubmed", progress.Harvested)
+				}
+				if len(set.Articles) == 0 {
+					break
+				}
+			}
+		},
+	}
+)
+
+var pubmedHeader = []string{
+	"id",
+	"field_edtf_date_issued",
+	"title",
+	"field_full_title",
+	"field_abstract",
+	"field_model",
+	"field_linked_agent",
+	"field_affiliation",
+	"field_identifier",
+	"field_part_detail",
+	"field_related_item",
+	"field_extent",
+	"field_language",
+	"field_rights",
+	"rights_source",
+	"field_subject",
+	"field_subject_uri",
+	"file",
+	"pmcid",
+}
+
+// pubmedRow turns a PubMed article into a row of pubmedHeader
+// files are what was downloaded from PubMed Central, nil if the article isn't there
+func pubmedRow(a pubmed.Article, files *pmcFiles, format abstract.Format, crosswalk *subject.Crosswalk) []string {
+	linkedAgent := []string{}
+	affiliations := []string{}
+	for _, author := range a.Authors {
+		agentType := "person"
+		if author.LastName == "" {
+			agentType = "corporate_body"
+		}
+		linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:%s:%s", agentType, author.Name()))
+		for _, affiliation := range author.Affiliations {
+			if !utils.StrInSlice(affiliation, affiliations) {
+				affiliations = append(affiliations, affiliation)
+			}
+		}
+	}
+
+	identifiers := []string{
+		fmt.Sprintf(`{"attr0":"pmid","value":"%s"}`, a.PMID),
+	}
+	if a.PMCID() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"pmcid","value":"%s"}`, a.PMCID()))
+	}
+	if a.DOI() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, a.DOI()))
+	}
+	for _, i := range a.Journal.ISSNs {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"issn","value":"%s"}`, i.Value))
+	}
+
+	partDetail := []string{}
+	if a.Journal.Issue.Volume != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, a.Journal.Issue.Volume))
+	}
+	if a.Journal.Issue.Issue != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "issue", "number": "%s"}`, a.Journal.Issue.Issue))
+	}
+	relatedItem := ""
+	if a.Journal.Title != "" {
+		relatedItem = fmt.Sprintf(`{"title": "%s"}`, a.Journal.Title)
+	}
+	extent := ""
+	if a.Pagination != "" {
+		extent = fmt.Sprintf(`{"attr0": "page", "number": "%s"}`, a.Pagination)
+	}
+
+	subjects := subject.Terms{}
+	for _, h := range a.MeshHeadings {
+		subjects = subjects.Add(mapSubject(crosswalk, "mesh", h.Descriptor.UI, h.Descriptor.Name)...)
+	}
+
+	rights := license.Rights{}
+	pdf := ""
+	if files != nil {
+		if uri := files.Rights(); uri != "" {
+			rights = license.Rights{URI: uri, Source: license.PMC}
+		}
+		pdf = files.PDF
+	}
+
+	title := a.Title.Text()
+	fullTitle := ""
+	if len(title) > 255 {
+		fullTitle = title
+	}
+	return []string{
+		a.PMID,
+		a.Issued(),
+		utils.TrimToMaxLen(title, 255),
+		fullTitle,
+		abstract.FromJATS(a.AbstractJATS(), format),
+		"Digital Document",
+		strings.Join(linkedAgent, "|"),
+		strings.Join(affiliations, "|"),
+		strings.Join(identifiers, "|"),
+		strings.Join(partDetail, "|"),
+		relatedItem,
+		extent,
+		strings.Join(a.Languages, "|"),
+		rights.URI,
+		rights.Source,
+		subjects.Labels(),
+		subjects.URIs(),
+		pdf,
+		a.PMCID(),
+	}
+}
+
+func init() {
+	searchCmd.AddCommand(pubmedCmd)
+
+	pubmedCmd.Flags().StringP("url", "u", pubmed.EutilsURL, "The NCBI E-utilities url")
+	pubmedCmd.Flags().StringVar(&pmcOAURL, "oa-url", pubmed.OAURL, "The PMC Open Access Web Service url")
+	pubmedCmd.Flags().StringVarP(&query, "query", "q", "", "The PubMed search query to perform")
+	pubmedCmd.Flags().IntP("start", "s", 0, "The offset")
+	pubmedCmd.Flags().IntP("results", "r", 100, "The number of articles to fetch in a request")
+	pubmedCmd.Flags().StringVar(&ncbiCredentials.APIKey, "api-key", "", "NCBI API key (defaults to NCBI_API_KEY)")
+	pubmedCmd.Flags().StringVar(&ncbiCredentials.Email, "email", "", "email address NCBI can contact about your requests")
+	pubmedCmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the PDFs and JATS XML of articles in PubMed Central")
+	addAbstractFormatFlag(pubmedCmd)
+	pubmedCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping MeSH IDs to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	pubmedCmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	pubmedCmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each article with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	pubmedCmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	pubmedCmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
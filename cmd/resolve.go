@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/retraction"
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+	"github.com/spf13/cobra"
+)
+
+// indexedPaper is what a bibliographic index like DBLP or Semantic Scholar knows about a paper
+// which is used for its row when it can't be resolved through Crossref or arXiv
+type indexedPaper struct {
+	ID       string
+	DOI      string
+	ArxivID  string
+	Date     string
+	Title    string
+	Abstract string
+	// Authors are written Given Family, as DBLP and Semantic Scholar do
+	Authors []string
+	Venue   string
+	Volume  string
+	Issue   string
+	Pages   string
+	// PDF is the URL of an open access copy, if the index knows one
+	PDF string
+	// Identifiers are the paper's IDs in the index e.g. {"attr0":"dblp","value":"..."}
+	Identifiers []string
+}
+
+// resolver fills in papers found in an index with their Crossref or arXiv metadata
+type resolver struct {
+	doiURL      string
+	arxivURL    string
+	format      abstract.Format
+	crosswalk   *subject.Crosswalk
+	retractions retraction.Dataset
+	arxiv       arxivOptions
+}
+
+// newResolver reads the flags of the command, including the ones arxivRow uses
+func newResolver(cmd *cobra.Command, doiURL, arxivURL string) resolver {
+	o := loadArxivOptions(cmd)
+	o.download = downloadPdfs
+	o.limiter = arxivLimiter
+
+	return resolver{
+		doiURL:      doiURL,
+		arxivURL:    arxivURL,
+		format:      o.format,
+		crosswalk:   o.crosswalk,
+		retractions: loadRetractions(),
+		arxiv:       o,
+	}
+}
+
+// resolve returns the paper as a row of doiHeader and where its metadata came from:
+// crossref if it has a DOI, arxiv if it's a preprint and index if neither could be found
+func (r resolver) resolve(p indexedPaper, index string) ([]string, string) {
+	if p.DOI != "" {
+		a, err := doi.GetDoi(p.DOI, r.doiURL)
+		if err == nil {
+			pdf := ""
+			if downloadPdfs {
+				pdf = a.DownloadPdf()
+			}
+			return doiRow(p.DOI, a, pdf, r.format, r.crosswalk, r.retractions), "crossref"
+		}
+		log.Printf("Unable to get %s from Crossref: %v", p.DOI, err)
+	}
+
+	if p.ArxivID != "" {
+		if row, ok := r.arxivRow(p.ArxivID); ok {
+			return row, "arxiv"
+		}
+	}
+
+	return p.row(r.format), index
+}
+
+// arxivRow fetches an arXiv paper and reshapes its row of arxivHeader into doiHeader
+func (r resolver) arxivRow(id string) ([]string, bool) {
+	params := url.Values{}
+	params.Set("id_list", id)
+	arxivLimiter.Wait()
+	result, err := arxiv.GetResults(fmt.Sprintf("%s?%s", r.arxivURL, params.Encode()))
+	if err != nil {
+		log.Printf("Unable to get %s from arXiv: %v", id, err)
+		return nil, false
+	}
+	for _, e := range result.Entries {
+		entryID, version, ok := splitArxivID(e.ID)
+		if !ok {
+			continue
+		}
+		e.ID = entryID
+		row, pdf := arxivRow(e, version, "", r.arxiv)
+		paper := record.New(arxivHeader, row)
+
+		rec := record.New(doiHeader, nil)
+		for _, column := range doiHeader {
+			if paper.Has(column) {
+				rec.Set(column, paper.Get(column))
+			}
+		}
+		rec.Set("field_model", "Digital Document")
+		if rec.Get("field_rights") != "" {
+			rec.Set("rights_source", license.Arxiv)
+		}
+		rec.Set("file", pdf)
+
+		return rec.Row(), true
+	}
+	log.Printf("arXiv doesn't have %s", id)
+
+	return nil, false
+}
+
+// row builds a row of doiHeader from the index's metadata
+func (p indexedPaper) row(format abstract.Format) []string {
+	linkedAgent := []string{}
+	for _, name := range p.Authors {
+		linkedAgent = append(linkedAgent, fmt.Sprintf("relators:aut:person:%s", utils.InvertName(name)))
+	}
+	partDetail := []string{}
+	if p.Volume != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "volume", "number": "%s"}`, p.Volume))
+	}
+	if p.Issue != "" {
+		partDetail = append(partDetail, fmt.Sprintf(`{"type": "issue", "number": "%s"}`, p.Issue))
+	}
+
+	rec := record.New(doiHeader, nil)
+	rec.Set("id", p.ID)
+	rec.Set("field_edtf_date_issued", p.Date)
+	rec.Set("title", utils.TrimToMaxLen(p.Title, 255))
+	if len(p.Title) > 255 {
+		rec.Set("field_full_title", p.Title)
+	}
+	rec.Set("field_abstract", abstract.FromArxiv(p.Abstract, format))
+	rec.Set("field_model", "Digital Document")
+	rec.Set("field_linked_agent", strings.Join(linkedAgent, "|"))
+	rec.Set("field_identifier", strings.Join(p.Identifiers, "|"))
+	rec.Set("field_part_detail", strings.Join(partDetail, "|"))
+	if p.Venue != "" {
+		rec.Set("field_related_item", fmt.Sprintf(`{"title": "%s"}`, p.Venue))
+	}
+	if p.Pages != "" {
+		rec.Set("field_extent", fmt.Sprintf(`{"attr0": "page", "number": "%s"}`, p.Pages))
+	}
+	rec.Set("file", p.PDF)
+
+	return rec.Row()
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/retraction"
+)
+
+var (
+	// used for flags.
+	retractionWatchPath string
+)
+
+// loadRetractions reads the Retraction Watch dataset passed with --retraction-watch, if any
+func loadRetractions() retraction.Dataset {
+	if retractionWatchPath == "" {
+		return nil
+	}
+	d, err := retraction.LoadRetractionWatch(retractionWatchPath)
+	if err != nil {
+		log.Fatalf("Unable to load the Retraction Watch dataset: %v", err)
+	}
+
+	return d
+}
+
+// retractionStatus returns an article's retraction_status and retraction_notice columns
+func retractionStatus(a doi.Article, retractions retraction.Dataset) (string, string) {
+	updates := retractions.Check(a)
+	status := retraction.Status(updates)
+	if status != "" && status != retraction.Notice {
+		log.Printf("%s is %s", a.DOI, strings.ReplaceAll(status, "_", " "))
+	}
+
+	return status, strings.Join(retraction.DOIs(updates), "|")
+}
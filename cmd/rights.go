@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+)
+
+var (
+	// used for flags.
+	unpaywallEmail string
+
+	// arxivLimiter spaces out the arXiv requests made while looking up DOIs
+	arxivLimiter = ratelimit.New(arxiv.RequestDelay)
+
+	// skipSherpa leaves Sherpa Romeo out of articleRights
+	// e.g. when papercut serve runs without SHERPA_ROMEO_API_KEY
+	skipSherpa bool
+)
+
+// articleRights finds the rights statement for an article
+// falling back from Sherpa Romeo to DOAB, Crossref, Unpaywall and arXiv
+func articleRights(a doi.Article) license.Rights {
+	return license.ForArticle(a, license.Options{
+		UnpaywallEmail: unpaywallEmail,
+		SkipSherpa:     skipSherpa,
+		ArxivRecord: func(id string) map[string]string {
+			return arxivOai(id, arxivLimiter)
+		},
+	})
+}
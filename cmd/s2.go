@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/pkg/progress"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/s2"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s2Cmd = &cobra.Command{
+		Use:   "s2",
+		Short: "Search Semantic Scholar for articles",
+		Long: `Search the Semantic Scholar Graph API for the papers of an author.
+
+Papers with a DOI are filled in from Crossref and arXiv preprints from arXiv,
+the same as get doi and search arxiv would, and anything else is written with what Semantic Scholar knows.
+The s2_paper_id column keeps each paper's Semantic Scholar ID.
+
+Requests without an API key share Semantic Scholar's rate limit with everyone else,
+so pass --api-key or set S2_API_KEY if you have one.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			author, err := cmd.Flags().GetString("author")
+			if err != nil {
+				log.Fatal(err)
+			}
+			apiKey, err := cmd.Flags().GetString("api-key")
+			if err != nil {
+				log.Fatal(err)
+			}
+			doiURL, err := cmd.Flags().GetString("doi-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			arxivURL, err := cmd.Flags().GetString("arxiv-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			start, err := cmd.Flags().GetInt("start")
+			if err != nil {
+				log.Fatal(err)
+			}
+			results, err := cmd.Flags().GetInt("results")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if author == "" {
+				log.Fatal("--author is required")
+			}
+			if apiKey == "" {
+				apiKey = os.Getenv("S2_API_KEY")
+			}
+			if results < 1 || results > s2.MaxLimit {
+				log.Fatalf("--results must be between 1 and %d", s2.MaxLimit)
+			}
+			checkLayout()
+			r := newResolver(cmd, doiURL, arxivURL)
+			defer writeUnmapped(r.crosswalk)
+
+			limiter := ratelimit.New(s2.RequestDelay)
+			papers := []s2.Paper{}
+			offset := start
+			for {
+				limiter.Wait()
+				log.Printf("Fetching the papers of Semantic Scholar author %s starting at %d\n", author, offset)
+				page, err := s2.GetAuthorPapers(author, offset, results, apiKey)
+				if err != nil {
+					log.Fatal(err)
+				}
+				papers = append(papers, page.Data...)
+				if page.Next == 0 || len(page.Data) == 0 {
+					break
+				}
+				offset = page.Next
+			}
+
+			tracker := newTracker("search s2")
+			tracker.AddTotal(len(papers))
+			defer writeSummary(tracker)
+
+			wr := csv.NewWriter(os.Stdout)
+			header := s2Header
+			err = wr.Write(header)
+			if err != nil {
+				log.Fatalf("Unable to write to CSV: %v", err)
+			}
+			for _, p := range papers {
+				row, source := r.resolve(s2Paper(p), "s2")
+				row = append(row, p.ArxivID(), p.PaperID)
+				err = wr.Write(storeLayout(header, row))
+				if err != nil {
+					log.Fatalf("Unable to write to CSV: %v", err)
+				}
+				wr.Flush()
+
+				rec := record.New(header, row)
+				if downloadPdfs {
+					tracker.PDF(rec.Get("file") != "" && fileExists(rec.Get("file")))
+				}
+				tracker.License(rec.Get("field_rights") != "")
+				tracker.Done(source, progress.Harvested)
+			}
+		},
+	}
+)
+
+// s2Header is doiHeader with the paper's arXiv ID and Semantic Scholar ID
+var s2Header = append(append([]string{}, doiHeader...), "arxiv_id", "s2_paper_id")
+
+// s2Paper is what Semantic Scholar knows about a paper
+func s2Paper(p s2.Paper) indexedPaper {
+	identifiers := []string{}
+	if p.DOI() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"doi","value":"%s"}`, p.DOI()))
+	}
+	if p.ArxivID() != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"arxiv","value":"%s"}`, p.ArxivID()))
+	}
+	if p.ExternalIDs.PubMed != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"pmid","value":"%s"}`, p.ExternalIDs.PubMed))
+	}
+	if p.ExternalIDs.DBLP != "" {
+		identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"dblp","value":"%s"}`, p.ExternalIDs.DBLP))
+	}
+	identifiers = append(identifiers, fmt.Sprintf(`{"attr0":"s2","value":"%s"}`, p.PaperID))
+
+	authors := []string{}
+	for _, a := range p.Authors {
+		authors = append(authors, a.Name)
+	}
+	date := p.PublicationDate
+	if date == "" && p.Year > 0 {
+		date = strconv.Itoa(p.Year)
+	}
+	id := p.DOI()
+	if id == "" {
+		id = p.PaperID
+	}
+
+	paper := indexedPaper{
+		ID:          id,
+		DOI:         p.DOI(),
+		ArxivID:     p.ArxivID(),
+		Date:        date,
+		Title:       p.Title,
+		Abstract:    p.Abstract,
+		Authors:     authors,
+		Venue:       p.Venue,
+		Identifiers: identifiers,
+	}
+	if j := p.Journal; j != nil {
+		if j.Name != "" {
+			paper.Venue = j.Name
+		}
+		paper.Volume = j.Volume
+		paper.Pages = j.Pages
+	}
+	if p.OpenAccessPDF != nil {
+		paper.PDF = p.OpenAccessPDF.URL
+	}
+
+	return paper
+}
+
+func init() {
+	searchCmd.AddCommand(s2Cmd)
+
+	s2Cmd.Flags().StringVarP(&s2.URL, "url", "u", s2.URL, "The Semantic Scholar Graph API url")
+	s2Cmd.Flags().String("author", "", "Semantic Scholar author ID to fetch the papers of e.g. 1741101")
+	s2Cmd.Flags().String("api-key", "", "Semantic Scholar API key (defaults to the S2_API_KEY environment variable)")
+	s2Cmd.Flags().IntP("start", "s", 0, "The offset of the first paper")
+	s2Cmd.Flags().IntP("results", "r", 100, "The number of papers to fetch in a request")
+	s2Cmd.Flags().String("doi-url", "https://dx.doi.org", "The DOI API url to look up papers with DOIs in")
+	s2Cmd.Flags().String("arxiv-url", "https://export.arxiv.org/api/query", "The arXiv API url to look up preprints in")
+	s2Cmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+	s2Cmd.Flags().BoolVarP(&downloadPdfs, "download-pdfs", "d", true, "whether to download the PDFs")
+	addAbstractFormatFlag(s2Cmd)
+	s2Cmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping Crossref subjects and arXiv categories to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+	s2Cmd.Flags().StringVar(&unmappedPath, "unmapped", "unmapped-subjects.csv", "where to report the subjects missing from the crosswalk")
+	s2Cmd.Flags().String("taxonomy", "", "path to an arXiv taxonomy JSON file created by \"papercut taxonomy refresh\" (defaults to the taxonomy built into papercut)")
+	s2Cmd.Flags().StringVar(&latexMode, "latex", "unicode", "how to convert LaTeX in arXiv titles, abstracts and names: unicode, tex (keep $...$ math), mathml or none")
+	s2Cmd.Flags().StringVar(&retractionWatchPath, "retraction-watch", "", "Retraction Watch dataset CSV to check for retractions, corrections and expressions of concern as well as Crossref")
+	s2Cmd.Flags().StringVar(&layoutTemplate, "layout", "", "also store each paper with a metadata.json and its upstream responses in a directory given by this template e.g. {source}/{year}/{id}")
+	s2Cmd.Flags().StringVar(&summaryPath, "summary", "run-summary.json", "where to write the JSON summary of the run (empty to skip it)")
+	s2Cmd.Flags().BoolVar(&showProgress, "progress", true, "print progress to stderr")
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+	"github.com/lehigh-university-libraries/papercut/pkg/retraction"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve papercut lookups over HTTP",
+		Long: `Serve papercut lookups over HTTP.
+
+Endpoints return JSON records with the same columns as the CSVs papercut writes:
+
+  GET /doi/{doi}          metadata for a DOI
+  GET /license/{doi}      the license for a DOI and where it came from
+  GET /policy/{issn}      the Sherpa Romeo policy for an ISSN
+  GET /arxiv/search       search arXiv with ?query= or ?ids= (and optionally &start= and &results=)
+  GET /health             whether the server is up
+
+Requests share papercut's cache and are rate limited per upstream service.
+PDFs are not downloaded.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			addr, err := cmd.Flags().GetString("addr")
+			if err != nil {
+				log.Fatal(err)
+			}
+			doiURL, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			arxivURL, err := cmd.Flags().GetString("arxiv-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if os.Getenv("SHERPA_ROMEO_API_KEY") == "" {
+				log.Println("Without SHERPA_ROMEO_API_KEY /policy is unavailable and licenses won't come from Sherpa Romeo")
+				skipSherpa = true
+			}
+
+			s := &server{
+				doiURL:      doiURL,
+				arxivURL:    arxivURL,
+				arxiv:       loadArxivOptions(cmd),
+				retractions: loadRetractions(),
+				crossref:    crossrefLimiter,
+			}
+			s.arxiv.limiter = arxivLimiter
+
+			log.Printf("Listening on %s", addr)
+			log.Fatal(http.ListenAndServe(addr, s.routes()))
+		},
+	}
+)
+
+type server struct {
+	doiURL   string
+	arxivURL string
+	arxiv    arxivOptions
+	crossref *ratelimit.Limiter
+	// retractions is the Retraction Watch dataset, if one was given
+	retractions retraction.Dataset
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("GET /doi/{doi...}", s.doi)
+	mux.HandleFunc("GET /license/{doi...}", s.license)
+	mux.HandleFunc("GET /policy/{issn}", s.policy)
+	mux.HandleFunc("GET /arxiv/search", s.arxivSearch)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("%s %s", r.Method, r.URL.RequestURI())
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "ok",
+		"version": rootCmd.Version,
+	})
+}
+
+// article fetches a DOI's metadata, waiting our turn if it isn't cached
+func (s *server) article(d string) (doi.Article, error) {
+	if !fileExists(filepath.Join(os.TempDir(), "dois", d, "doi.json")) {
+		s.crossref.Wait()
+	}
+
+	return doi.GetDoi(d, s.doiURL)
+}
+
+func (s *server) doi(w http.ResponseWriter, r *http.Request) {
+	d, err := doi.Normalize(r.PathValue("doi"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	a, err := s.article(d)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, record.New(doiHeader, doiRow(d, a, "", s.arxiv.format, s.arxiv.crosswalk, s.retractions)))
+}
+
+func (s *server) license(w http.ResponseWriter, r *http.Request) {
+	d, err := doi.Normalize(r.PathValue("doi"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	a, err := s.article(d)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	rights := articleRights(a)
+	rec := record.Record{}
+	rec.Set("id", d)
+	rec.Set("field_rights", rights.URI)
+	rec.Set("rights_source", rights.Source)
+
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *server) policy(w http.ResponseWriter, r *http.Request) {
+	issn := r.PathValue("issn")
+	p, err := romeo.GetIssnPublication(issn)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, romeo.ErrNoApiKey) {
+			status = http.StatusServiceUnavailable
+		} else if errors.Is(err, romeo.ErrNotFound) {
+			status = http.StatusNotFound
+		} else if _, invalid := romeo.NormalizeIssn(issn); invalid != nil && !errors.Is(invalid, romeo.ErrCheckDigit) {
+			status = http.StatusBadRequest
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	policies := []string{}
+	for _, publication := range p.Publications {
+		for _, policy := range publication.PublisherPolicies {
+			if policy.Uri != "" && !utils.StrInSlice(policy.Uri, policies) {
+				policies = append(policies, policy.Uri)
+			}
+		}
+	}
+	rec := record.Record{}
+	rec.Set("id", issn)
+	rec.Set("field_rights", p.GetLicense())
+	rec.Set("publisher_policy", strings.Join(policies, "|"))
+
+	writeJSON(w, http.StatusOK, rec)
+}
+
+func (s *server) arxivSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := q.Get("query")
+	params := url.Values{}
+	params.Set("start", "0")
+	params.Set("max_results", "10")
+	switch {
+	case q.Get("ids") != "":
+		params.Set("id_list", q.Get("ids"))
+		query = q.Get("ids")
+	case query != "":
+		params.Set("search_query", query)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query or ids is required"))
+		return
+	}
+	for param, limit := range map[string]int{"start": 0, "max_results": 100} {
+		v := q.Get(strings.TrimPrefix(param, "max_"))
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || (limit > 0 && n > limit) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", strings.TrimPrefix(param, "max_"), v))
+			return
+		}
+		params.Set(param, v)
+	}
+
+	s.arxiv.limiter.Wait()
+	result, err := arxiv.GetResults(fmt.Sprintf("%s?%s", s.arxivURL, params.Encode()))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	records := []record.Record{}
+	for _, e := range result.Entries {
+		id, version, ok := splitArxivID(e.ID)
+		if !ok {
+			continue
+		}
+		e.ID = id
+		row, _ := arxivRow(e, version, query, s.arxiv)
+		records = append(records, record.New(arxivHeader, row))
+	}
+
+	writeJSON(w, http.StatusOK, records)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Unable to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", ":8080", "address to listen on")
+	serveCmd.Flags().StringP("url", "u", "https://dx.doi.org", "The DOI API url")
+	serveCmd.Flags().String("arxiv-url", "https://export.arxiv.org/api/query", "The arXiv API url")
+	serveCmd.Flags().StringVar(&romeo.RetrieveURL, "sherpa-url", romeo.RetrieveURL, "The Sherpa Romeo retrieve API url")
+	serveCmd.Flags().StringVar(&unpaywallEmail, "unpaywall-email", "", "email address to look up licenses in Unpaywall with (Unpaywall is skipped without one)")
+	addAbstractFormatFlag(serveCmd)
+	serveCmd.Flags().StringVar(&latexMode, "latex", "unicode", "how to convert LaTeX in arXiv titles, abstracts and names: unicode, tex (keep $...$ math), mathml or none")
+	serveCmd.Flags().String("taxonomy", "", "path to an arXiv taxonomy JSON file created by \"papercut taxonomy refresh\" (defaults to the taxonomy built into papercut)")
+	serveCmd.Flags().StringVar(&retractionWatchPath, "retraction-watch", "", "Retraction Watch dataset CSV to check for retractions, corrections and expressions of concern as well as Crossref")
+	serveCmd.Flags().StringVar(&crosswalkPath, "crosswalk", "", "CSV file mapping arXiv categories and Crossref subjects to controlled vocabulary terms (columns: source,subject,label,uri,vocabulary)")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+)
+
+var (
+	// used for flags.
+	crosswalkPath string
+	unmappedPath  string
+)
+
+// loadCrosswalk reads the --crosswalk file
+// nil is returned if subjects shouldn't be mapped
+func loadCrosswalk() *subject.Crosswalk {
+	if crosswalkPath == "" {
+		return nil
+	}
+
+	c, err := subject.LoadCrosswalk(crosswalkPath)
+	if err != nil {
+		log.Fatalf("Unable to load crosswalk %s: %v", crosswalkPath, err)
+	}
+
+	return c
+}
+
+// mapSubject returns the terms for a subject from source
+// subjects the crosswalk has no mapping for are returned as is
+func mapSubject(c *subject.Crosswalk, source, s, fallback string) []subject.Term {
+	if c == nil {
+		return []subject.Term{{Label: fallback}}
+	}
+	terms, ok := c.Map(source, s)
+	if !ok {
+		return []subject.Term{{Label: fallback}}
+	}
+
+	return terms
+}
+
+// writeUnmapped writes the subjects that weren't in the crosswalk to --unmapped
+func writeUnmapped(c *subject.Crosswalk) {
+	if c == nil || unmappedPath == "" {
+		return
+	}
+
+	f, err := os.Create(unmappedPath)
+	if err != nil {
+		log.Printf("Unable to create %s: %v", unmappedPath, err)
+		return
+	}
+	defer f.Close()
+
+	err = c.WriteUnmapped(f)
+	if err != nil {
+		log.Printf("Unable to write %s: %v", unmappedPath, err)
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/sword"
+	"github.com/spf13/cobra"
+)
+
+var (
+	swordCmd = &cobra.Command{
+		Use:   "sword",
+		Short: "Deposit articles in a repository with SWORD v2",
+		Long: `Deposit the articles in a papercut CSV in an institutional repository's collection with SWORD v2
+e.g. DSpace's SWORD v2 endpoint.
+
+Each article's metadata is deposited as Dublin Core in an Atom entry, its PDF is added,
+and the deposit is completed so it enters the repository's workflow unless --in-progress is given.
+
+deposit_id is the ID in the deposit receipt and deposit_url its Edit-IRI, or its page in the repository when the server says where that is.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			collection, err := cmd.Flags().GetString("collection")
+			if err != nil {
+				log.Fatal(err)
+			}
+			username, err := cmd.Flags().GetString("username")
+			if err != nil {
+				log.Fatal(err)
+			}
+			password, err := cmd.Flags().GetString("password")
+			if err != nil {
+				log.Fatal(err)
+			}
+			onBehalfOf, err := cmd.Flags().GetString("on-behalf-of")
+			if err != nil {
+				log.Fatal(err)
+			}
+			inProgress, err := cmd.Flags().GetBool("in-progress")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if collection == "" {
+				log.Fatal("--collection is required")
+			}
+			if password == "" {
+				password = os.Getenv("SWORD_PASSWORD")
+			}
+			c := sword.Credentials{
+				Username:   username,
+				Password:   password,
+				OnBehalfOf: onBehalfOf,
+			}
+
+			runDeposit(cmd, func(a depositArticle) (depositResult, error) {
+				r, err := sword.DepositFile(collection, swordEntry(a), a.PDF, !inProgress, c)
+				if r == nil {
+					return depositResult{}, err
+				}
+				d := depositResult{ID: r.ID, URL: r.URL(), State: "submitted"}
+				if d.URL == "" {
+					d.URL = r.EditIRI()
+				}
+				if inProgress {
+					d.State = "draft"
+				}
+
+				return d, err
+			})
+		},
+	}
+)
+
+// swordEntry maps an article onto the Dublin Core of a SWORD deposit
+func swordEntry(a depositArticle) sword.Entry {
+	e := sword.Entry{
+		Title:     a.Title,
+		Abstract:  a.Abstract,
+		Creators:  a.Authors,
+		Issued:    a.Date,
+		Publisher: a.Publisher,
+		Rights:    a.Rights,
+		Subjects:  a.Subjects,
+	}
+	if a.DOI != "" {
+		e.Identifiers = append(e.Identifiers, "https://doi.org/"+a.DOI)
+	}
+	if a.Journal != "" {
+		citation := []string{a.Journal}
+		if a.Volume != "" {
+			citation = append(citation, "vol. "+a.Volume)
+		}
+		if a.Issue != "" {
+			citation = append(citation, "no. "+a.Issue)
+		}
+		if a.Pages != "" {
+			citation = append(citation, "pp. "+a.Pages)
+		}
+		e.Citation = strings.Join(citation, ", ")
+	}
+
+	return e
+}
+
+func init() {
+	depositCmd.AddCommand(swordCmd)
+
+	addDepositFlags(swordCmd)
+	swordCmd.Flags().String("collection", "", "the SWORD collection URL (Col-IRI) to deposit in, from the server's service document")
+	swordCmd.Flags().String("username", "", "username to deposit as")
+	swordCmd.Flags().String("password", "", "password to deposit with (defaults to the SWORD_PASSWORD environment variable)")
+	swordCmd.Flags().String("on-behalf-of", "", "user to deposit on behalf of, for servers that allow mediated deposit")
+	swordCmd.Flags().Bool("in-progress", false, "leave the deposits in progress instead of completing them")
+}
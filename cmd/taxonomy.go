@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	taxonomyCmd = &cobra.Command{
+		Use:   "taxonomy",
+		Short: "Manage the arXiv category taxonomy",
+		Long: `Manage the arXiv category taxonomy.
+
+papercut ships with a copy of arXiv's group, archive and category hierarchy
+which is used to turn arXiv categories into subject terms.`,
+	}
+
+	taxonomyRefreshCmd = &cobra.Command{
+		Use:   "refresh",
+		Short: "Update the arXiv taxonomy from arxiv.org",
+		Long: `Update the arXiv taxonomy from arxiv.org.
+
+The updated taxonomy is written to a JSON file
+that can be passed to "papercut search arxiv --taxonomy".`,
+		Run: func(cmd *cobra.Command, args []string) {
+			input, err := cmd.Flags().GetString("input")
+			if err != nil {
+				log.Fatal(err)
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				log.Fatal(err)
+			}
+			url, err := cmd.Flags().GetString("url")
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			taxonomy, err := arxiv.LoadTaxonomy(input)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			body, err := arxiv.GetTaxonomyPage(url)
+			if err != nil {
+				log.Fatalf("Unable to fetch %s: %v", url, err)
+			}
+			added := taxonomy.Refresh(body)
+
+			content, err := json.MarshalIndent(taxonomy, "", "  ")
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = os.WriteFile(output, content, 0644)
+			if err != nil {
+				log.Fatalf("Unable to write %s: %v", output, err)
+			}
+			log.Printf("Wrote taxonomy version %s to %s (%d new categories)\n", taxonomy.Version, output, added)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(taxonomyCmd)
+	taxonomyCmd.AddCommand(taxonomyRefreshCmd)
+
+	taxonomyRefreshCmd.Flags().StringP("url", "u", "https://arxiv.org/category_taxonomy", "The arXiv category taxonomy page")
+	taxonomyRefreshCmd.Flags().StringP("input", "i", "", "taxonomy JSON file to update (defaults to the taxonomy built into papercut)")
+	taxonomyRefreshCmd.Flags().StringP("output", "o", "arxiv-taxonomy.json", "path to write the updated taxonomy to")
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+	"github.com/lehigh-university-libraries/papercut/pkg/crossref"
+	"github.com/lehigh-university-libraries/papercut/pkg/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// crossrefLimiter spaces out requests to Crossref's REST API
+	crossrefLimiter = ratelimit.New(crossref.RequestDelay)
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Run harvest jobs on a schedule",
+		Long: `Run the harvest jobs in a config file on a schedule.
+
+Jobs search arXiv, find the works Crossref has for a list of ORCID iDs,
+or search Crossref by affiliation and filters. Each job runs every interval
+and only fetches works submitted (arXiv) or indexed (Crossref) since its last
+successful harvest, which is remembered in the state file.
+
+Every harvest writes a CSV and a run summary to the config's output directory.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			configPath, err := cmd.Flags().GetString("config")
+			if err != nil {
+				log.Fatal(err)
+			}
+			statePath, err := cmd.Flags().GetString("state")
+			if err != nil {
+				log.Fatal(err)
+			}
+			once, err := cmd.Flags().GetBool("once")
+			if err != nil {
+				log.Fatal(err)
+			}
+			worksURL, err := cmd.Flags().GetString("crossref-url")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if configPath == "" {
+				log.Fatal("--config is required")
+			}
+
+			config, err := watch.LoadConfig(configPath)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := os.MkdirAll(config.Output, 0755); err != nil {
+				log.Fatal(err)
+			}
+
+			for {
+				state, err := watch.LoadState(statePath)
+				if err != nil {
+					log.Fatal(err)
+				}
+				for _, job := range config.Jobs {
+					s := state[job.Name]
+					now := time.Now()
+					if !job.Due(s, now) {
+						continue
+					}
+
+					log.Printf("Running %s", job.Name)
+					s.LastRun = now
+					output, err := runWatchJob(config, job, s, worksURL)
+					if err != nil {
+						log.Printf("%s failed: %v", job.Name, err)
+						s.LastError = err.Error()
+					} else {
+						log.Printf("%s finished", job.Name)
+						s.LastSuccess = now
+						s.LastError = ""
+						s.LastOutput = output
+					}
+					state[job.Name] = s
+					if err := state.Save(statePath); err != nil {
+						log.Fatalf("Unable to save %s: %v", statePath, err)
+					}
+				}
+
+				if once {
+					return
+				}
+				time.Sleep(time.Minute)
+			}
+		},
+	}
+)
+
+// runWatchJob harvests the works for a job since its last successful harvest
+// by running papercut search arxiv or papercut get doi, returning the CSV it wrote
+func runWatchJob(config *watch.Config, job watch.Job, s watch.JobState, worksURL string) (string, error) {
+	from := job.From(s)
+	base := filepath.Join(config.Output, fmt.Sprintf("%s-%s", safeName(job.Name), s.LastRun.Format("20060102-150405")))
+	common := []string{"--summary", base + "-summary.json", "--progress=false"}
+
+	var args []string
+	switch job.Type {
+	case watch.Arxiv:
+		args = append([]string{"search", "arxiv", "--query", watch.ArxivQuery(job.Query, from, s.LastRun)}, common...)
+	default:
+		dois := []string{}
+		for _, q := range job.CrossrefQueries(from, config.Mailto) {
+			found, err := crossref.GetWorkDois(worksURL, q, crossrefLimiter)
+			if err != nil {
+				return "", err
+			}
+			for _, d := range found {
+				if !utils.StrInSlice(d, dois) {
+					dois = append(dois, d)
+				}
+			}
+		}
+		log.Printf("Found %d works for %s", len(dois), job.Name)
+		if len(dois) == 0 {
+			return "", nil
+		}
+		doiFile := base + "-dois.txt"
+		if err := os.WriteFile(doiFile, []byte(strings.Join(dois, "\n")+"\n"), 0644); err != nil {
+			return "", err
+		}
+		args = append([]string{"get", "doi", "--file", doiFile}, common...)
+	}
+	args = append(args, job.Args...)
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	output := base + ".csv"
+	out, err := os.Create(output)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	c := exec.Command(exe, args...)
+	c.Stdout = out
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("papercut %s: %v", strings.Join(args[:2], " "), err)
+	}
+
+	return output, nil
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringP("config", "c", "", "path to a JSON file of harvest jobs")
+	watchCmd.Flags().String("state", "watch-state.json", "path to the file remembering when each job last ran")
+	watchCmd.Flags().Bool("once", false, "run the jobs that are due then exit (e.g. from cron) instead of running forever")
+	watchCmd.Flags().String("crossref-url", "https://api.crossref.org/works", "The Crossref works API url")
+}
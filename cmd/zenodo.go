@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/zenodo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	zenodoCmd = &cobra.Command{
+		Use:   "zenodo",
+		Short: "Deposit articles in Zenodo",
+		Long: `Deposit the articles in a papercut CSV in Zenodo with its REST API.
+
+Each article's PDF is uploaded to a new deposition with its title, abstract, authors,
+publication date, journal, subjects and license, and its DOI as the version of record.
+Depositions are left as drafts to be reviewed in Zenodo unless --publish is given.
+
+deposit_id is the Zenodo deposition ID and deposit_url its page, or its DOI once published.
+Use --sandbox to try deposits out in Zenodo's sandbox, which needs a token of its own.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := cmd.Flags().GetString("token")
+			if err != nil {
+				log.Fatal(err)
+			}
+			sandbox, err := cmd.Flags().GetBool("sandbox")
+			if err != nil {
+				log.Fatal(err)
+			}
+			publish, err := cmd.Flags().GetBool("publish")
+			if err != nil {
+				log.Fatal(err)
+			}
+			if token == "" {
+				token = os.Getenv("ZENODO_TOKEN")
+			}
+			if token == "" {
+				log.Fatal("--token or ZENODO_TOKEN is required")
+			}
+			if sandbox {
+				zenodo.URL = zenodo.SandboxURL
+			}
+
+			runDeposit(cmd, func(a depositArticle) (depositResult, error) {
+				d, err := zenodo.DepositFile(zenodoMetadata(a), a.PDF, publish, token)
+				if d == nil {
+					return depositResult{}, err
+				}
+				r := depositResult{ID: strconv.Itoa(d.ID), URL: d.Links.HTML, State: "draft"}
+				if d.Submitted {
+					r.URL, r.State = d.DOIURL, "published"
+				}
+
+				return r, err
+			})
+		},
+	}
+)
+
+// zenodoMetadata maps an article onto a Zenodo deposition
+// dates are padded to a full date, which Zenodo requires
+func zenodoMetadata(a depositArticle) zenodo.Metadata {
+	m := zenodo.Metadata{
+		UploadType:      "publication",
+		PublicationType: "article",
+		Title:           a.Title,
+		Description:     a.Abstract,
+		AccessRight:     "open",
+		License:         zenodo.LicenseID(a.Rights),
+		Keywords:        a.Subjects,
+		JournalTitle:    a.Journal,
+		JournalVolume:   a.Volume,
+		JournalIssue:    a.Issue,
+		JournalPages:    a.Pages,
+	}
+	if m.Description == "" {
+		// Zenodo won't take a deposition without a description
+		m.Description = a.Title
+	}
+	for _, name := range a.Authors {
+		m.Creators = append(m.Creators, zenodo.Creator{Name: name})
+	}
+	if date := strings.Split(a.Date, "-"); len(date[0]) == 4 {
+		for len(date) < 3 {
+			date = append(date, "01")
+		}
+		m.PublicationDate = strings.Join(date[:3], "-")
+	}
+	if a.DOI != "" {
+		m.RelatedIdentifiers = append(m.RelatedIdentifiers, zenodo.RelatedIdentifier{
+			Identifier: a.DOI,
+			Relation:   "isVersionOf",
+		})
+	}
+
+	return m
+}
+
+func init() {
+	depositCmd.AddCommand(zenodoCmd)
+
+	addDepositFlags(zenodoCmd)
+	zenodoCmd.Flags().StringVarP(&zenodo.URL, "url", "u", zenodo.URL, "The Zenodo API url")
+	zenodoCmd.Flags().String("token", "", "Zenodo personal access token with the deposit:write scope, and deposit:actions to publish (defaults to the ZENODO_TOKEN environment variable)")
+	zenodoCmd.Flags().Bool("sandbox", false, "deposit in the Zenodo sandbox at "+zenodo.SandboxURL+" instead of --url")
+	zenodoCmd.Flags().Bool("publish", false, "publish the depositions, which mints their DOIs and can't be undone")
+	zenodoCmd.MarkFlagsMutuallyExclusive("sandbox", "url")
+}
@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter spaces out requests to a service so they're at least interval apart
+// it's safe to share between goroutines e.g. the handlers of papercut serve
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func New(interval time.Duration) *Limiter {
+	return &Limiter{interval: interval}
+}
+
+// Wait blocks until it's our turn to make a request
+func (l *Limiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
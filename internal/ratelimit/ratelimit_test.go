@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	interval := 20 * time.Millisecond
+	l := New(interval)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Wait()
+		}()
+	}
+	wg.Wait()
+
+	// the first request goes straight away and the others wait their turn
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("expected 4 requests to take at least %v, took %v", 3*interval, elapsed)
+	}
+
+	// after a quiet spell the next request doesn't wait
+	time.Sleep(2 * interval)
+	start = time.Now()
+	l.Wait()
+	if elapsed := time.Since(start); elapsed > interval/2 {
+		t.Errorf("expected no wait after a quiet spell, waited %v", elapsed)
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
@@ -65,59 +67,144 @@ func MkTmpDir(d string) (string, error) {
 }
 
 func DownloadPdf(url, filePath string) error {
+	return DownloadFile(url, filePath, "application/pdf")
+}
+
+// DownloadFile saves url to filePath unless filePath already exists
+// the file is downloaded next to filePath and only renamed to it once the download succeeds
+// so a failed download doesn't leave a partial file behind that would never be retried
+// empty files, which older versions of papercut left when a download failed, are downloaded again
+func DownloadFile(url, filePath, acceptContentType string) error {
 	downloadDirectory := filepath.Dir(filePath)
 	if err := os.MkdirAll(downloadDirectory, 0755); err != nil {
 		fmt.Println("Error creating directory:", err)
 		return err
 	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		file, err := os.Create(filePath)
-		if err != nil {
-			fmt.Println("Error creating file:", err)
+	if info, err := os.Stat(filePath); os.IsNotExist(err) || (err == nil && info.Size() == 0) {
+		if err := download(url, filePath, acceptContentType); err != nil {
 			return err
 		}
-		defer file.Close()
+	}
 
-		client := &http.Client{
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-			},
-		}
+	time.Sleep(500 * time.Microsecond)
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			log.Println("Error creating request:", err)
-			return err
-		}
+	return nil
+}
 
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36")
-		req.Header.Set("Accept", "application/pdf")
-		req.Header.Set("Accept-Language", "en-US")
-		req.Header.Set("Connection", "keep-alive")
-		req.Header.Set("Cache-Control", "no-cache")
+func download(url, filePath, acceptContentType string) error {
+	file, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.part")
+	if err != nil {
+		fmt.Println("Error creating file:", err)
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
 
-		response, err := client.Do(req)
-		if err != nil {
-			log.Println("Error downloading PDF:", err)
-			return err
-		}
-		defer response.Body.Close()
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+	}
 
-		if response.StatusCode > 299 {
-			log.Printf("Error: HTTP status %d\n", response.StatusCode)
-			return fmt.Errorf("%s returned a non-200 status code: %d", url, response.StatusCode)
-		}
-		_, err = io.Copy(file, response.Body)
-		if err != nil {
-			log.Println("Error copying PDF content to file:", err)
-			return err
-		}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		log.Println("Error creating request:", err)
+		return err
 	}
 
-	time.Sleep(500 * time.Microsecond)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", acceptContentType)
+	req.Header.Set("Accept-Language", "en-US")
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Cache-Control", "no-cache")
 
-	return nil
+	response, err := client.Do(req)
+	if err != nil {
+		log.Println("Error downloading file:", err)
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode > 299 {
+		log.Printf("Error: HTTP status %d\n", response.StatusCode)
+		return fmt.Errorf("%s returned a non-200 status code: %d", url, response.StatusCode)
+	}
+	_, err = io.Copy(file, response.Body)
+	if err != nil {
+		log.Println("Error copying content to file:", err)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filePath)
+}
+
+// UserAgent identifies papercut to the APIs it harvests from
+const UserAgent = "papercut (+https://github.com/lehigh-university-libraries/papercut)"
+
+// Mailto is an email address added to the User-Agent of API requests so the API can get in touch
+// e.g. OpenAlex's polite pool
+var Mailto string
+
+// Fetch GETs an API URL and returns its body, adding headers like an API key to the request
+// errors leave out the URL's query, which can hold API keys and email addresses
+func Fetch(u string, headers map[string]string) ([]byte, error) {
+	endpoint, _, _ := strings.Cut(u, "?")
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request %s: %v", endpoint, err)
+	}
+	userAgent := UserAgent
+	if Mailto != "" {
+		userAgent = fmt.Sprintf("%s; mailto:%s)", strings.TrimSuffix(UserAgent, ")"), Mailto)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request %s: %v", endpoint, errors.Unwrap(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned a non-200 status code: %d", endpoint, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// nameParticles are the lowercase prefixes that belong to a family name e.g. van in Ludwig van Beethoven
+var nameParticles = []string{"da", "de", "del", "della", "der", "di", "du", "la", "le", "van", "von"}
+
+// nameSuffixes come after the given name when a name is inverted e.g. King, Martin Luther, Jr.
+var nameSuffixes = []string{"Jr", "Jr.", "Sr", "Sr.", "II", "III", "IV"}
+
+// InvertName turns a name written Given Family, like OpenAlex and DBLP write them, into Family, Given
+// names that already have a comma or are a single word are returned as they are
+func InvertName(name string) string {
+	name = strings.TrimSpace(name)
+	words := strings.Fields(name)
+	if strings.Contains(name, ",") || len(words) < 2 {
+		return name
+	}
+
+	suffix := ""
+	if last := words[len(words)-1]; StrInSlice(last, nameSuffixes) && len(words) > 2 {
+		suffix = ", " + last
+		words = words[:len(words)-1]
+	}
+	family := len(words) - 1
+	for family > 1 && StrInSlice(words[family-1], nameParticles) {
+		family--
+	}
+
+	return fmt.Sprintf("%s, %s%s", strings.Join(words[family:], " "), strings.Join(words[:family], " "), suffix)
 }
 
 func StrInSlice(s string, sl []string) bool {
@@ -161,17 +248,29 @@ func CheckCachedFile(d string) []byte {
 	return nil
 }
 
+// WriteCachedFile caches c as f
+// it's written to a temporary file that's renamed to f so requests being handled at the same time
+// e.g. by papercut serve, never read or write a half-written file
 func WriteCachedFile(f, c string) {
-	cacheFile, err := os.Create(f)
+	cacheFile, err := os.CreateTemp(filepath.Dir(f), "."+filepath.Base(f)+".*.part")
 	if err != nil {
 		fmt.Println("Error creating file:", err)
 		return
 	}
+	defer os.Remove(cacheFile.Name())
 	defer cacheFile.Close()
 
 	_, err = cacheFile.WriteString(c)
 	if err != nil {
 		log.Println("Error caching DOI JSON:", err)
+		return
+	}
+	if err := cacheFile.Close(); err != nil {
+		log.Println("Error caching DOI JSON:", err)
+		return
+	}
+	if err := os.Rename(cacheFile.Name(), f); err != nil {
+		log.Println("Error caching DOI JSON:", err)
 	}
 }
 
@@ -211,3 +310,27 @@ func getResult(url, acceptContentType string) ([]byte, error) {
 
 	return body, nil
 }
+
+// CopyFile copies src to dst, creating dst's directory if needed
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
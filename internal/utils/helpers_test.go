@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -123,6 +126,23 @@ func TestTrimToMaxLen(t *testing.T) {
 	}
 }
 
+func TestInvertName(t *testing.T) {
+	tests := map[string]string{
+		"Jane Smith":             "Smith, Jane",
+		"Mary Jane Smith":        "Smith, Mary Jane",
+		"Ludwig van Beethoven":   "van Beethoven, Ludwig",
+		"Martin Luther King Jr.": "King, Martin Luther, Jr.",
+		"Smith, Jane":            "Smith, Jane",
+		"Madonna":                "Madonna",
+		" Jane  Smith ":          "Smith, Jane",
+	}
+	for in, expected := range tests {
+		if got := InvertName(in); got != expected {
+			t.Errorf("InvertName(%q) = %q; want %q", in, got, expected)
+		}
+	}
+}
+
 func TestStrInSlice(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -146,3 +166,131 @@ func TestStrInSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "doi.json")
+	if err := os.WriteFile(src, []byte(`{"DOI":"10.1000/xyz123"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "doi", "2024", "10.1000_xyz123", "doi.json")
+	if err := CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile() returned an error: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"DOI":"10.1000/xyz123"}` {
+		t.Errorf("CopyFile() copied %q", got)
+	}
+
+	if err := CopyFile(filepath.Join(dir, "missing"), dst); err == nil {
+		t.Error("CopyFile() expected an error for a missing file")
+	}
+}
+
+func TestWriteCachedFile(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "doi.json")
+	contents := []string{strings.Repeat("a", 1<<16), strings.Repeat("b", 1<<16)}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			WriteCachedFile(f, contents[i%2])
+		}()
+	}
+	wg.Wait()
+
+	got := string(CheckCachedFile(f))
+	if got != contents[0] && got != contents[1] {
+		t.Errorf("WriteCachedFile() left a mix of writes (%d bytes)", len(got))
+	}
+	if files, _ := os.ReadDir(filepath.Dir(f)); len(files) != 1 {
+		t.Errorf("WriteCachedFile() left %d files behind", len(files))
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	fail := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	pdf := filepath.Join(dir, "papers", "paper.pdf")
+	if err := DownloadFile(ts.URL, pdf, "application/pdf"); err == nil {
+		t.Error("DownloadFile() expected an error for a 500")
+	}
+	if _, err := os.Stat(pdf); !os.IsNotExist(err) {
+		t.Errorf("DownloadFile() left a file behind after a failed download: %v", err)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(pdf)); len(files) != 0 {
+		t.Errorf("DownloadFile() left %d partial files behind", len(files))
+	}
+
+	fail = false
+	if err := DownloadFile(ts.URL, pdf, "application/pdf"); err != nil {
+		t.Fatalf("DownloadFile() returned an error retrying: %v", err)
+	}
+	got, err := os.ReadFile(pdf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "%PDF-1.4" {
+		t.Errorf("DownloadFile() downloaded %q", got)
+	}
+
+	empty := filepath.Join(dir, "papers", "empty.pdf")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := DownloadFile(ts.URL, empty, "application/pdf"); err != nil {
+		t.Fatalf("DownloadFile() returned an error replacing an empty file: %v", err)
+	}
+	if got, _ := os.ReadFile(empty); string(got) != "%PDF-1.4" {
+		t.Errorf("DownloadFile() expected an empty file to be downloaded again, got %q", got)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.Header.Get("User-Agent") + "|" + r.Header.Get("x-api-key")))
+	}))
+	defer ts.Close()
+
+	body, err := Fetch(ts.URL+"/works", map[string]string{"x-api-key": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != UserAgent+"|secret" {
+		t.Errorf("Fetch() sent %q", body)
+	}
+
+	Mailto = "librarian@example.edu"
+	defer func() { Mailto = "" }()
+	body, err = Fetch(ts.URL+"/works", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "mailto:librarian@example.edu") {
+		t.Errorf("Fetch() didn't send the mailto in %q", body)
+	}
+
+	_, err = Fetch(ts.URL+"/missing?api_key=secret", nil)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Fetch() returned %v, expected an error without the query", err)
+	}
+}
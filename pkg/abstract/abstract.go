@@ -0,0 +1,358 @@
+package abstract
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+type Format string
+
+const (
+	// Raw leaves the abstract as the source provided it
+	Raw      Format = "raw"
+	HTML     Format = "html"
+	Text     Format = "text"
+	Markdown Format = "markdown"
+)
+
+var (
+	whitespace = regexp.MustCompile(`\s+`)
+	mathML     = regexp.MustCompile(`(?s)<math\b.*?</math>`)
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Raw, HTML, Text, Markdown:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unknown abstract format %q (expected raw, html, text or markdown)", s)
+}
+
+// node is an element (or text when name is empty) in a JATS abstract
+type node struct {
+	name     string
+	attrs    []xml.Attr
+	text     string
+	children []*node
+}
+
+// FromJATS converts a JATS abstract e.g. from Crossref into the given format
+// inline TeX (<jats:tex-math>) is kept as $...$ and MathML is kept for HTML
+func FromJATS(s string, f Format) string {
+	if f == Raw || strings.TrimSpace(s) == "" {
+		return s
+	}
+
+	root, err := parseJATS(s)
+	if err != nil {
+		// not well formed, so treat it as text
+		root = &node{children: []*node{{text: s}}}
+	}
+
+	var b strings.Builder
+	for _, p := range blocks(root) {
+		var content string
+		switch p.name {
+		case "title":
+			content = inline(p, f)
+			if strings.EqualFold(content, "abstract") {
+				continue
+			}
+			switch f {
+			case HTML:
+				content = fmt.Sprintf("<h3>%s</h3>", content)
+			case Markdown:
+				content = "### " + content
+			}
+		case "list":
+			items := []string{}
+			for _, item := range p.children {
+				if item.name != "list-item" {
+					continue
+				}
+				switch f {
+				case HTML:
+					items = append(items, fmt.Sprintf("<li>%s</li>", inline(item, f)))
+				default:
+					items = append(items, "- "+inline(item, f))
+				}
+			}
+			content = strings.Join(items, "\n")
+			if f == HTML {
+				content = fmt.Sprintf("<ul>%s</ul>", strings.Join(items, ""))
+			}
+		default:
+			content = inline(p, f)
+			if content == "" {
+				continue
+			}
+			if f == HTML {
+				content = fmt.Sprintf("<p>%s</p>", content)
+			}
+		}
+		if b.Len() > 0 {
+			if f == HTML {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(content)
+	}
+
+	return b.String()
+}
+
+// FromArxiv converts an arXiv summary into the given format.
+// arXiv wraps summaries at a fixed width and starts new paragraphs with an indented line,
+// so lines are unwrapped into paragraphs. LaTeX and MathML are left as is.
+func FromArxiv(s string, f Format) string {
+	if f == Raw {
+		return s
+	}
+
+	paragraphs := []string{}
+	current := []string{}
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = []string{}
+		}
+	}
+	for _, line := range strings.Split(strings.Trim(s, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		if len(current) > 0 && strings.TrimLeft(line, " \t") != line {
+			flush()
+		}
+		current = append(current, whitespace.ReplaceAllString(trimmed, " "))
+	}
+	flush()
+
+	if f == HTML {
+		for i, p := range paragraphs {
+			paragraphs[i] = fmt.Sprintf("<p>%s</p>", escapeText(p))
+		}
+		return strings.Join(paragraphs, "\n")
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// escapeText escapes HTML special characters outside of any MathML elements
+func escapeText(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mathML.FindAllStringIndex(s, -1) {
+		b.WriteString(html.EscapeString(s[last:m[0]]))
+		b.WriteString(s[m[0]:m[1]])
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(s[last:]))
+
+	return b.String()
+}
+
+func parseJATS(s string) (*node, error) {
+	d := xml.NewDecoder(strings.NewReader("<root>" + s + "</root>"))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	var root *node
+	stack := []*node{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, attrs: t.Attr}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return root, nil
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &node{text: string(t)})
+			}
+		}
+	}
+}
+
+// blocks flattens sections into a list of block level nodes (titles, paragraphs and lists)
+// text directly inside a section is grouped into its own paragraph
+func blocks(n *node) []*node {
+	result := []*node{}
+	loose := &node{name: "p"}
+	flush := func() {
+		if len(loose.children) > 0 {
+			result = append(result, loose)
+			loose = &node{name: "p"}
+		}
+	}
+	for _, c := range n.children {
+		switch c.name {
+		case "sec", "abstract", "trans-abstract":
+			flush()
+			result = append(result, blocks(c)...)
+		case "title", "p", "list":
+			flush()
+			result = append(result, c)
+		default:
+			loose.children = append(loose.children, c)
+		}
+	}
+	flush()
+
+	return result
+}
+
+// inline renders the contents of a block level node
+func inline(n *node, f Format) string {
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(renderInline(c, f))
+	}
+
+	return strings.TrimSpace(whitespace.ReplaceAllString(b.String(), " "))
+}
+
+func renderInline(n *node, f Format) string {
+	if n.name == "" {
+		if f == HTML {
+			return html.EscapeString(n.text)
+		}
+		return n.text
+	}
+
+	switch n.name {
+	case "tex-math":
+		tex := strings.TrimSpace(text(n))
+		tex = strings.TrimPrefix(strings.TrimSuffix(tex, "$"), "$")
+		tex = "$" + tex + "$"
+		if f == HTML {
+			return html.EscapeString(tex)
+		}
+		return tex
+	case "math":
+		if f == HTML {
+			return rawXML(n)
+		}
+		return text(n)
+	case "inline-formula", "disp-formula", "alternatives":
+		// prefer TeX over MathML when both are present
+		for _, c := range n.children {
+			if c.name == "tex-math" {
+				return renderInline(c, f)
+			}
+		}
+	}
+
+	content := inline(n, f)
+	if n.name == "p" || n.name == "list-item" {
+		return " " + content + " "
+	}
+
+	switch f {
+	case HTML:
+		switch n.name {
+		case "italic":
+			return "<em>" + content + "</em>"
+		case "bold":
+			return "<strong>" + content + "</strong>"
+		case "sup":
+			return "<sup>" + content + "</sup>"
+		case "sub":
+			return "<sub>" + content + "</sub>"
+		case "ext-link", "uri":
+			href := attr(n, "href")
+			if href == "" {
+				href = content
+			}
+			return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), content)
+		}
+	case Markdown:
+		switch n.name {
+		case "italic":
+			return "*" + content + "*"
+		case "bold":
+			return "**" + content + "**"
+		case "sup":
+			return "<sup>" + content + "</sup>"
+		case "sub":
+			return "<sub>" + content + "</sub>"
+		case "ext-link", "uri":
+			href := attr(n, "href")
+			if href == "" {
+				href = content
+			}
+			return fmt.Sprintf("[%s](%s)", content, href)
+		}
+	}
+
+	return content
+}
+
+// text returns all the character data below a node
+func text(n *node) string {
+	if n.name == "" {
+		return n.text
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(text(c))
+	}
+
+	return b.String()
+}
+
+// rawXML serializes a node (i.e. MathML) back to XML without namespace prefixes
+func rawXML(n *node) string {
+	if n.name == "" {
+		return html.EscapeString(n.text)
+	}
+	var b strings.Builder
+	b.WriteString("<" + n.name)
+	for _, a := range n.attrs {
+		// drop namespaced attributes, including the namespace declarations
+		if a.Name.Space != "" || a.Name.Local == "xmlns" {
+			continue
+		}
+		fmt.Fprintf(&b, ` %s="%s"`, a.Name.Local, html.EscapeString(a.Value))
+	}
+	b.WriteString(">")
+	for _, c := range n.children {
+		b.WriteString(rawXML(c))
+	}
+	b.WriteString("</" + n.name + ">")
+
+	return b.String()
+}
+
+func attr(n *node, name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,101 @@
+package abstract_test
+
+import (
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+)
+
+const jats = `<jats:title>Abstract</jats:title><jats:sec><jats:title>Background</jats:title><jats:p>We study <jats:italic>E. coli</jats:italic> growth at <jats:inline-formula><jats:tex-math>\alpha &lt; 1</jats:tex-math></jats:inline-formula> &amp; more.</jats:p></jats:sec>
+<jats:sec><jats:title>Results</jats:title><jats:p>H<jats:sub>2</jats:sub>O is <jats:bold>wet</jats:bold>.</jats:p></jats:sec>`
+
+func TestFromJATS(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   abstract.Format
+		expected string
+	}{
+		{
+			name:     "raw",
+			input:    jats,
+			format:   abstract.Raw,
+			expected: jats,
+		},
+		{
+			name:     "html",
+			input:    jats,
+			format:   abstract.HTML,
+			expected: "<h3>Background</h3>\n<p>We study <em>E. coli</em> growth at $\\alpha &lt; 1$ &amp; more.</p>\n<h3>Results</h3>\n<p>H<sub>2</sub>O is <strong>wet</strong>.</p>",
+		},
+		{
+			name:     "text",
+			input:    jats,
+			format:   abstract.Text,
+			expected: "Background\n\nWe study E. coli growth at $\\alpha < 1$ & more.\n\nResults\n\nH2O is wet.",
+		},
+		{
+			name:     "markdown",
+			input:    jats,
+			format:   abstract.Markdown,
+			expected: "### Background\n\nWe study *E. coli* growth at $\\alpha < 1$ & more.\n\n### Results\n\nH<sub>2</sub>O is **wet**.",
+		},
+		{
+			name:     "plain text input",
+			input:    "An abstract without any markup.",
+			format:   abstract.HTML,
+			expected: "<p>An abstract without any markup.</p>",
+		},
+		{
+			name:     "mathml",
+			input:    `<jats:p>Let <mml:math xmlns:mml="http://www.w3.org/1998/Math/MathML"><mml:mi>x</mml:mi></mml:math> be</jats:p>`,
+			format:   abstract.HTML,
+			expected: "<p>Let <math><mi>x</mi></math> be</p>",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := abstract.FromJATS(test.input, test.format)
+			if got != test.expected {
+				t.Errorf("FromJATS(%s) = %q; want %q", test.format, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestFromArxiv(t *testing.T) {
+	summary := `  We present a method for
+computing $x_1 < y$ quickly.
+  In a second paragraph we
+discuss results.
+`
+	tests := []struct {
+		format   abstract.Format
+		expected string
+	}{
+		{abstract.Text, "We present a method for computing $x_1 < y$ quickly.\n\nIn a second paragraph we discuss results."},
+		{abstract.HTML, "<p>We present a method for computing $x_1 &lt; y$ quickly.</p>\n<p>In a second paragraph we discuss results.</p>"},
+		{abstract.Raw, summary},
+	}
+	mathML := `<math xmlns="http://www.w3.org/1998/Math/MathML"><mi>x</mi></math>`
+	if got := abstract.FromArxiv("Let "+mathML+" < 1", abstract.HTML); got != "<p>Let "+mathML+" &lt; 1</p>" {
+		t.Errorf("Expected MathML to be left unescaped, got %q", got)
+	}
+
+	for _, test := range tests {
+		got := abstract.FromArxiv(summary, test.format)
+		if got != test.expected {
+			t.Errorf("FromArxiv(%s) = %q; want %q", test.format, got, test.expected)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := abstract.ParseFormat("HTML"); err != nil || f != abstract.HTML {
+		t.Errorf("ParseFormat(HTML) = %v, %v", f, err)
+	}
+	if _, err := abstract.ParseFormat("pdf"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
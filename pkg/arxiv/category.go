@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 )
 
 type Category struct {
@@ -12,32 +11,17 @@ type Category struct {
 	Scheme string `xml:"scheme,attr"`
 }
 
-func GetCategoryLabels() map[string]string {
-	url := "https://arxiv.org/category_taxonomy"
+// GetTaxonomyPage fetches arXiv's category taxonomy HTML page
+func GetTaxonomyPage(url string) ([]byte, error) {
 	resp, err := http.Get(url)
 	if err != nil {
-		fmt.Println("Error fetching URL: ", err)
-		return nil
+		return nil, err
 	}
 	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error reading response body: ", err)
-		return nil
-	}
-
-	return TransformLabels(body)
-}
-
-func TransformLabels(body []byte) map[string]string {
-	pattern := `<h4>([a-z\-]+(\.[A-Z]+)?) <span>\(([^)]+)\)</span></h4>`
-	re := regexp.MustCompile(pattern)
-	matches := re.FindAllStringSubmatch(string(body), -1)
 
-	categories := map[string]string{}
-	for _, match := range matches {
-		categories[match[1]] = match[3]
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned a non-200 status code: %d", url, resp.StatusCode)
 	}
 
-	return categories
+	return io.ReadAll(resp.Body)
 }
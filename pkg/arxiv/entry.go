@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/latex"
 )
 
 type Entry struct {
@@ -45,6 +47,19 @@ func (e *Entry) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// ConvertLatex replaces LaTeX accents and macros in the entry's text with Unicode
+func (e *Entry) ConvertLatex(mode latex.MathMode) {
+	e.Title = latex.ToUnicode(e.Title, mode)
+	e.Summary = latex.ToUnicode(e.Summary, mode)
+	e.Comment = latex.ToUnicode(e.Comment, mode)
+	// copy the authors so entries sharing them with a feed aren't changed too
+	e.Authors = append([]Author{}, e.Authors...)
+	for i, a := range e.Authors {
+		e.Authors[i].Name = latex.ToUnicode(a.Name, mode)
+		e.Authors[i].Affiliation = latex.ToUnicode(a.Affiliation, mode)
+	}
+}
+
 // Helper function to clean string (remove new lines and trim whitespace)
 func cleanString(s string) string {
 	cs := strings.TrimSpace(strings.ReplaceAll(s, "\n", ""))
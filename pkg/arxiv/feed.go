@@ -3,8 +3,8 @@ package arxiv
 import (
 	"encoding/xml"
 	"fmt"
-	"io"
-	"net/http"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
 )
 
 type Feed struct {
@@ -22,18 +22,11 @@ type Feed struct {
 func GetResults(url string) (Feed, error) {
 	var result Feed
 
-	resp, err := http.Get(url)
+	body, err := utils.Fetch(url, nil)
 	if err != nil {
 		fmt.Println("Error requesting XML data:", err)
 		return result, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error reading response body:", err)
-		return result, err
-	}
 
 	err = xml.Unmarshal(body, &result)
 	if err != nil {
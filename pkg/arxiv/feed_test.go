@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+	"github.com/lehigh-university-libraries/papercut/pkg/latex"
 )
 
 func TestGetResults(t *testing.T) {
@@ -44,3 +45,15 @@ func TestGetResults(t *testing.T) {
 		t.Errorf("Expected totalResults to be 2, got %d", feed.TotalResults)
 	}
 }
+
+func TestConvertLatex(t *testing.T) {
+	authors := []arxiv.Author{{Name: `Kurt G\"odel`}}
+	e := arxiv.Entry{Title: `G\"odel's theorem`, Authors: authors}
+	e.ConvertLatex(latex.Unicode)
+	if e.Title != "Gödel's theorem" || e.Authors[0].Name != "Kurt Gödel" {
+		t.Errorf("expected the LaTeX to be converted, got %q by %q", e.Title, e.Authors[0].Name)
+	}
+	if authors[0].Name != `Kurt G\"odel` {
+		t.Errorf("expected the caller's authors to be left alone, got %q", authors[0].Name)
+	}
+}
@@ -3,9 +3,9 @@ package arxiv
 import (
 	"encoding/xml"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
 )
 
 // OAIResponse represents the XML structure of the OAI response
@@ -34,14 +34,7 @@ type OaiAuthor struct {
 }
 
 func GetOaiRecord(url string) map[string]string {
-	resp, err := http.Get(url)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return nil
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := utils.Fetch(url, nil)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return nil
@@ -0,0 +1,76 @@
+package arxiv
+
+import (
+	"time"
+)
+
+// RequestDelay is the pause arXiv asks for between API requests
+const RequestDelay = 3 * time.Second
+
+// AveragePdfSize is a rough size of an arXiv PDF, used to estimate how much a harvest will download
+const AveragePdfSize int64 = 2 << 20
+
+// HarvestOptions describe the requests made for a query's results
+type HarvestOptions struct {
+	// PageSize is the max_results of each API request
+	PageSize int
+	// RequestsPerEntry counts the requests made for each result e.g. the OAI record and the PDF
+	RequestsPerEntry int
+	// DelaysPerEntry counts the requests for each result that wait RequestDelay first
+	DelaysPerEntry int
+}
+
+// Estimate is the work needed to harvest every result of a query
+type Estimate struct {
+	Results  int
+	Pages    int
+	Requests int
+	PDFs     int
+	Bytes    int64
+	Duration time.Duration
+}
+
+// EstimateHarvest estimates the work to harvest a query from the first page of its results
+// the share of first page entries with a PDF is assumed to hold for the remaining pages
+func EstimateHarvest(first Feed, opts HarvestOptions) Estimate {
+	e := Estimate{
+		Results: first.TotalResults - first.StartIndex,
+	}
+	if e.Results <= 0 {
+		return Estimate{Pages: 1, Requests: 1, Duration: RequestDelay}
+	}
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = e.Results
+	}
+	e.Pages = (e.Results + pageSize - 1) / pageSize
+
+	withPdf := 0
+	for _, entry := range first.Entries {
+		if entry.PDF != "" {
+			withPdf++
+		}
+	}
+	if len(first.Entries) > 0 {
+		e.PDFs = e.Results * withPdf / len(first.Entries)
+	}
+	e.Bytes = int64(e.PDFs) * AveragePdfSize
+
+	e.Requests = e.Pages + e.Results*opts.RequestsPerEntry
+	e.Duration = time.Duration(e.Pages+e.Results*opts.DelaysPerEntry) * RequestDelay
+
+	return e
+}
+
+// Add sums two estimates e.g. for the total of every query in a harvest
+func (e Estimate) Add(o Estimate) Estimate {
+	return Estimate{
+		Results:  e.Results + o.Results,
+		Pages:    e.Pages + o.Pages,
+		Requests: e.Requests + o.Requests,
+		PDFs:     e.PDFs + o.PDFs,
+		Bytes:    e.Bytes + o.Bytes,
+		Duration: e.Duration + o.Duration,
+	}
+}
@@ -0,0 +1,60 @@
+package arxiv_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+)
+
+func TestEstimateHarvest(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    arxiv.Feed
+		opts     arxiv.HarvestOptions
+		expected arxiv.Estimate
+	}{
+		{
+			name: "several pages",
+			first: arxiv.Feed{
+				TotalResults: 25,
+				ItemsPerPage: 10,
+				Entries:      []arxiv.Entry{{PDF: "a"}, {PDF: "b"}, {PDF: "c"}, {PDF: "d"}, {}},
+			},
+			opts: arxiv.HarvestOptions{PageSize: 10, RequestsPerEntry: 2, DelaysPerEntry: 1},
+			expected: arxiv.Estimate{
+				Results:  25,
+				Pages:    3,
+				Requests: 53,
+				PDFs:     20,
+				Bytes:    20 * arxiv.AveragePdfSize,
+				Duration: 28 * 3 * time.Second,
+			},
+		},
+		{
+			name:     "offset",
+			first:    arxiv.Feed{TotalResults: 25, StartIndex: 20, Entries: []arxiv.Entry{{PDF: "a"}}},
+			opts:     arxiv.HarvestOptions{PageSize: 10, RequestsPerEntry: 3, DelaysPerEntry: 2},
+			expected: arxiv.Estimate{Results: 5, Pages: 1, Requests: 16, PDFs: 5, Bytes: 5 * arxiv.AveragePdfSize, Duration: 11 * 3 * time.Second},
+		},
+		{
+			name:     "no results",
+			first:    arxiv.Feed{},
+			opts:     arxiv.HarvestOptions{PageSize: 10, RequestsPerEntry: 2, DelaysPerEntry: 1},
+			expected: arxiv.Estimate{Pages: 1, Requests: 1, Duration: 3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := arxiv.EstimateHarvest(tt.first, tt.opts)
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	total := tests[0].expected.Add(tests[1].expected)
+	if total.Results != 30 || total.Duration != 39*3*time.Second {
+		t.Errorf("unexpected total %+v", total)
+	}
+}
@@ -0,0 +1,69 @@
+package arxiv
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// SourceURL is where the LaTeX source (or whatever was submitted) of a version of a paper can be downloaded
+func SourceURL(id string, version int) string {
+	return fmt.Sprintf("https://arxiv.org/e-print/%sv%d", id, version)
+}
+
+var ancillaryPattern = regexp.MustCompile(`href="(/src/[^"]+/anc/[^"]+)"`)
+
+// GetAncillaryFiles lists the URLs of the ancillary files linked from a paper's abstract page
+// e.g. https://arxiv.org/abs/2101.00001v2
+func GetAncillaryFiles(absURL string) ([]string, error) {
+	body, err := utils.Fetch(absURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseAncillaryFiles(body, absURL)
+}
+
+// ParseAncillaryFiles finds the ancillary file links on an abstract page
+// resolving them against the page's URL
+func ParseAncillaryFiles(body []byte, absURL string) ([]string, error) {
+	base, err := url.Parse(absURL)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	seen := map[string]bool{}
+	for _, match := range ancillaryPattern.FindAllSubmatch(body, -1) {
+		ref, err := url.Parse(string(match[1]))
+		if err != nil {
+			continue
+		}
+		u := base.ResolveReference(ref).String()
+		if !seen[u] {
+			seen[u] = true
+			files = append(files, u)
+		}
+	}
+
+	return files, nil
+}
+
+// AncillaryPath returns the path of an ancillary file relative to the paper's anc directory
+// e.g. data/results.csv for https://arxiv.org/src/2101.00001v2/anc/data/results.csv
+func AncillaryPath(fileURL string) string {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return path.Base(fileURL)
+	}
+	_, rel, found := strings.Cut(u.Path, "/anc/")
+	if !found {
+		return path.Base(u.Path)
+	}
+
+	return path.Clean("/" + rel)[1:]
+}
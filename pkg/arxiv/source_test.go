@@ -0,0 +1,42 @@
+package arxiv_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+)
+
+func TestParseAncillaryFiles(t *testing.T) {
+	body := []byte(`<div class="ancillary">
+		<ul>
+			<li><a href="/src/2101.00001v2/anc/data.csv" class="anc-file">data.csv</a></li>
+			<li><a href="/src/2101.00001v2/anc/code/run%20all.py" class="anc-file">code/run all.py</a></li>
+			<li><a href="/src/2101.00001v2/anc/data.csv" class="anc-file">data.csv</a></li>
+		</ul>
+	</div>
+	<a href="/pdf/2101.00001v2">PDF</a>`)
+
+	files, err := arxiv.ParseAncillaryFiles(body, "https://arxiv.org/abs/2101.00001v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"https://arxiv.org/src/2101.00001v2/anc/data.csv",
+		"https://arxiv.org/src/2101.00001v2/anc/code/run%20all.py",
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+
+	tests := map[string]string{
+		files[0]: "data.csv",
+		files[1]: "code/run all.py",
+		"https://arxiv.org/src/2101.00001v2/anc/../../x": "x",
+	}
+	for url, expected := range tests {
+		if got := arxiv.AncillaryPath(url); got != expected {
+			t.Errorf("AncillaryPath(%q): expected %q, got %q", url, expected, got)
+		}
+	}
+}
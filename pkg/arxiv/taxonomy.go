@@ -0,0 +1,190 @@
+package arxiv
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:embed taxonomy.json
+var embeddedTaxonomy []byte
+
+// Taxonomy is arXiv's group -> archive -> category hierarchy
+// e.g. Physics -> Astrophysics -> astro-ph.SR (Solar and Stellar Astrophysics)
+type Taxonomy struct {
+	Version string  `json:"version"`
+	Groups  []Group `json:"groups"`
+	// Aliases maps deprecated or duplicate category IDs to their canonical ID
+	// e.g. math.IT -> cs.IT
+	Aliases map[string]string `json:"aliases"`
+
+	index map[string]subjectPath
+}
+
+type Group struct {
+	Name     string    `json:"name"`
+	Archives []Archive `json:"archives"`
+}
+
+type Archive struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	Categories []TaxonomyCategory `json:"categories"`
+}
+
+type TaxonomyCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type subjectPath struct {
+	group    string
+	archive  string
+	category string
+}
+
+// LoadTaxonomy reads a taxonomy JSON file
+// or, if path is empty, the taxonomy embedded in papercut
+func LoadTaxonomy(path string) (*Taxonomy, error) {
+	body := embeddedTaxonomy
+	if path != "" {
+		var err error
+		body, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var t Taxonomy
+	err := json.Unmarshal(body, &t)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal taxonomy: %v", err)
+	}
+	t.buildIndex()
+
+	return &t, nil
+}
+
+func (t *Taxonomy) buildIndex() {
+	t.index = map[string]subjectPath{}
+	for _, g := range t.Groups {
+		for _, a := range g.Archives {
+			for _, c := range a.Categories {
+				t.index[c.ID] = subjectPath{
+					group:    g.Name,
+					archive:  a.Name,
+					category: c.Name,
+				}
+			}
+		}
+	}
+}
+
+// Canonical resolves an alias to its canonical category ID
+func (t *Taxonomy) Canonical(term string) string {
+	if canonical, ok := t.Aliases[term]; ok {
+		return canonical
+	}
+
+	return term
+}
+
+// Subject returns the hierarchical subject term for a category
+// e.g. "Physics--Astrophysics--Solar and Stellar Astrophysics"
+// levels with the same name as their parent are collapsed
+// e.g. "Computer Science--Artificial Intelligence"
+func (t *Taxonomy) Subject(term string) (string, bool) {
+	p, ok := t.index[t.Canonical(term)]
+	if !ok {
+		return "", false
+	}
+
+	levels := []string{p.group}
+	for _, l := range []string{p.archive, p.category} {
+		if l != levels[len(levels)-1] {
+			levels = append(levels, l)
+		}
+	}
+
+	return strings.Join(levels, "--"), true
+}
+
+// Refresh updates the taxonomy with the category names on https://arxiv.org/category_taxonomy
+// categories that are new to the taxonomy are added to the archive matching their prefix
+// or a new archive in the group they're listed under
+func (t *Taxonomy) Refresh(body []byte) int {
+	groupPattern := regexp.MustCompile(`<h2 class="accordion-head">([^<]+)</h2>`)
+	groups := groupPattern.FindAllSubmatchIndex(body, -1)
+	categoryPattern := regexp.MustCompile(`<h4>([a-z\-]+(\.[A-Za-z\-]+)?) <span>\(([^)]+)\)</span></h4>`)
+
+	added := 0
+	for _, match := range categoryPattern.FindAllSubmatchIndex(body, -1) {
+		id := string(body[match[2]:match[3]])
+		name := string(body[match[6]:match[7]])
+
+		group := ""
+		for _, g := range groups {
+			if g[0] < match[0] {
+				group = strings.TrimSpace(string(body[g[2]:g[3]]))
+			}
+		}
+
+		if t.rename(id, name) {
+			continue
+		}
+		t.add(group, id, name)
+		added++
+	}
+	t.Version = time.Now().Format("2006-01-02")
+	t.buildIndex()
+
+	return added
+}
+
+// rename updates the name of an existing category
+func (t *Taxonomy) rename(id, name string) bool {
+	for i, g := range t.Groups {
+		for j, a := range g.Archives {
+			for k, c := range a.Categories {
+				if c.ID == id {
+					t.Groups[i].Archives[j].Categories[k].Name = name
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *Taxonomy) add(group, id, name string) {
+	archiveID := strings.Split(id, ".")[0]
+	for i, g := range t.Groups {
+		for j, a := range g.Archives {
+			if a.ID == archiveID {
+				t.Groups[i].Archives[j].Categories = append(a.Categories, TaxonomyCategory{ID: id, Name: name})
+				return
+			}
+		}
+	}
+
+	archive := Archive{
+		ID:         archiveID,
+		Name:       name,
+		Categories: []TaxonomyCategory{{ID: id, Name: name}},
+	}
+	for i, g := range t.Groups {
+		if g.Name == group {
+			t.Groups[i].Archives = append(g.Archives, archive)
+			return
+		}
+	}
+	if group == "" {
+		group = archiveID
+	}
+	t.Groups = append(t.Groups, Group{Name: group, Archives: []Archive{archive}})
+}
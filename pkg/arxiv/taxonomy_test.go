@@ -0,0 +1,65 @@
+package arxiv_test
+
+import (
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+)
+
+func TestTaxonomySubject(t *testing.T) {
+	taxonomy, err := arxiv.LoadTaxonomy("")
+	if err != nil {
+		t.Fatalf("Unable to load embedded taxonomy: %v", err)
+	}
+
+	// aliases like chao-dyn and math.IT map to the same subject as their canonical category
+	tests := map[string]string{
+		"cs.AI":          "Computer Science--Artificial Intelligence",
+		"astro-ph.SR":    "Physics--Astrophysics--Solar and Stellar Astrophysics",
+		"gr-qc":          "Physics--General Relativity and Quantum Cosmology",
+		"nlin.CD":        "Physics--Nonlinear Sciences--Chaotic Dynamics",
+		"chao-dyn":       "Physics--Nonlinear Sciences--Chaotic Dynamics",
+		"cs.IT":          "Computer Science--Information Theory",
+		"math.IT":        "Computer Science--Information Theory",
+		"physics.optics": "Physics--Optics",
+	}
+	for term, want := range tests {
+		got, ok := taxonomy.Subject(term)
+		if !ok || got != want {
+			t.Errorf("Subject(%s) = %q; want %q", term, got, want)
+		}
+	}
+
+	if got, ok := taxonomy.Subject("not.ACategory"); ok {
+		t.Errorf("Expected not.ACategory to be missing from the taxonomy, got %q", got)
+	}
+}
+
+func TestTaxonomyRefresh(t *testing.T) {
+	taxonomy, err := arxiv.LoadTaxonomy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := []byte(`<h2 class="accordion-head">Computer Science</h2>
+	<h4>cs.AI <span>(Artificial Intelligence Renamed)</span></h4>
+	<h4>cs.ZZ <span>(A New Category)</span></h4>
+	<h2 class="accordion-head">Physics</h2>
+	<h4>new-ph <span>(New Physics)</span></h4>`)
+
+	if added := taxonomy.Refresh(page); added != 2 {
+		t.Errorf("Expected 2 categories to be added, got %d", added)
+	}
+
+	tests := map[string]string{
+		"cs.AI":  "Computer Science--Artificial Intelligence Renamed",
+		"cs.ZZ":  "Computer Science--A New Category",
+		"new-ph": "Physics--New Physics",
+	}
+	for term, want := range tests {
+		got, ok := taxonomy.Subject(term)
+		if !ok || got != want {
+			t.Errorf("Subject(%s) = %q; want %q", term, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package arxiv
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// RawResponse represents an OAI response in the arXivRaw metadata format
+// which, unlike the arXiv format, lists every version of a paper
+type RawResponse struct {
+	XMLName  xml.Name  `xml:"OAI-PMH"`
+	ID       string    `xml:"GetRecord>record>metadata>arXivRaw>id"`
+	Versions []Version `xml:"GetRecord>record>metadata>arXivRaw>version"`
+}
+
+type Version struct {
+	Version    string `xml:"version,attr"`
+	Date       string `xml:"date"`
+	Size       string `xml:"size"`
+	SourceType string `xml:"source_type"`
+}
+
+var versionPattern = regexp.MustCompile(`^(.+?)v(\d+)$`)
+
+// SplitVersion splits an arXiv ID like 2101.00001v2 into its ID and version number
+// the version is 0 if the ID doesn't have one
+func SplitVersion(id string) (string, int) {
+	matches := versionPattern.FindStringSubmatch(id)
+	if matches == nil {
+		return id, 0
+	}
+	v, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return id, 0
+	}
+
+	return matches[1], v
+}
+
+// Number returns the version number e.g. 2 for v2
+func (v Version) Number() int {
+	_, n := SplitVersion("x" + v.Version)
+	return n
+}
+
+// submittedLayout is how arXiv writes submission dates, which is RFC 1123 without the day padded
+// e.g. Mon, 2 Apr 2007 19:18:42 GMT
+const submittedLayout = "Mon, _2 Jan 2006 15:04:05 MST"
+
+// Submitted returns when the version was submitted
+func (v Version) Submitted() (time.Time, error) {
+	return time.Parse(submittedLayout, v.Date)
+}
+
+// GetVersions fetches the version history for a paper from an arXivRaw OAI GetRecord URL
+func GetVersions(url string) ([]Version, error) {
+	body, err := utils.Fetch(url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseVersions(body)
+}
+
+func ParseVersions(body []byte) ([]Version, error) {
+	var r RawResponse
+	err := xml.Unmarshal(body, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Versions, nil
+}
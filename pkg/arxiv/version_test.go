@@ -0,0 +1,66 @@
+package arxiv_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/arxiv"
+)
+
+func TestGetVersions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		xml := `<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+		<GetRecord>
+			<record>
+				<metadata>
+					<arXivRaw xmlns="http://arxiv.org/OAI/arXivRaw/">
+						<id>2101.00001</id>
+						<version version="v1"><date>Fri, 1 Jan 2021 00:00:01 GMT</date><size>120kb</size><source_type>D</source_type></version>
+						<version version="v2"><date>Mon, 15 Feb 2021 12:30:00 GMT</date><size>130kb</size><source_type>D</source_type></version>
+					</arXivRaw>
+				</metadata>
+			</record>
+		</GetRecord>
+		</OAI-PMH>`
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintln(w, xml)
+	}))
+	defer ts.Close()
+
+	versions, err := arxiv.GetVersions(ts.URL)
+	if err != nil {
+		t.Fatalf("GetVersions returned error: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(versions))
+	}
+	if versions[1].Number() != 2 || versions[1].Size != "130kb" {
+		t.Errorf("Unexpected version %v", versions[1])
+	}
+	for i, expected := range []string{"2021-01-01", "2021-02-15"} {
+		submitted, err := versions[i].Submitted()
+		if err != nil || submitted.Format("2006-01-02") != expected {
+			t.Errorf("Unexpected submitted date %v (%v), expected %s", submitted, err, expected)
+		}
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		id      string
+		version int
+	}{
+		{"2101.00001v2", "2101.00001", 2},
+		{"hep-th/9901001v10", "hep-th/9901001", 10},
+		{"2101.00001", "2101.00001", 0},
+	}
+	for _, test := range tests {
+		id, version := arxiv.SplitVersion(test.input)
+		if id != test.id || version != test.version {
+			t.Errorf("SplitVersion(%q) = %q, %d; want %q, %d", test.input, id, version, test.id, test.version)
+		}
+	}
+}
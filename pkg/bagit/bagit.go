@@ -0,0 +1,384 @@
+package bagit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version of the BagIt spec (RFC 8493) bags are created with
+const Version = "1.0"
+
+// Algorithms bags are created with
+var Algorithms = []string{"sha256", "sha512"}
+
+// hashes Validate can check
+var hashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Bag is a BagIt bag being built in a directory
+// add the payload then call Close to write the tag files and move the bag to Path
+// or Discard to remove it
+type Bag struct {
+	Path string
+	// dir is where the bag is built, next to Path, so a bag that's never finished
+	// doesn't stop it being made again
+	dir  string
+	info [][2]string
+}
+
+// New creates an empty bag that will be at path once it's closed
+// path must not already exist so we never add to or overwrite an existing bag
+func New(path string) (*Bag, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.part")
+	if err != nil {
+		return nil, err
+	}
+	// temporary directories are only readable by us, unlike the bags we make
+	if err := os.Chmod(dir, 0755); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	if err := os.Mkdir(filepath.Join(dir, "data"), 0755); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return &Bag{Path: path, dir: dir}, nil
+}
+
+// Discard removes a bag that won't be closed e.g. because adding its payload failed
+func (b *Bag) Discard() error {
+	return os.RemoveAll(b.dir)
+}
+
+// AddInfo adds a label to bag-info.txt e.g. Source-Organization
+func (b *Bag) AddInfo(label, value string) {
+	if value == "" {
+		return
+	}
+	b.info = append(b.info, [2]string{label, value})
+}
+
+// AddFile copies the file at src into the payload as name
+// name is relative to the data directory and uses forward slashes
+func (b *Bag) AddFile(src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return b.add(name, f)
+}
+
+// AddBytes writes content into the payload as name
+func (b *Bag) AddBytes(name string, content []byte) error {
+	return b.add(name, bytes.NewReader(content))
+}
+
+// AddDir copies every file under src into the payload under name
+// files called skip (e.g. a manifest of the directory) are left out
+func (b *Bag) AddDir(src, name string, skip ...string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, s := range skip {
+			if rel == s {
+				return nil
+			}
+		}
+
+		return b.AddFile(path, name+"/"+rel)
+	})
+}
+
+func (b *Bag) add(name string, r io.Reader) error {
+	dest, err := b.payloadPath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (b *Bag) payloadPath(name string) (string, error) {
+	data := filepath.Join(b.dir, "data")
+	path := filepath.Join(data, filepath.FromSlash(name))
+	if !strings.HasPrefix(path, data+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%s is outside of the bag's payload", name)
+	}
+
+	return path, nil
+}
+
+// Close writes bagit.txt, the payload manifests, bag-info.txt and the tag manifests
+// then moves the finished bag to Path
+func (b *Bag) Close() error {
+	err := os.WriteFile(filepath.Join(b.dir, "bagit.txt"), []byte(fmt.Sprintf("BagIt-Version: %s\nTag-File-Character-Encoding: UTF-8\n", Version)), 0644)
+	if err != nil {
+		return err
+	}
+
+	payload, err := files(b.dir, "data")
+	if err != nil {
+		return err
+	}
+	var octets int64
+	for _, f := range payload {
+		fi, err := os.Stat(filepath.Join(b.dir, filepath.FromSlash(f)))
+		if err != nil {
+			return err
+		}
+		octets += fi.Size()
+	}
+	for _, alg := range Algorithms {
+		if err := writeManifest(b.dir, "manifest-"+alg+".txt", alg, payload); err != nil {
+			return err
+		}
+	}
+
+	info := append([][2]string{}, b.info...)
+	info = append(info,
+		[2]string{"Bagging-Date", time.Now().Format("2006-01-02")},
+		[2]string{"Bag-Software-Agent", "papercut"},
+		[2]string{"Payload-Oxum", fmt.Sprintf("%d.%d", octets, len(payload))},
+	)
+	var sb strings.Builder
+	for _, i := range info {
+		// tag values can't span lines without indenting the continuation
+		value := strings.ReplaceAll(strings.TrimSpace(i[1]), "\n", "\n  ")
+		fmt.Fprintf(&sb, "%s: %s\n", i[0], value)
+	}
+	if err := os.WriteFile(filepath.Join(b.dir, "bag-info.txt"), []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+
+	tags := []string{"bagit.txt", "bag-info.txt"}
+	for _, alg := range Algorithms {
+		tags = append(tags, "manifest-"+alg+".txt")
+	}
+	for _, alg := range Algorithms {
+		if err := writeManifest(b.dir, "tagmanifest-"+alg+".txt", alg, tags); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(b.dir, b.Path)
+}
+
+// files lists the files under dir in bag, relative to bag with forward slashes
+func files(bag, dir string) ([]string, error) {
+	list := []string{}
+	err := filepath.WalkDir(filepath.Join(bag, dir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(bag, path)
+		if err != nil {
+			return err
+		}
+		list = append(list, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(list)
+
+	return list, err
+}
+
+func writeManifest(bag, name, alg string, files []string) error {
+	var sb strings.Builder
+	for _, f := range files {
+		sum, err := checksum(filepath.Join(bag, filepath.FromSlash(f)), alg)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&sb, "%s  %s\n", sum, encodePath(f))
+	}
+
+	return os.WriteFile(filepath.Join(bag, name), []byte(sb.String()), 0644)
+}
+
+func checksum(path, alg string) (string, error) {
+	newHash, ok := hashes[alg]
+	if !ok {
+		return "", fmt.Errorf("unsupported algorithm %s", alg)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// encodePath percent encodes the characters RFC 8493 doesn't allow in manifest paths
+func encodePath(p string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(p)
+}
+
+func decodePath(p string) string {
+	return strings.NewReplacer("%0D", "\r", "%0A", "\n", "%25", "%").Replace(p)
+}
+
+// Validate checks a bag is complete and every file matches its checksums
+// all of the problems found are returned joined into one error
+func Validate(path string) error {
+	problems := []error{}
+	if _, err := os.Stat(filepath.Join(path, "bagit.txt")); err != nil {
+		return fmt.Errorf("%s is not a bag: missing bagit.txt", path)
+	}
+
+	manifests, _ := filepath.Glob(filepath.Join(path, "manifest-*.txt"))
+	if len(manifests) == 0 {
+		problems = append(problems, errors.New("no payload manifest"))
+	}
+	payload, err := files(path, "data")
+	if err != nil {
+		return err
+	}
+	for _, m := range manifests {
+		entries, err := checkManifest(path, m, &problems)
+		if err != nil {
+			return err
+		}
+		for _, f := range payload {
+			if !entries[f] {
+				problems = append(problems, fmt.Errorf("%s is not listed in %s", f, filepath.Base(m)))
+			}
+		}
+	}
+
+	tagManifests, _ := filepath.Glob(filepath.Join(path, "tagmanifest-*.txt"))
+	for _, m := range tagManifests {
+		if _, err := checkManifest(path, m, &problems); err != nil {
+			return err
+		}
+	}
+
+	checkOxum(path, payload, &problems)
+
+	return errors.Join(problems...)
+}
+
+// checkManifest verifies every entry in a manifest, returning the paths it lists
+func checkManifest(bag, manifest string, problems *[]error) (map[string]bool, error) {
+	name := filepath.Base(manifest)
+	alg := strings.TrimSuffix(name[strings.LastIndex(name, "-")+1:], ".txt")
+	if _, ok := hashes[alg]; !ok {
+		*problems = append(*problems, fmt.Errorf("%s uses an unsupported algorithm", name))
+		return map[string]bool{}, nil
+	}
+
+	f, err := os.Open(manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		sum, p, found := strings.Cut(line, " ")
+		if !found {
+			*problems = append(*problems, fmt.Errorf("%s has a malformed line: %s", name, line))
+			continue
+		}
+		p = decodePath(strings.TrimLeft(p, " *"))
+		if !filepath.IsLocal(filepath.FromSlash(p)) {
+			*problems = append(*problems, fmt.Errorf("%s lists %s which is outside of the bag", name, p))
+			continue
+		}
+		entries[p] = true
+
+		actual, err := checksum(filepath.Join(bag, filepath.FromSlash(p)), alg)
+		if err != nil {
+			*problems = append(*problems, fmt.Errorf("%s lists %s which can't be read: %v", name, p, err))
+			continue
+		}
+		if !strings.EqualFold(actual, sum) {
+			*problems = append(*problems, fmt.Errorf("%s checksum of %s does not match %s", alg, p, name))
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// checkOxum compares the payload to the Payload-Oxum in bag-info.txt, if there is one
+func checkOxum(bag string, payload []string, problems *[]error) {
+	info, err := os.ReadFile(filepath.Join(bag, "bag-info.txt"))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(info), "\n") {
+		label, value, found := strings.Cut(line, ":")
+		if !found || label != "Payload-Oxum" {
+			continue
+		}
+		var octets int64
+		for _, f := range payload {
+			fi, err := os.Stat(filepath.Join(bag, filepath.FromSlash(f)))
+			if err == nil {
+				octets += fi.Size()
+			}
+		}
+		if actual := fmt.Sprintf("%d.%d", octets, len(payload)); strings.TrimSpace(value) != actual {
+			*problems = append(*problems, fmt.Errorf("Payload-Oxum is %s but the payload is %s", strings.TrimSpace(value), actual))
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package bagit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/bagit"
+)
+
+func makeBag(t *testing.T) string {
+	dir := t.TempDir()
+	pdf := filepath.Join(dir, "paper.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.5"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "bag")
+	bag, err := bagit.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag.AddInfo("External-Identifier", "10.1000/xyz123")
+	if err := bag.AddFile(pdf, "10.1000_xyz123.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := bag.AddBytes("metadata/doi.json", []byte(`{"DOI":"10.1000/xyz123"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bag.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestCreate(t *testing.T) {
+	path := makeBag(t)
+	for _, f := range []string{
+		"bagit.txt",
+		"bag-info.txt",
+		"manifest-sha256.txt",
+		"manifest-sha512.txt",
+		"tagmanifest-sha256.txt",
+		"tagmanifest-sha512.txt",
+		"data/10.1000_xyz123.pdf",
+		"data/metadata/doi.json",
+	} {
+		if _, err := os.Stat(filepath.Join(path, f)); err != nil {
+			t.Errorf("bag is missing %s", f)
+		}
+	}
+
+	info, err := os.ReadFile(filepath.Join(path, "bag-info.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"External-Identifier: 10.1000/xyz123\n", "Payload-Oxum: 32.2\n"} {
+		if !strings.Contains(string(info), expected) {
+			t.Errorf("expected bag-info.txt to contain %q, got %s", expected, info)
+		}
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(path, "manifest-sha256.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "  data/10.1000_xyz123.pdf\n"
+	if !strings.Contains(string(manifest), expected) {
+		t.Errorf("expected manifest to contain %q, got %s", expected, manifest)
+	}
+
+	if _, err := bagit.New(path); err == nil {
+		t.Error("expected an error creating a bag that already exists")
+	}
+
+	unfinished, err := bagit.New(path + "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unfinished.AddBytes("../bagit.txt", nil); err == nil {
+		t.Error("expected an error adding a file outside of the payload")
+	}
+	if _, err := os.Stat(path + "2"); !os.IsNotExist(err) {
+		t.Errorf("expected the bag to only be at its path once it's closed, got %v", err)
+	}
+	if err := unfinished.Discard(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 2 {
+		t.Errorf("expected a discarded bag to be removed, got %d entries next to it", len(entries))
+	}
+	if _, err := bagit.New(path + "2"); err != nil {
+		t.Errorf("expected a discarded bag to be made again, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(path string) error
+		expected string
+	}{
+		{"valid", func(path string) error { return nil }, ""},
+		{"modified payload", func(path string) error {
+			return os.WriteFile(filepath.Join(path, "data", "metadata", "doi.json"), []byte("{}"), 0644)
+		}, "sha256 checksum of data/metadata/doi.json does not match manifest-sha256.txt"},
+		{"missing payload", func(path string) error {
+			return os.Remove(filepath.Join(path, "data", "10.1000_xyz123.pdf"))
+		}, "manifest-sha512.txt lists data/10.1000_xyz123.pdf which can't be read"},
+		{"extra payload", func(path string) error {
+			return os.WriteFile(filepath.Join(path, "data", "extra.txt"), []byte("x"), 0644)
+		}, "data/extra.txt is not listed in manifest-sha256.txt"},
+		{"modified tag file", func(path string) error {
+			return os.WriteFile(filepath.Join(path, "bag-info.txt"), []byte("Payload-Oxum: 32.2\n"), 0644)
+		}, "checksum of bag-info.txt does not match tagmanifest-sha256.txt"},
+		{"path outside the bag", func(path string) error {
+			m := filepath.Join(path, "manifest-sha256.txt")
+			manifest, err := os.ReadFile(m)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(m, append(manifest, []byte("0000  ../paper.pdf\n")...), 0644)
+		}, "manifest-sha256.txt lists ../paper.pdf which is outside of the bag"},
+		{"not a bag", func(path string) error {
+			return os.Remove(filepath.Join(path, "bagit.txt"))
+		}, "missing bagit.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := makeBag(t)
+			if err := tt.modify(path); err != nil {
+				t.Fatal(err)
+			}
+			err := bagit.Validate(path)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("expected a valid bag, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package biorxiv
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// URL is the bioRxiv API, which also serves medRxiv
+var URL = "https://api.biorxiv.org"
+
+// Servers the API can be asked about
+const (
+	Biorxiv = "biorxiv"
+	Medrxiv = "medrxiv"
+)
+
+// RequestDelay is the pause we leave between API requests
+const RequestDelay = time.Second
+
+// Response is a page of the details API
+type Response struct {
+	Messages   []Message  `json:"messages"`
+	Collection []Preprint `json:"collection"`
+}
+
+// Message describes the page e.g. its cursor and the total number of preprints in the interval
+type Message struct {
+	Status string `json:"status"`
+	Cursor Number `json:"cursor"`
+	Count  Number `json:"count"`
+	Total  Number `json:"total"`
+}
+
+// Number is a count the API sometimes sends as a string
+type Number int
+
+func (n *Number) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("expected a number, got %s", b)
+	}
+	*n = Number(i)
+
+	return nil
+}
+
+// Preprint is a version of a preprint
+type Preprint struct {
+	DOI   string `json:"doi"`
+	Title string `json:"title"`
+	// Authors is a list of names like "Smith, J.; Doe, A. B."
+	Authors                        string `json:"authors"`
+	AuthorCorresponding            string `json:"author_corresponding"`
+	AuthorCorrespondingInstitution string `json:"author_corresponding_institution"`
+	// Date is when this version was posted
+	Date     string `json:"date"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`
+	License  string `json:"license"`
+	Category string `json:"category"`
+	JATSXML  string `json:"jatsxml"`
+	Abstract string `json:"abstract"`
+	// Published is the DOI of the journal version of the preprint, or NA if it hasn't been published
+	Published string `json:"published"`
+	Server    string `json:"server"`
+}
+
+// GetDetails returns a page of the preprints posted to a server between from and to (YYYY-MM-DD)
+// each version of a preprint posted in the interval is listed, a hundred to a page
+func GetDetails(server, from, to string, cursor int) (*Response, error) {
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, fmt.Errorf("dates must be like 2024-01-31, got %q", d)
+		}
+	}
+
+	u := fmt.Sprintf("%s/details/%s/%s/%s/%d/json", URL, server, from, to, cursor)
+	body, err := utils.Fetch(u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Response
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s details: %v", server, err)
+	}
+
+	return &r, nil
+}
+
+// Total is the number of preprint versions in the interval
+func (r Response) Total() int {
+	if len(r.Messages) == 0 {
+		return 0
+	}
+
+	return int(r.Messages[0].Total)
+}
+
+// Next returns the cursor of the next page, or false if this is the last one
+func (r Response) Next() (int, bool) {
+	if len(r.Messages) == 0 || len(r.Collection) == 0 {
+		return 0, false
+	}
+	m := r.Messages[0]
+	next := int(m.Cursor) + len(r.Collection)
+
+	return next, next < int(m.Total)
+}
+
+// AuthorNames splits the preprint's authors into their names
+func (p Preprint) AuthorNames() []string {
+	names := []string{}
+	for _, name := range strings.Split(p.Authors, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// PublishedDOI is the DOI of the journal version of the preprint, or "" if it hasn't been published
+func (p Preprint) PublishedDOI() string {
+	if strings.EqualFold(p.Published, "NA") {
+		return ""
+	}
+
+	return strings.TrimSpace(p.Published)
+}
+
+// PDFURL is the link to this version's PDF e.g. https://www.biorxiv.org/content/10.1101/2024.01.01.123456v2.full.pdf
+func (p Preprint) PDFURL() string {
+	server := strings.ToLower(p.Server)
+	if server == "" {
+		server = Biorxiv
+	}
+
+	return fmt.Sprintf("https://www.%s.org/content/%sv%s.full.pdf", server, p.DOI, p.Version)
+}
+
+// VersionNumber is the preprint's version as a number, 1 if it can't be read
+func (p Preprint) VersionNumber() int {
+	v, err := strconv.Atoi(p.Version)
+	if err != nil || v < 1 {
+		return 1
+	}
+
+	return v
+}
+
+// Latest keeps the latest version of each preprint, in the order the preprints were first seen
+func Latest(preprints []Preprint) []Preprint {
+	index := map[string]int{}
+	latest := []Preprint{}
+	for _, p := range preprints {
+		i, ok := index[p.DOI]
+		if !ok {
+			index[p.DOI] = len(latest)
+			latest = append(latest, p)
+			continue
+		}
+		if p.VersionNumber() > latest[i].VersionNumber() {
+			latest[i] = p
+		}
+	}
+
+	return latest
+}
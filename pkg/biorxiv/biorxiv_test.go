@@ -0,0 +1,78 @@
+package biorxiv_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/biorxiv"
+)
+
+func TestGetDetails(t *testing.T) {
+	pages := map[string]string{
+		"/details/biorxiv/2024-01-01/2024-01-31/0/json": `{"messages":[{"status":"ok","interval":"2024-01-01:2024-01-31","cursor":0,"count":2,"total":"3"}],"collection":[
+			{"doi":"10.1101/2024.01.02.000001","title":"Fly brains","authors":"Smith, J.; Doe, A. B.;","author_corresponding":"Jane Smith","author_corresponding_institution":"Lehigh University","date":"2024-01-02","version":"1","type":"new results","license":"cc_by","category":"neuroscience","abstract":"Flies.","published":"NA","server":"bioRxiv"},
+			{"doi":"10.1101/2024.01.03.000002","title":"Mouse hearts","authors":"Roe, R.","date":"2024-01-03","version":"1","license":"cc_no","category":"physiology","published":"10.1038/s41586-024-00002-2","server":"bioRxiv"}]}`,
+		"/details/biorxiv/2024-01-01/2024-01-31/2/json": `{"messages":[{"status":"ok","cursor":"2","count":1,"total":3}],"collection":[
+			{"doi":"10.1101/2024.01.02.000001","title":"Fly brains, revised","authors":"Smith, J.; Doe, A. B.","date":"2024-01-20","version":"2","license":"cc_by","category":"neuroscience","published":"NA","server":"bioRxiv"}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			fmt.Fprintln(w, `{"messages":[{"status":"no posts found"}],"collection":[]}`)
+			return
+		}
+		fmt.Fprintln(w, page)
+	}))
+	defer ts.Close()
+	biorxiv.URL = ts.URL
+
+	preprints := []biorxiv.Preprint{}
+	cursor := 0
+	for {
+		r, err := biorxiv.GetDetails(biorxiv.Biorxiv, "2024-01-01", "2024-01-31", cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Total() != 3 {
+			t.Errorf("expected a total of 3, got %d", r.Total())
+		}
+		preprints = append(preprints, r.Collection...)
+		next, ok := r.Next()
+		if !ok {
+			break
+		}
+		cursor = next
+	}
+	if len(preprints) != 3 {
+		t.Fatalf("expected 3 versions, got %d", len(preprints))
+	}
+
+	latest := biorxiv.Latest(preprints)
+	if len(latest) != 2 || latest[0].Version != "2" || latest[0].Title != "Fly brains, revised" {
+		t.Errorf("expected the latest version of each preprint, got %+v", latest)
+	}
+	p := latest[0]
+	if !reflect.DeepEqual(p.AuthorNames(), []string{"Smith, J.", "Doe, A. B."}) {
+		t.Errorf("unexpected authors %v", p.AuthorNames())
+	}
+	if p.PDFURL() != "https://www.biorxiv.org/content/10.1101/2024.01.02.000001v2.full.pdf" {
+		t.Errorf("unexpected PDF %s", p.PDFURL())
+	}
+	if p.PublishedDOI() != "" || latest[1].PublishedDOI() != "10.1038/s41586-024-00002-2" {
+		t.Errorf("unexpected published DOIs %q %q", p.PublishedDOI(), latest[1].PublishedDOI())
+	}
+
+	r, err := biorxiv.GetDetails(biorxiv.Medrxiv, "2024-01-01", "2024-01-31", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Next(); ok || r.Total() != 0 {
+		t.Error("expected no pages for an interval without preprints")
+	}
+	if _, err := biorxiv.GetDetails(biorxiv.Biorxiv, "2024-01", "2024-01-31", 0); err == nil {
+		t.Error("expected an error for a date without a day")
+	}
+}
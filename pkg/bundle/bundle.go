@@ -0,0 +1,234 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Format is what a downloaded bundle turned out to be
+type Format string
+
+const (
+	TarGzip Format = "tar.gz"
+	Tar     Format = "tar"
+	// Gzip is a single gzipped file e.g. a lone .tex source
+	Gzip Format = "gzip"
+	// Single is a file that isn't compressed or archived e.g. a PDF-only submission
+	Single Format = "file"
+)
+
+// ManifestFile lists the checksums of every file in a bundle directory
+const ManifestFile = "manifest-sha256.txt"
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Detect works out the format of a bundle from its first bytes
+func Detect(r *bufio.Reader) (Format, error) {
+	head, err := r.Peek(2)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if bytes.Equal(head, gzipMagic) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return "", err
+		}
+		defer zr.Close()
+		if isTar(bufio.NewReader(zr)) {
+			return TarGzip, nil
+		}
+		return Gzip, nil
+	}
+	if isTar(r) {
+		return Tar, nil
+	}
+
+	return Single, nil
+}
+
+// isTar looks for the ustar magic in the header of the first tar entry
+func isTar(r *bufio.Reader) bool {
+	block, err := r.Peek(512)
+	if err != nil {
+		return false
+	}
+
+	return bytes.HasPrefix(block[257:], []byte("ustar"))
+}
+
+// Unpack extracts the bundle at src into the dest directory
+// archives are extracted as is, a single (gzipped) file is saved as name
+// with an extension guessed from its contents
+func Unpack(src, dest, name string) (Format, error) {
+	format, err := detectFile(src)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", err
+	}
+
+	var r io.Reader = f
+	if format == TarGzip || format == Gzip {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	switch format {
+	case TarGzip, Tar:
+		return format, untar(r, dest)
+	}
+
+	br := bufio.NewReader(r)
+	return format, writeFile(filepath.Join(dest, name+extension(br)), br)
+}
+
+func detectFile(path string) (Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return Detect(bufio.NewReader(f))
+}
+
+// extension guesses the extension of a single file submission
+func extension(r *bufio.Reader) string {
+	head, _ := r.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF")):
+		return ".pdf"
+	case bytes.HasPrefix(head, []byte("%!")):
+		return ".ps"
+	}
+
+	return ".tex"
+}
+
+func untar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, tr); err != nil {
+				return err
+			}
+		}
+		// links and devices aren't needed to preserve a submission so are skipped
+	}
+}
+
+// safeJoin stops archive entries like ../../etc/passwd escaping dest
+func safeJoin(dest, name string) (string, error) {
+	path := filepath.Join(dest, name)
+	if path != filepath.Clean(dest) && !strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q is outside of %s", name, dest)
+	}
+
+	return path, nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// WriteManifest writes the sha256 checksum of every file under dir
+// to dir/manifest-sha256.txt in the "checksum  path" format of sha256sum
+func WriteManifest(dir string) error {
+	lines := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ManifestFile {
+			return nil
+		}
+		sum, err := Sha256(path)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s", sum, rel))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][66:] < lines[j][66:]
+	})
+
+	return os.WriteFile(filepath.Join(dir, ManifestFile), []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// Sha256 returns the hex encoded sha256 checksum of a file
+func Sha256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,135 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/bundle"
+)
+
+func tarball(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, body := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestUnpack(t *testing.T) {
+	source := map[string]string{
+		"main.tex":         `\documentclass{article}`,
+		"figures/fig1.eps": "%!PS-Adobe-3.0 EPSF-3.0",
+	}
+	tests := []struct {
+		name     string
+		body     []byte
+		format   bundle.Format
+		expected map[string]string
+	}{
+		{"tar.gz", gzipped(t, tarball(t, source)), bundle.TarGzip, source},
+		{"tar", tarball(t, source), bundle.Tar, source},
+		{"gzip", gzipped(t, []byte(`\documentclass{article}`)), bundle.Gzip, map[string]string{"2101.00001v1.tex": `\documentclass{article}`}},
+		{"pdf", []byte("%PDF-1.5"), bundle.Single, map[string]string{"2101.00001v1.pdf": "%PDF-1.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "e-print")
+			if err := os.WriteFile(src, tt.body, 0644); err != nil {
+				t.Fatal(err)
+			}
+			dest := filepath.Join(dir, "paper", "source")
+			format, err := bundle.Unpack(src, dest, "2101.00001v1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, format)
+			}
+			for name, expected := range tt.expected {
+				got, err := os.ReadFile(filepath.Join(dest, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != expected {
+					t.Errorf("%s: expected %q, got %q", name, expected, got)
+				}
+			}
+
+			if err := bundle.WriteManifest(filepath.Join(dir, "paper")); err != nil {
+				t.Fatal(err)
+			}
+			manifest, err := os.ReadFile(filepath.Join(dir, "paper", bundle.ManifestFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(manifest)), "\n")
+			if len(lines) != len(tt.expected) {
+				t.Errorf("expected %d manifest lines, got %q", len(tt.expected), lines)
+			}
+			for name := range tt.expected {
+				if !strings.Contains(string(manifest), "  source/"+name+"\n") {
+					t.Errorf("manifest is missing %s: %s", name, manifest)
+				}
+			}
+		})
+	}
+}
+
+func TestUnpackTraversal(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "e-print")
+	if err := os.WriteFile(src, tarball(t, map[string]string{"../evil.tex": "x"}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bundle.Unpack(src, filepath.Join(dir, "source"), "x"); err == nil {
+		t.Error("expected an error unpacking an entry outside of the destination")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.tex")); err == nil {
+		t.Error("entry was written outside of the destination")
+	}
+}
+
+func TestSha256(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := bundle.Sha256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if sum != expected {
+		t.Errorf("expected %s, got %s", expected, sum)
+	}
+}
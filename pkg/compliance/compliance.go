@@ -0,0 +1,224 @@
+package compliance
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/lehigh-university-libraries/papercut/pkg/unpaywall"
+)
+
+//go:embed rules.json
+var embeddedRules []byte
+
+// Statuses of an article under a rule
+const (
+	Compliant    = "compliant"
+	ActionNeeded = "action-needed"
+	Unknown      = "unknown"
+)
+
+// Rules are the funder public access policies articles are checked against
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule is a funder's public access policy
+// e.g. NIH requires the accepted manuscript in PubMed Central within 12 months of publication
+type Rule struct {
+	Name string `json:"name"`
+	// Funders are the Crossref Funder IDs the rule applies to
+	Funders []string `json:"funders"`
+	// EffectiveFrom and EffectiveUntil limit the rule to articles published
+	// between the two dates (YYYY-MM-DD), either of which can be empty
+	EffectiveFrom  string `json:"effective_from,omitempty"`
+	EffectiveUntil string `json:"effective_until,omitempty"`
+	// EmbargoMonths is how long after publication the article has to be available
+	EmbargoMonths int `json:"embargo_months"`
+	// Repository is where the article has to be available, empty for any repository
+	Repository string `json:"repository,omitempty"`
+	// RepositoryMatches identify a copy in Repository by its URL or Unpaywall's repository_institution
+	RepositoryMatches []string `json:"repository_matches,omitempty"`
+}
+
+// LoadRules reads a rules JSON file
+// or, if path is empty, the rules embedded in papercut
+func LoadRules(path string) (*Rules, error) {
+	body := embeddedRules
+	if path != "" {
+		var err error
+		body, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var r Rules
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal rules: %v", err)
+	}
+	for _, rule := range r.Rules {
+		if rule.Name == "" || len(rule.Funders) == 0 {
+			return nil, fmt.Errorf("every rule needs a name and funders")
+		}
+		for _, d := range []string{rule.EffectiveFrom, rule.EffectiveUntil} {
+			if _, err := parseDate(d); err != nil {
+				return nil, fmt.Errorf("rule %q has an invalid date: %v", rule.Name, err)
+			}
+		}
+	}
+
+	return &r, nil
+}
+
+func parseDate(d string) (time.Time, error) {
+	if d == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.DateOnly, d)
+}
+
+// Evidence is what we know about where an article is available
+type Evidence struct {
+	// Copies are the article's open access copies, nil if they weren't checked
+	Copies []unpaywall.Location
+	// Policy is the journal's Sherpa Romeo record, nil if it wasn't found
+	Policy *romeo.Response
+}
+
+// Result is an article's status under a rule and why
+type Result struct {
+	DOI    string `json:"doi"`
+	Rule   string `json:"rule"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+	// Due is when the article has to be available
+	Due string `json:"due,omitempty"`
+	// Copy is the URL of the copy that complies with the rule
+	Copy string `json:"copy,omitempty"`
+}
+
+// Published is when the article was first published, or zero if Crossref doesn't say
+func Published(a doi.Article) time.Time {
+	for _, d := range []doi.DateParts{a.Issued, a.PublishedOnline, a.PublishedPrint} {
+		if t := d.Time(); !t.IsZero() {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// Check returns the article's status under every rule that applies to it
+func (r *Rules) Check(a doi.Article, e Evidence, now time.Time) []Result {
+	results := []Result{}
+	for _, rule := range r.Rules {
+		if rule.Applies(a) {
+			results = append(results, rule.Check(a, e, now))
+		}
+	}
+
+	return results
+}
+
+// Applies reports whether the article was funded by one of the rule's funders
+// and, if we know when it was published, was published while the rule was in effect
+func (r Rule) Applies(a doi.Article) bool {
+	if !a.FundedBy(r.Funders...) {
+		return false
+	}
+	published := Published(a)
+	if published.IsZero() {
+		return true
+	}
+	from, _ := parseDate(r.EffectiveFrom)
+	until, _ := parseDate(r.EffectiveUntil)
+
+	return !published.Before(from) && (until.IsZero() || !published.After(until))
+}
+
+// Check returns the article's status under the rule
+func (r Rule) Check(a doi.Article, e Evidence, now time.Time) Result {
+	result := Result{DOI: a.DOI, Rule: r.Name, Status: Unknown}
+	published := Published(a)
+	if published.IsZero() {
+		result.Reason = "Crossref has no publication date"
+		return result
+	}
+	due := published.AddDate(0, r.EmbargoMonths, 0)
+	result.Due = due.Format(time.DateOnly)
+
+	where := "a repository"
+	if r.Repository != "" {
+		where = r.Repository
+	}
+	if e.Copies == nil {
+		result.Reason = fmt.Sprintf("open access copies weren't checked so we don't know if it's in %s", where)
+		return result
+	}
+
+	for _, c := range e.Copies {
+		if r.matches(c) && (c.Version == "acceptedVersion" || c.Version == "publishedVersion") {
+			result.Status = Compliant
+			result.Copy = c.URL
+			result.Reason = fmt.Sprintf("the %s is in %s", versionName(c.Version), where)
+			return result
+		}
+	}
+
+	result.Status = ActionNeeded
+	reasons := []string{fmt.Sprintf("the accepted manuscript isn't in %s", where)}
+	if now.After(due) {
+		reasons[0] += fmt.Sprintf(" and was due %s", result.Due)
+	} else {
+		reasons[0] += fmt.Sprintf(" yet, it's due %s", result.Due)
+	}
+	if e.Policy != nil {
+		months, ok := e.Policy.RepositoryEmbargo("accepted")
+		switch {
+		case !ok:
+			reasons = append(reasons, "Sherpa Romeo doesn't list a publisher policy allowing it in a repository")
+		case months > r.EmbargoMonths:
+			reasons = append(reasons, fmt.Sprintf("the publisher's %d month embargo is longer than the %d months allowed", months, r.EmbargoMonths))
+		case months > 0:
+			reasons = append(reasons, fmt.Sprintf("the publisher allows it in a repository after %d months", months))
+		default:
+			reasons = append(reasons, "the publisher allows it in a repository without an embargo")
+		}
+	}
+	result.Reason = strings.Join(reasons, "; ")
+
+	return result
+}
+
+// matches reports whether a copy is in the rule's repository
+func (r Rule) matches(c unpaywall.Location) bool {
+	if c.HostType != "repository" {
+		return false
+	}
+	if len(r.RepositoryMatches) == 0 {
+		return true
+	}
+	for _, m := range r.RepositoryMatches {
+		m = strings.ToLower(m)
+		if strings.Contains(strings.ToLower(c.URL), m) || strings.Contains(strings.ToLower(c.RepositoryInstitution), m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func versionName(v string) string {
+	if v == "publishedVersion" {
+		return "published version"
+	}
+
+	return "accepted manuscript"
+}
@@ -0,0 +1,103 @@
+package compliance_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/compliance"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/lehigh-university-libraries/papercut/pkg/unpaywall"
+)
+
+func article(funder string, year, month, day int) doi.Article {
+	return doi.Article{
+		DOI:    "10.1000/abc",
+		Funder: []doi.Funder{{DOI: funder, Name: "A Funder"}},
+		Issued: doi.DateParts{Dates: [][]int{{year, month, day}}},
+	}
+}
+
+func TestEmbeddedRules(t *testing.T) {
+	rules, err := compliance.LoadRules("")
+	if err != nil {
+		t.Fatalf("Unable to load embedded rules: %v", err)
+	}
+
+	tests := []struct {
+		article  doi.Article
+		expected []string
+	}{
+		{article("10.13039/100000002", 2020, 3, 1), []string{"NIH Public Access Policy"}},
+		{article("https://doi.org/10.13039/100000054", 2025, 8, 1), []string{"NIH 2024 Public Access Policy"}},
+		{article("10.13039/100000001", 2024, 1, 1), []string{"NSF Public Access Policy"}},
+		{article("10.13039/100000015", 2026, 1, 1), []string{"OSTP 2022 Public Access Memo"}},
+		{article("10.13039/100000015", 2024, 1, 1), []string{}},
+		{article("10.13039/501100000780", 2024, 1, 1), []string{}},
+	}
+	for _, tt := range tests {
+		names := []string{}
+		for _, r := range rules.Check(tt.article, compliance.Evidence{}, time.Now()) {
+			names = append(names, r.Rule)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s in %v: expected %v, got %v", tt.article.Funder[0].DOI, tt.article.Issued.Dates, tt.expected, names)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rule := compliance.Rule{
+		Name:              "NIH",
+		Funders:           []string{"10.13039/100000002"},
+		EmbargoMonths:     12,
+		Repository:        "PubMed Central",
+		RepositoryMatches: []string{"ncbi.nlm.nih.gov/pmc"},
+	}
+	a := article("10.13039/100000002", 2024, 1, 15)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	r := rule.Check(a, compliance.Evidence{}, now)
+	if r.Status != compliance.Unknown {
+		t.Errorf("expected unknown without copies, got %+v", r)
+	}
+
+	pmc := unpaywall.Location{URL: "https://www.ncbi.nlm.nih.gov/pmc/articles/PMC1234567", HostType: "repository", Version: "acceptedVersion"}
+	r = rule.Check(a, compliance.Evidence{Copies: []unpaywall.Location{pmc}}, now)
+	if r.Status != compliance.Compliant || r.Copy != pmc.URL || r.Due != "2025-01-15" {
+		t.Errorf("expected compliant with the PMC copy, got %+v", r)
+	}
+
+	elsewhere := unpaywall.Location{URL: "https://repository.example.edu/1", HostType: "repository", Version: "acceptedVersion"}
+	policy := &romeo.Response{Publications: []romeo.Publication{{PublisherPolicies: []romeo.PublisherPolicy{{PermittedOa: []romeo.OpenAccess{
+		{ArticleVersion: []string{"accepted"}, Location: romeo.Location{Locations: []string{"any_repository"}}, Embargo: romeo.Embargo{Amount: 24, Units: "months"}},
+	}}}}}}
+	r = rule.Check(a, compliance.Evidence{Copies: []unpaywall.Location{elsewhere}, Policy: policy}, now)
+	if r.Status != compliance.ActionNeeded || !strings.Contains(r.Reason, "due 2025-01-15") || !strings.Contains(r.Reason, "24 month embargo") {
+		t.Errorf("expected action needed with the embargo as the reason, got %+v", r)
+	}
+
+	r = rule.Check(a, compliance.Evidence{Copies: []unpaywall.Location{}}, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	if r.Status != compliance.ActionNeeded || !strings.Contains(r.Reason, "was due 2025-01-15") {
+		t.Errorf("expected an overdue manuscript, got %+v", r)
+	}
+
+	rule.Repository, rule.RepositoryMatches = "", nil
+	r = rule.Check(a, compliance.Evidence{Copies: []unpaywall.Location{elsewhere}}, now)
+	if r.Status != compliance.Compliant {
+		t.Errorf("expected any repository to comply, got %+v", r)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"rules":[{"name":"Bad","funders":["10.13039/100000001"],"effective_from":"01/01/2024"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compliance.LoadRules(path); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
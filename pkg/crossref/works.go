@@ -0,0 +1,109 @@
+package crossref
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// RequestDelay keeps us to ten requests a second, well under Crossref's rate limit
+const RequestDelay = 100 * time.Millisecond
+
+// WorksResponse is a page of results from the Crossref REST API works endpoint
+type WorksResponse struct {
+	Status  string `json:"status"`
+	Message struct {
+		TotalResults int    `json:"total-results"`
+		NextCursor   string `json:"next-cursor"`
+		Items        []Work `json:"items"`
+	} `json:"message"`
+}
+
+// Work is the part of a works result we need to harvest it
+// the full metadata is fetched with doi.GetDoi
+type Work struct {
+	DOI string `json:"DOI"`
+}
+
+// WorksQuery is a search of the works endpoint e.g. https://api.crossref.org/works
+type WorksQuery struct {
+	// Filters are Crossref filters e.g. orcid or from-index-date
+	Filters map[string]string
+	// Affiliation searches the affiliations of a work's contributors
+	Affiliation string
+	// Mailto puts requests in Crossref's polite pool
+	Mailto string
+	// Rows is the page size, at most 1000
+	Rows int
+}
+
+// Params encodes the query as URL parameters starting from cursor
+func (q WorksQuery) Params(cursor string) url.Values {
+	params := url.Values{}
+	filters := []string{}
+	for k, v := range q.Filters {
+		filters = append(filters, k+":"+v)
+	}
+	sort.Strings(filters)
+	if len(filters) > 0 {
+		params.Set("filter", strings.Join(filters, ","))
+	}
+	if q.Affiliation != "" {
+		params.Set("query.affiliation", q.Affiliation)
+	}
+	if q.Mailto != "" {
+		params.Set("mailto", q.Mailto)
+	}
+	rows := q.Rows
+	if rows <= 0 {
+		rows = 1000
+	}
+	params.Set("rows", fmt.Sprintf("%d", rows))
+	params.Set("select", "DOI")
+	params.Set("cursor", cursor)
+
+	return params
+}
+
+// GetWorkDois returns the DOIs of every work matching the query
+// following Crossref's deep paging cursor until the results run out
+// limiter spaces out the requests for each page
+func GetWorkDois(worksURL string, q WorksQuery, limiter *ratelimit.Limiter) ([]string, error) {
+	dois := []string{}
+	cursor := "*"
+	for {
+		limiter.Wait()
+		u := fmt.Sprintf("%s?%s", worksURL, q.Params(cursor).Encode())
+		page, err := getWorks(u)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range page.Message.Items {
+			dois = append(dois, w.DOI)
+		}
+		if len(page.Message.Items) == 0 || page.Message.NextCursor == "" || len(dois) >= page.Message.TotalResults {
+			return dois, nil
+		}
+		cursor = page.Message.NextCursor
+	}
+}
+
+func getWorks(u string) (WorksResponse, error) {
+	var r WorksResponse
+	body, err := utils.Fetch(u, nil)
+	if err != nil {
+		return r, err
+	}
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return r, fmt.Errorf("could not unmarshal works: %v", err)
+	}
+
+	return r, nil
+}
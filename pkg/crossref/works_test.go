@@ -0,0 +1,66 @@
+package crossref_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/pkg/crossref"
+)
+
+func TestGetWorkDois(t *testing.T) {
+	pages := map[string]string{
+		"*":  `{"status":"ok","message":{"total-results":3,"next-cursor":"c2","items":[{"DOI":"10.1000/a"},{"DOI":"10.1000/b"}]}}`,
+		"c2": `{"status":"ok","message":{"total-results":3,"next-cursor":"c3","items":[{"DOI":"10.1000/c"}]}}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("filter") != "from-index-date:2024-01-01,orcid:0000-0002-1825-0097" {
+			t.Errorf("unexpected filter %q", q.Get("filter"))
+		}
+		if q.Get("query.affiliation") != "Lehigh University" || q.Get("mailto") != "library@example.edu" {
+			t.Errorf("unexpected query %v", q)
+		}
+		page, ok := pages[q.Get("cursor")]
+		if !ok {
+			t.Errorf("unexpected cursor %q", q.Get("cursor"))
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, page)
+	}))
+	defer ts.Close()
+
+	dois, err := crossref.GetWorkDois(ts.URL, crossref.WorksQuery{
+		Filters: map[string]string{
+			"orcid":           "0000-0002-1825-0097",
+			"from-index-date": "2024-01-01",
+		},
+		Affiliation: "Lehigh University",
+		Mailto:      "library@example.edu",
+		Rows:        2,
+	}, ratelimit.New(crossref.RequestDelay))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"10.1000/a", "10.1000/b", "10.1000/c"}
+	if !reflect.DeepEqual(dois, expected) {
+		t.Errorf("expected %v, got %v", expected, dois)
+	}
+
+	_, err = crossref.GetWorkDois(ts.URL+"/missing", crossref.WorksQuery{Mailto: "library@example.edu"}, ratelimit.New(crossref.RequestDelay))
+	if err == nil {
+		t.Error("expected an error for a failed request")
+	} else if strings.Contains(err.Error(), "library@example.edu") {
+		t.Errorf("expected the mailto to be left out of %q", err)
+	}
+}
@@ -0,0 +1,275 @@
+package dblp
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// URL is the DBLP website, which serves the person and search APIs
+var URL = "https://dblp.org"
+
+// RequestDelay is the pause we leave between requests, as DBLP asks people to be gentle
+const RequestDelay = time.Second
+
+// MaxHits is the most publications the search API returns in a page
+const MaxHits = 1000
+
+// Publication is a DBLP record of a paper, from either a person's page or a search
+type Publication struct {
+	// Key is the DBLP key e.g. conf/sigir/SmithD20
+	Key string
+	// Type is the kind of record e.g. article, inproceedings or incollection
+	Type    string
+	Title   string
+	Authors []string
+	// Venue is the journal or the conference's short name
+	Venue  string
+	Volume string
+	Number string
+	Pages  string
+	Year   string
+	// EEs are links to electronic editions e.g. https://doi.org/... or https://arxiv.org/abs/...
+	EEs []string
+}
+
+// person is a DBLP person page
+type person struct {
+	Name    string `xml:"name,attr"`
+	Records []struct {
+		Publication record `xml:",any"`
+	} `xml:"r"`
+}
+
+// record is a publication in a DBLP person page, whose element is named after its type
+type record struct {
+	XMLName   xml.Name
+	Key       string   `xml:"key,attr"`
+	Authors   []string `xml:"author"`
+	Editors   []string `xml:"editor"`
+	Title     markup   `xml:"title"`
+	Journal   string   `xml:"journal"`
+	BookTitle string   `xml:"booktitle"`
+	Volume    string   `xml:"volume"`
+	Number    string   `xml:"number"`
+	Pages     string   `xml:"pages"`
+	Year      string   `xml:"year"`
+	EEs       []string `xml:"ee"`
+}
+
+type markup struct {
+	Inner string `xml:",innerxml"`
+}
+
+var tags = regexp.MustCompile(`<[^>]+>`)
+
+func (m markup) text() string {
+	return strings.TrimSpace(html.UnescapeString(tags.ReplaceAllString(m.Inner, "")))
+}
+
+// GetPerson returns the name and publications on a DBLP person page
+// pid is the person's key e.g. 123/4567 or a URL like https://dblp.org/pid/123/4567
+func GetPerson(pid string) (string, []Publication, error) {
+	pid = strings.TrimSpace(pid)
+	if i := strings.Index(pid, "/pid/"); i >= 0 {
+		pid = pid[i+len("/pid/"):]
+	}
+	pid = strings.TrimSuffix(strings.TrimSuffix(pid, ".xml"), ".html")
+
+	body, err := utils.Fetch(fmt.Sprintf("%s/pid/%s.xml", URL, pid), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	var p person
+	d := xml.NewDecoder(bytes.NewReader(body))
+	d.CharsetReader = asciiReader
+	if err := d.Decode(&p); err != nil {
+		return "", nil, fmt.Errorf("could not unmarshal DBLP person %s: %v", pid, err)
+	}
+
+	publications := []Publication{}
+	for _, r := range p.Records {
+		rec := r.Publication
+		venue := rec.Journal
+		if venue == "" {
+			venue = rec.BookTitle
+		}
+		authors := rec.Authors
+		if len(authors) == 0 {
+			authors = rec.Editors
+		}
+		publications = append(publications, Publication{
+			Key:     rec.Key,
+			Type:    rec.XMLName.Local,
+			Title:   cleanTitle(rec.Title.text()),
+			Authors: cleanNames(authors),
+			Venue:   venue,
+			Volume:  rec.Volume,
+			Number:  rec.Number,
+			Pages:   rec.Pages,
+			Year:    rec.Year,
+			EEs:     rec.EEs,
+		})
+	}
+
+	return p.Name, publications, nil
+}
+
+// asciiReader reads the US-ASCII DBLP declares its XML in, which is already UTF-8
+// anything outside ASCII is sent as a character reference
+func asciiReader(charset string, input io.Reader) (io.Reader, error) {
+	if !strings.EqualFold(charset, "us-ascii") {
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+
+	return input, nil
+}
+
+type searchResponse struct {
+	Result struct {
+		Hits struct {
+			Total string `json:"@total"`
+			Hit   []struct {
+				Info struct {
+					Authors struct {
+						Author list[struct {
+							Text string `json:"text"`
+						}] `json:"author"`
+					} `json:"authors"`
+					Title  string       `json:"title"`
+					Venue  list[string] `json:"venue"`
+					Volume string       `json:"volume"`
+					Number string       `json:"number"`
+					Pages  string       `json:"pages"`
+					Year   string       `json:"year"`
+					Type   string       `json:"type"`
+					Key    string       `json:"key"`
+					DOI    string       `json:"doi"`
+					EE     list[string] `json:"ee"`
+				} `json:"info"`
+			} `json:"hit"`
+		} `json:"hits"`
+	} `json:"result"`
+}
+
+// list is a JSON value DBLP sends as an object when there's one and an array when there are more
+type list[T any] []T
+
+func (l *list[T]) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '[' {
+		var items []T
+		err := json.Unmarshal(b, &items)
+		*l = items
+		return err
+	}
+	var item T
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	*l = list[T]{item}
+
+	return nil
+}
+
+// Search returns a page of the publications matching a query, and how many there are in all
+func Search(query string, first, hits int) ([]Publication, int, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("h", strconv.Itoa(hits))
+	params.Set("f", strconv.Itoa(first))
+
+	body, err := utils.Fetch(fmt.Sprintf("%s/search/publ/api?%s", URL, params.Encode()), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	var r searchResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, 0, fmt.Errorf("could not unmarshal DBLP search results: %v", err)
+	}
+	total, _ := strconv.Atoi(r.Result.Hits.Total)
+
+	publications := []Publication{}
+	for _, h := range r.Result.Hits.Hit {
+		info := h.Info
+		authors := []string{}
+		for _, a := range info.Authors.Author {
+			authors = append(authors, a.Text)
+		}
+		ees := []string(info.EE)
+		if info.DOI != "" {
+			ees = append([]string{"https://doi.org/" + info.DOI}, ees...)
+		}
+		publications = append(publications, Publication{
+			Key:     info.Key,
+			Type:    info.Type,
+			Title:   cleanTitle(html.UnescapeString(info.Title)),
+			Authors: cleanNames(authors),
+			Venue:   strings.Join(info.Venue, ", "),
+			Volume:  info.Volume,
+			Number:  info.Number,
+			Pages:   info.Pages,
+			Year:    info.Year,
+			EEs:     ees,
+		})
+	}
+
+	return publications, total, nil
+}
+
+// cleanTitle drops the full stop DBLP ends titles with
+func cleanTitle(t string) string {
+	return strings.TrimSuffix(strings.TrimSpace(t), ".")
+}
+
+var homonymNumber = regexp.MustCompile(`\s+\d{4}$`)
+
+// cleanNames drops the numbers DBLP adds to tell people with the same name apart e.g. Jane Smith 0002
+func cleanNames(names []string) []string {
+	cleaned := []string{}
+	for _, n := range names {
+		cleaned = append(cleaned, homonymNumber.ReplaceAllString(html.UnescapeString(strings.TrimSpace(n)), ""))
+	}
+
+	return cleaned
+}
+
+var arxivAbs = regexp.MustCompile(`arxiv\.org/abs/(.+?)(v\d+)?$`)
+
+// DOI returns the publication's DOI from its electronic editions, leaving out arXiv's DOIs
+func (p Publication) DOI() string {
+	for _, ee := range p.EEs {
+		for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/"} {
+			if d, ok := strings.CutPrefix(ee, prefix); ok && !strings.HasPrefix(strings.ToLower(d), "10.48550/arxiv.") {
+				return d
+			}
+		}
+	}
+
+	return ""
+}
+
+// ArxivID returns the publication's arXiv ID e.g. 2101.00001, if it's on arXiv
+func (p Publication) ArxivID() string {
+	for _, ee := range p.EEs {
+		if m := arxivAbs.FindStringSubmatch(ee); m != nil {
+			return m[1]
+		}
+		lower := strings.ToLower(ee)
+		if i := strings.Index(lower, "10.48550/arxiv."); i >= 0 {
+			return ee[i+len("10.48550/arxiv."):]
+		}
+	}
+
+	return ""
+}
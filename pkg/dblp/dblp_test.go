@@ -0,0 +1,107 @@
+package dblp_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/dblp"
+)
+
+const personXML = `<?xml version="1.0" encoding="US-ASCII"?>
+<dblpperson name="Jane Smith 0002" pid="123/4567" n="3">
+<person key="homepages/123/4567" mdate="2024-01-01"><author pid="123/4567">Jane Smith 0002</author></person>
+<r><inproceedings key="conf/sigir/SmithD20" mdate="2020-07-01">
+<author pid="123/4567">Jane Smith 0002</author><author pid="89/1011">Alex Doe</author>
+<title>Ranking <i>Everything</i> &amp; More.</title>
+<pages>1-10</pages><year>2020</year><booktitle>SIGIR</booktitle>
+<ee>https://doi.org/10.1145/3397271.3401001</ee>
+<ee type="oa">https://arxiv.org/abs/2005.00001</ee>
+</inproceedings></r>
+<r><article publtype="informal" key="journals/corr/abs-2101-00001" mdate="2021-01-05">
+<author pid="123/4567">Jane Smith 0002</author>
+<title>A Preprint.</title><journal>CoRR</journal><volume>abs/2101.00001</volume><year>2021</year>
+<ee type="oa">https://arxiv.org/abs/2101.00001v2</ee>
+</article></r>
+<r><article key="journals/x/Smith22" mdate="2022-01-05">
+<author pid="123/4567">Jane Smith 0002</author>
+<title>No Links.</title><journal>X</journal><volume>1</volume><number>2</number><year>2022</year>
+<ee>https://doi.org/10.48550/arXiv.2201.00003</ee>
+</article></r>
+</dblpperson>`
+
+func TestGetPerson(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pid/123/4567.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, personXML)
+	}))
+	defer ts.Close()
+	dblp.URL = ts.URL
+
+	name, publications, err := dblp.GetPerson("https://dblp.org/pid/123/4567.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Jane Smith 0002" || len(publications) != 3 {
+		t.Fatalf("unexpected person %s with %d publications", name, len(publications))
+	}
+
+	p := publications[0]
+	if p.Key != "conf/sigir/SmithD20" || p.Type != "inproceedings" || p.Venue != "SIGIR" || p.Title != "Ranking Everything & More" {
+		t.Errorf("unexpected publication %+v", p)
+	}
+	if !reflect.DeepEqual(p.Authors, []string{"Jane Smith", "Alex Doe"}) {
+		t.Errorf("expected the homonym numbers to be dropped, got %v", p.Authors)
+	}
+	if p.DOI() != "10.1145/3397271.3401001" || p.ArxivID() != "2005.00001" {
+		t.Errorf("unexpected identifiers %q %q", p.DOI(), p.ArxivID())
+	}
+	if publications[1].DOI() != "" || publications[1].ArxivID() != "2101.00001" {
+		t.Errorf("expected the arXiv ID without its version, got %q", publications[1].ArxivID())
+	}
+	if publications[2].DOI() != "" || publications[2].ArxivID() != "2201.00003" {
+		t.Errorf("expected an arXiv DOI to be an arXiv ID, got %q %q", publications[2].DOI(), publications[2].ArxivID())
+	}
+
+	if _, _, err := dblp.GetPerson("000/0000"); err == nil {
+		t.Error("expected an error for a person DBLP doesn't have")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/search/publ/api" || q.Get("q") != "ranking" || q.Get("format") != "json" || q.Get("f") != "0" || q.Get("h") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprintln(w, `{"result":{"hits":{"@total":"5","@sent":"2","@first":"0","hit":[
+			{"info":{"authors":{"author":[{"@pid":"123/4567","text":"Jane Smith 0002"},{"@pid":"89/1011","text":"Alex Doe"}]},
+				"title":"Ranking Everything &amp; More.","venue":"SIGIR","pages":"1-10","year":"2020","type":"Conference and Workshop Papers",
+				"key":"conf/sigir/SmithD20","doi":"10.1145/3397271.3401001","ee":"https://doi.org/10.1145/3397271.3401001"}},
+			{"info":{"authors":{"author":{"@pid":"123/4567","text":"Jane Smith 0002"}},
+				"title":"A Preprint.","venue":["CoRR","arXiv"],"year":"2021","key":"journals/corr/abs-2101-00001",
+				"ee":["https://arxiv.org/abs/2101.00001"]}}
+		]}}}`)
+	}))
+	defer ts.Close()
+	dblp.URL = ts.URL
+
+	publications, total, err := dblp.Search("ranking", 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || len(publications) != 2 {
+		t.Fatalf("expected 2 of 5 publications, got %d of %d", len(publications), total)
+	}
+	if p := publications[0]; p.Title != "Ranking Everything & More" || p.DOI() != "10.1145/3397271.3401001" || len(p.Authors) != 2 {
+		t.Errorf("unexpected publication %+v", p)
+	}
+	if p := publications[1]; !reflect.DeepEqual(p.Authors, []string{"Jane Smith"}) || p.Venue != "CoRR, arXiv" || p.ArxivID() != "2101.00001" {
+		t.Errorf("expected a single author and venue to be read like lists, got %+v", p)
+	}
+}
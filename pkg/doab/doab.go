@@ -0,0 +1,101 @@
+package doab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// SearchURL is the Directory of Open Access Books REST API's search endpoint
+var SearchURL = "https://directory.doabooks.org/rest/search"
+
+// Item is a book or chapter in DOAB
+type Item struct {
+	Handle   string     `json:"handle"`
+	Name     string     `json:"name"`
+	Metadata []Metadata `json:"metadata"`
+}
+
+// Metadata is a Dublin Core field of an item e.g. dc.rights
+type Metadata struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Get returns the values of an item's metadata field
+func (i Item) Get(key string) []string {
+	values := []string{}
+	for _, m := range i.Metadata {
+		if m.Key == key && m.Value != "" {
+			values = append(values, m.Value)
+		}
+	}
+
+	return values
+}
+
+// License returns the URI of the item's license, if it has one
+func (i Item) License() string {
+	for _, key := range []string{"dc.rights.uri", "dc.rights"} {
+		for _, v := range i.Get(key) {
+			if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+				return v
+			}
+		}
+	}
+
+	return ""
+}
+
+// NormalizeIsbn strips the hyphens and spaces from an ISBN
+func NormalizeIsbn(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn)))
+}
+
+// GetIsbnItems returns the DOAB items with an ISBN, caching the response
+func GetIsbnItems(isbn string) ([]Item, error) {
+	isbn = NormalizeIsbn(isbn)
+	if len(isbn) != 10 && len(isbn) != 13 {
+		return nil, fmt.Errorf("invalid ISBN %q", isbn)
+	}
+
+	d, err := utils.MkTmpDir("isbns")
+	if err != nil {
+		return nil, err
+	}
+	d = filepath.Join(d, isbn+".json")
+
+	params := url.Values{}
+	params.Set("query", "isbn:"+isbn)
+	params.Set("expand", "metadata")
+	result := utils.GetResult(d, fmt.Sprintf("%s?%s", SearchURL, params.Encode()), "application/json")
+	if result == nil {
+		return nil, fmt.Errorf("could not search DOAB for ISBN %s", isbn)
+	}
+
+	var items []Item
+	if err := json.Unmarshal(result, &items); err != nil {
+		return nil, fmt.Errorf("could not unmarshal DOAB results for %s: %v", isbn, err)
+	}
+
+	return items, nil
+}
+
+// GetIsbnLicense returns the license of the first DOAB item with an ISBN that has one
+func GetIsbnLicense(isbn string) (string, error) {
+	items, err := GetIsbnItems(isbn)
+	if err != nil {
+		return "", err
+	}
+	for _, i := range items {
+		if l := i.License(); l != "" {
+			return l, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,61 @@
+package doab_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doab"
+)
+
+func TestGetIsbnLicense(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if q.Get("expand") != "metadata" {
+			t.Errorf("unexpected query %v", q)
+		}
+		switch q.Get("query") {
+		case "isbn:9783110000000":
+			fmt.Fprintln(w, `[{"handle":"20.500.12854/1","name":"A Book","metadata":[
+				{"key":"dc.title","value":"A Book"},
+				{"key":"dc.rights","value":"open access"},
+				{"key":"dc.rights","value":"https://creativecommons.org/licenses/by/4.0/"}
+			]}]`)
+		case "isbn:9783110000001":
+			fmt.Fprintln(w, `[]`)
+		default:
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+	doab.SearchURL = ts.URL
+
+	for range 2 {
+		license, err := doab.GetIsbnLicense("978-3-11-000000-0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if license != "https://creativecommons.org/licenses/by/4.0/" {
+			t.Errorf("unexpected license %q", license)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected the search to be cached, got %d requests", requests)
+	}
+
+	license, err := doab.GetIsbnLicense("9783110000001")
+	if err != nil || license != "" {
+		t.Errorf("expected no license for a book DOAB doesn't have, got %q, %v", license, err)
+	}
+	if _, err := doab.GetIsbnLicense("123"); err == nil {
+		t.Error("expected an error for an invalid ISBN")
+	}
+	if _, err := doab.GetIsbnLicense("9783110000002"); err == nil {
+		t.Error("expected an error for a failed search")
+	}
+}
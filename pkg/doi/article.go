@@ -46,49 +46,123 @@ type JournalIssue struct {
 	PublishedPrint  DateParts `json:"published-print"`
 }
 
+// Reference is an entry in the article's bibliography
+type Reference struct {
+	Key           string `json:"key"`
+	DOI           string `json:"DOI"`
+	DoiAssertedBy string `json:"doi-asserted-by"`
+	ArticleTitle  string `json:"article-title"`
+	JournalTitle  string `json:"journal-title"`
+	Author        string `json:"author"`
+	Year          string `json:"year"`
+	Unstructured  string `json:"unstructured"`
+}
+
+// Relation links the article to another work e.g. "is-cited-by" or "has-preprint"
+type Relation struct {
+	IDType     string `json:"id-type"`
+	ID         string `json:"id"`
+	AssertedBy string `json:"asserted-by"`
+}
+
+// License is a license that applies to a version of the article from its start date
+type License struct {
+	URL            string    `json:"URL"`
+	Start          DateParts `json:"start"`
+	DelayInDays    int       `json:"delay-in-days"`
+	ContentVersion string    `json:"content-version"`
+}
+
+// Funder is an organization that funded the work, with its award numbers
+type Funder struct {
+	// DOI is the funder's ID in the Crossref Funder Registry e.g. 10.13039/100000001
+	DOI           string   `json:"DOI"`
+	Name          string   `json:"name"`
+	DoiAssertedBy string   `json:"doi-asserted-by"`
+	Award         []string `json:"award"`
+}
+
+// ClinicalTrialNumber is the registration of a clinical trial the article reports on
+type ClinicalTrialNumber struct {
+	Number string `json:"clinical-trial-number"`
+	// Registry is the DOI of the trial registry e.g. 10.18810/clinical-trials-gov
+	Registry string `json:"registry"`
+	Type     string `json:"type"`
+}
+
+// Update links a notice like a correction or retraction to the article it updates
+// in update-to it's the article a notice updates and in updated-by the notice updating the article
+type Update struct {
+	DOI     string    `json:"DOI"`
+	Type    string    `json:"type"`
+	Label   string    `json:"label"`
+	Source  string    `json:"source"`
+	Updated DateParts `json:"updated"`
+}
+
+// Assertion is a piece of Crossmark metadata e.g. the received date or peer review method
+type Assertion struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	URL   string `json:"URL"`
+	Order int    `json:"order"`
+	Group struct {
+		Name  string `json:"name"`
+		Label string `json:"label"`
+	} `json:"group"`
+}
+
 type DateParts struct {
 	Dates [][]int `json:"date-parts"`
 }
 
 // Define the main struct
 type Article struct {
-	ReferenceCount      int           `json:"reference-count"`
-	Publisher           string        `json:"publisher"`
-	Issue               string        `json:"issue"`
-	ContentDomain       ContentDomain `json:"content-domain"`
-	Abstract            string        `json:"abstract"`
-	DOI                 string        `json:"DOI"`
-	Type                string        `json:"type"`
-	Created             DateParts     `json:"created"`
-	Page                string        `json:"page"`
-	Source              string        `json:"source"`
-	IsReferencedByCount int           `json:"is-referenced-by-count"`
-	PublishedPrint      DateParts     `json:"published-print"`
-	Title               string        `json:"title"`
-	Prefix              string        `json:"prefix"`
-	Volume              string        `json:"volume"`
-	Authors             []Author      `json:"author"`
-	Member              string        `json:"member"`
-	PublishedOnline     DateParts     `json:"published-online"`
-	ContainerTitle      string        `json:"container-title"`
-	OriginalTitle       []string      `json:"original-title"`
-	Language            string        `json:"language"`
-	Link                []Link        `json:"link"`
-	Deposited           DateParts     `json:"deposited"`
-	Score               int           `json:"score"`
-	Resource            Resource      `json:"resource"`
-	Subtitle            []string      `json:"subtitle"`
-	ShortTitle          []string      `json:"short-title"`
-	Issued              DateParts     `json:"issued"`
-	ReferencesCount     int           `json:"references-count"`
-	JournalIssue        JournalIssue  `json:"journal-issue"`
-	URL                 string        `json:"URL"`
-	ISSN                []string      `json:"ISSN"`
-	Subject             []string      `json:"subject"`
-	ContainerTitleShort string        `json:"container-title-short"`
-	PublishedDate       DateParts     `json:"published"`
-	// do not have a need for relation ATM
-	//	Relation            interface{}   `json:"relation"`
+	ReferenceCount      int                   `json:"reference-count"`
+	Publisher           string                `json:"publisher"`
+	Issue               string                `json:"issue"`
+	ContentDomain       ContentDomain         `json:"content-domain"`
+	Abstract            string                `json:"abstract"`
+	DOI                 string                `json:"DOI"`
+	Type                string                `json:"type"`
+	Created             DateParts             `json:"created"`
+	Page                string                `json:"page"`
+	Source              string                `json:"source"`
+	IsReferencedByCount int                   `json:"is-referenced-by-count"`
+	PublishedPrint      DateParts             `json:"published-print"`
+	Title               string                `json:"title"`
+	Prefix              string                `json:"prefix"`
+	Volume              string                `json:"volume"`
+	Authors             []Author              `json:"author"`
+	Member              string                `json:"member"`
+	PublishedOnline     DateParts             `json:"published-online"`
+	ContainerTitle      string                `json:"container-title"`
+	OriginalTitle       []string              `json:"original-title"`
+	Language            string                `json:"language"`
+	Link                []Link                `json:"link"`
+	Deposited           DateParts             `json:"deposited"`
+	Score               int                   `json:"score"`
+	Resource            Resource              `json:"resource"`
+	Subtitle            []string              `json:"subtitle"`
+	ShortTitle          []string              `json:"short-title"`
+	Issued              DateParts             `json:"issued"`
+	ReferencesCount     int                   `json:"references-count"`
+	JournalIssue        JournalIssue          `json:"journal-issue"`
+	URL                 string                `json:"URL"`
+	ISSN                []string              `json:"ISSN"`
+	ISBN                []string              `json:"ISBN"`
+	License             []License             `json:"license"`
+	Funder              []Funder              `json:"funder"`
+	ClinicalTrials      []ClinicalTrialNumber `json:"clinical-trial-number"`
+	UpdateTo            []Update              `json:"update-to"`
+	UpdatedBy           []Update              `json:"updated-by"`
+	Assertion           []Assertion           `json:"assertion"`
+	Subject             []string              `json:"subject"`
+	ContainerTitleShort string                `json:"container-title-short"`
+	PublishedDate       DateParts             `json:"published"`
+	References          []Reference           `json:"reference"`
+	Relation            map[string][]Relation `json:"relation"`
 }
 
 func GetDoi(d, url string) (Article, error) {
@@ -114,6 +188,101 @@ func GetDoi(d, url string) (Article, error) {
 	return a, nil
 }
 
+// RefreshDoi fetches a DOI's metadata again, ignoring the cache
+// the cached copy is only replaced once the new metadata has been read
+func RefreshDoi(d, url string) (Article, error) {
+	dirPath, err := utils.MkTmpDir(filepath.Join("dois", d))
+	if err != nil {
+		return Article{}, fmt.Errorf("unable to create cached file directory: %v", err)
+	}
+
+	result, err := utils.Fetch(fmt.Sprintf("%s/%s", url, d), map[string]string{"Accept": "application/json"})
+	if err != nil {
+		return Article{}, fmt.Errorf("could not find DOI %s: %v", d, err)
+	}
+
+	var a Article
+	err = json.Unmarshal(result, &a)
+	if err != nil {
+		return Article{}, fmt.Errorf("could not unmarshal JSON for %s: %v", d, err)
+	}
+	utils.WriteCachedFile(filepath.Join(dirPath, "doi.json"), string(result))
+
+	return a, nil
+}
+
+// Affiliations returns the distinct affiliation names of the article's authors
+func (a Article) Affiliations() []string {
+	affiliations := []string{}
+	for _, author := range a.Authors {
+		for _, aff := range author.Affiliation {
+			if aff.Name != "" && !utils.StrInSlice(aff.Name, affiliations) {
+				affiliations = append(affiliations, aff.Name)
+			}
+		}
+	}
+
+	return affiliations
+}
+
+// CitedDois returns the DOIs found in the article's bibliography
+func (a Article) CitedDois() []string {
+	dois := []string{}
+	for _, r := range a.References {
+		if r.DOI != "" {
+			dois = append(dois, r.DOI)
+		}
+	}
+
+	return dois
+}
+
+// RelatedDois returns the DOIs of the works related to the article by the given relation type
+func (a Article) RelatedDois(relationType string) []string {
+	dois := []string{}
+	for _, r := range a.Relation[relationType] {
+		if strings.EqualFold(r.IDType, "doi") {
+			dois = append(dois, r.ID)
+		}
+	}
+
+	return dois
+}
+
+// CurrentLicense returns the URL of the license in effect at now
+// preferring the version of record over the accepted manuscript, and either over an unspecified version
+// other licenses like text and data mining (tdm) or the STM sharing framework (stm-asf) are ignored
+// since they don't cover reuse of the article
+func (a Article) CurrentLicense(now time.Time) string {
+	rank := map[string]int{"vor": 0, "am": 1, "unspecified": 2}
+	best, bestRank, bestStart := "", 0, time.Time{}
+	for _, l := range a.License {
+		r, ok := rank[l.ContentVersion]
+		if l.URL == "" || !ok {
+			continue
+		}
+		start := l.Start.Time()
+		if start.After(now) {
+			continue
+		}
+		if best == "" || r < bestRank || (r == bestRank && start.After(bestStart)) {
+			best, bestRank, bestStart = l.URL, r, start
+		}
+	}
+
+	return best
+}
+
+// Time is the start of the first date, or zero if there isn't one
+func (d DateParts) Time() time.Time {
+	if len(d.Dates) == 0 || len(d.Dates[0]) == 0 {
+		return time.Time{}
+	}
+	parts := append(append([]int{}, d.Dates[0]...), 1, 1)
+
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], 0, 0, 0, 0, time.UTC)
+}
+
 func JoinDate(d DateParts) string {
 	l := len(d.Dates[0])
 
@@ -2,11 +2,13 @@ package doi
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestJoinDate(t *testing.T) {
@@ -136,3 +138,157 @@ func TestGetObject(t *testing.T) {
 		}
 	}
 }
+
+func TestRefreshDoi(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	title := "Harvested"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if title == "" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"title":%q}`, title)
+	}))
+	defer ts.Close()
+
+	if _, err := GetDoi("10.1000/abc", ts.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	title = "Retracted: Harvested"
+	a, err := RefreshDoi("10.1000/abc", ts.URL)
+	if err != nil || a.Title != title {
+		t.Fatalf("expected the DOI to be fetched again, got %q %v", a.Title, err)
+	}
+
+	title = ""
+	if _, err := RefreshDoi("10.1000/abc", ts.URL); err == nil {
+		t.Error("expected an error for a failed request")
+	}
+	a, err = GetDoi("10.1000/abc", ts.URL)
+	if err != nil || a.Title != "Retracted: Harvested" {
+		t.Errorf("expected a failed refresh to keep the cached metadata, got %q %v", a.Title, err)
+	}
+}
+
+func TestRelatedDois(t *testing.T) {
+	body := []byte(`{
+		"DOI": "10.1000/abc",
+		"reference": [
+			{"key": "ref1", "DOI": "10.1000/cited", "doi-asserted-by": "crossref"},
+			{"key": "ref2", "unstructured": "A reference without a DOI"}
+		],
+		"relation": {
+			"is-cited-by": [
+				{"id-type": "doi", "id": "10.1000/citing", "asserted-by": "object"},
+				{"id-type": "uri", "id": "https://example.com", "asserted-by": "object"}
+			]
+		}
+	}`)
+	var a Article
+	if err := json.Unmarshal(body, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := a.CitedDois(); !reflect.DeepEqual(got, []string{"10.1000/cited"}) {
+		t.Errorf("CitedDois() = %v", got)
+	}
+	if got := a.RelatedDois("is-cited-by"); !reflect.DeepEqual(got, []string{"10.1000/citing"}) {
+		t.Errorf("RelatedDois(is-cited-by) = %v", got)
+	}
+	if got := a.RelatedDois("has-preprint"); len(got) != 0 {
+		t.Errorf("RelatedDois(has-preprint) = %v", got)
+	}
+}
+
+func TestCurrentLicense(t *testing.T) {
+	body := []byte(`{
+		"DOI": "10.1000/abc",
+		"license": [
+			{"start": {"date-parts": [[2019, 1, 1]]}, "content-version": "stm-asf", "delay-in-days": 0, "URL": "https://doi.org/10.15223/policy-017"},
+			{"start": {"date-parts": [[2020, 1, 1]]}, "content-version": "tdm", "delay-in-days": 0, "URL": "https://example.com/tdm"},
+			{"start": {"date-parts": [[2020, 1, 1]]}, "content-version": "am", "delay-in-days": 0, "URL": "https://example.com/am"},
+			{"start": {"date-parts": [[2020, 1, 1]]}, "content-version": "vor", "delay-in-days": 0, "URL": "https://example.com/all-rights-reserved"},
+			{"start": {"date-parts": [[2021, 1, 1]]}, "content-version": "vor", "delay-in-days": 366, "URL": "https://creativecommons.org/licenses/by/4.0/"}
+		]
+	}`)
+	var a Article
+	if err := json.Unmarshal(body, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"2019-06-01": "",
+		"2020-06-01": "https://example.com/all-rights-reserved",
+		"2021-06-01": "https://creativecommons.org/licenses/by/4.0/",
+	}
+	for date, want := range tests {
+		now, _ := time.Parse(time.DateOnly, date)
+		if got := a.CurrentLicense(now); got != want {
+			t.Errorf("CurrentLicense(%s) = %q, want %q", date, got, want)
+		}
+	}
+
+	a.License = a.License[:3]
+	if got := a.CurrentLicense(time.Now()); got != "https://example.com/am" {
+		t.Errorf("expected the accepted manuscript's license without a version of record, got %q", got)
+	}
+
+	a.License = a.License[:2]
+	if got := a.CurrentLicense(time.Now()); got != "" {
+		t.Errorf("expected no license from the sharing framework and text and data mining licenses, got %q", got)
+	}
+
+	a.License = append(a.License, License{URL: "https://creativecommons.org/licenses/by/4.0/", ContentVersion: "unspecified"})
+	if got := a.CurrentLicense(time.Now()); got != "https://creativecommons.org/licenses/by/4.0/" {
+		t.Errorf("expected the license of an unspecified version, got %q", got)
+	}
+}
+
+func TestFunding(t *testing.T) {
+	body := []byte(`{
+		"DOI": "10.1000/abc",
+		"funder": [
+			{"DOI": "10.13039/100000001", "name": "National Science Foundation", "doi-asserted-by": "publisher", "award": ["1234567", "7654321"]},
+			{"name": "A Foundation Without an ID", "award": []}
+		],
+		"clinical-trial-number": [{"clinical-trial-number": "NCT01234567", "registry": "10.18810/clinical-trials-gov", "type": "preResults"}],
+		"update-to": [{"DOI": "10.1000/original", "type": "retraction", "label": "Retraction", "updated": {"date-parts": [[2024, 5, 1]]}}],
+		"assertion": [{"name": "received", "label": "Received", "value": "2024-01-01", "order": 0, "group": {"name": "publication_history", "label": "Publication History"}}]
+	}`)
+	var a Article
+	if err := json.Unmarshal(body, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	names, ids, awards := a.Funding()
+	if !reflect.DeepEqual(names, []string{"National Science Foundation", "A Foundation Without an ID"}) {
+		t.Errorf("unexpected funder names %v", names)
+	}
+	if !reflect.DeepEqual(ids, []string{"10.13039/100000001", ""}) {
+		t.Errorf("unexpected funder IDs %v", ids)
+	}
+	if !reflect.DeepEqual(awards, []string{"1234567;7654321", ""}) {
+		t.Errorf("unexpected awards %v", awards)
+	}
+
+	for _, id := range []string{"10.13039/100000001", "https://doi.org/10.13039/100000001", "100000001"} {
+		if !a.FundedBy("10.13039/100000002", id) {
+			t.Errorf("expected the article to be funded by %s", id)
+		}
+	}
+	if a.FundedBy("10.13039/100000002") {
+		t.Error("expected the article to not be funded by 10.13039/100000002")
+	}
+
+	if got := a.ClinicalTrialNumbers(); !reflect.DeepEqual(got, []string{"NCT01234567"}) {
+		t.Errorf("ClinicalTrialNumbers() = %v", got)
+	}
+	if len(a.UpdateTo) != 1 || a.UpdateTo[0].Type != "retraction" || a.UpdateTo[0].DOI != "10.1000/original" {
+		t.Errorf("unexpected update-to %+v", a.UpdateTo)
+	}
+	if len(a.Assertion) != 1 || a.Assertion[0].Group.Name != "publication_history" {
+		t.Errorf("unexpected assertions %+v", a.Assertion)
+	}
+}
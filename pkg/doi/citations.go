@@ -0,0 +1,54 @@
+package doi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// Citation is a row returned by the OpenCitations COCI citations endpoint
+type Citation struct {
+	OCI      string `json:"oci"`
+	Citing   string `json:"citing"`
+	Cited    string `json:"cited"`
+	Creation string `json:"creation"`
+}
+
+// GetCitations returns the DOIs of the works citing d
+// using an OpenCitations COCI compatible API
+// e.g. https://opencitations.net/index/coci/api/v1/citations
+func GetCitations(d, url string) ([]string, error) {
+	dirPath, err := utils.MkTmpDir(filepath.Join("dois", d))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cached file directory: %v", err)
+	}
+
+	dir := filepath.Join(dirPath, "citations.json")
+	u := fmt.Sprintf("%s/%s", strings.TrimSuffix(url, "/"), d)
+	result := utils.GetResult(dir, u, "application/json")
+	if result == nil {
+		return nil, fmt.Errorf("could not find citations for %s", d)
+	}
+
+	var citations []Citation
+	err = json.Unmarshal(result, &citations)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal citations for %s: %v", d, err)
+	}
+
+	dois := []string{}
+	for _, c := range citations {
+		// newer versions of the API prefix identifiers with their scheme
+		for _, id := range strings.Fields(c.Citing) {
+			id = strings.TrimPrefix(id, "doi:")
+			if strings.HasPrefix(id, "10.") {
+				dois = append(dois, id)
+			}
+		}
+	}
+
+	return dois, nil
+}
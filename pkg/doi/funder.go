@@ -0,0 +1,63 @@
+package doi
+
+import (
+	"strings"
+)
+
+// FunderIDPrefix is the DOI prefix of the Crossref Funder Registry
+const FunderIDPrefix = "10.13039/"
+
+// NormalizeFunderID turns a Funder Registry ID written as a DOI, a doi.org URL
+// or just its number into a DOI e.g. 10.13039/100000001
+func NormalizeFunderID(id string) string {
+	id = strings.TrimSpace(id)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"} {
+		id = strings.TrimPrefix(id, prefix)
+	}
+	if id == "" || strings.HasPrefix(id, FunderIDPrefix) {
+		return id
+	}
+
+	return FunderIDPrefix + id
+}
+
+// FundedBy reports whether any of the funders with the given Funder Registry IDs funded the article
+func (a Article) FundedBy(ids ...string) bool {
+	for _, f := range a.Funder {
+		if f.DOI == "" {
+			continue
+		}
+		for _, id := range ids {
+			if NormalizeFunderID(f.DOI) == NormalizeFunderID(id) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Funding returns the funders' names, Funder Registry IDs and award numbers
+// as lists in the same order, one entry per funder
+// a funder's award numbers are separated by semicolons
+func (a Article) Funding() (names, ids, awards []string) {
+	for _, f := range a.Funder {
+		names = append(names, f.Name)
+		ids = append(ids, NormalizeFunderID(f.DOI))
+		awards = append(awards, strings.Join(f.Award, ";"))
+	}
+
+	return names, ids, awards
+}
+
+// ClinicalTrialNumbers returns the registration numbers of the clinical trials the article reports on
+func (a Article) ClinicalTrialNumbers() []string {
+	numbers := []string{}
+	for _, c := range a.ClinicalTrials {
+		if c.Number != "" {
+			numbers = append(numbers, c.Number)
+		}
+	}
+
+	return numbers
+}
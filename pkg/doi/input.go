@@ -0,0 +1,260 @@
+package doi
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// Format is how a list of DOIs is written
+type Format string
+
+const (
+	// Lines is one DOI, URL or citation per line
+	Lines  Format = "lines"
+	CSV    Format = "csv"
+	BibTeX Format = "bibtex"
+	RIS    Format = "ris"
+)
+
+// FormatOf guesses a file's format from its extension, defaulting to Lines
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV
+	case ".bib", ".bibtex":
+		return BibTeX
+	case ".ris":
+		return RIS
+	}
+
+	return Lines
+}
+
+// ParseFormat checks a format given on the command line
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(s))
+	switch f {
+	case Lines, CSV, BibTeX, RIS:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q (expected lines, csv, bibtex or ris)", s)
+}
+
+// Read returns the normalized DOIs in a list, without duplicates, in the order they're found
+// along with the entries that didn't have one e.g. a line of text or a BibTeX key
+// column is the CSV column holding the DOIs, or "" for the id column falling back to the doi column
+// like the CSVs papercut writes
+func Read(r io.Reader, format Format, column string) ([]string, []string, error) {
+	var entries []entry
+	var err error
+	switch format {
+	case CSV:
+		entries, err = readCSV(r, column)
+	case BibTeX:
+		entries, err = readBibTeX(r)
+	case RIS:
+		entries, err = readRIS(r)
+	default:
+		entries, err = readLines(r)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dois, skipped := []string{}, []string{}
+	for _, e := range entries {
+		found := e.dois()
+		if len(found) == 0 {
+			skipped = append(skipped, e.name)
+		}
+		for _, d := range found {
+			if !utils.StrInSlice(d, dois) {
+				dois = append(dois, d)
+			}
+		}
+	}
+
+	return dois, skipped, nil
+}
+
+// entry is an item in a list of DOIs
+type entry struct {
+	// name identifies the entry in messages about it
+	name string
+	// values are where the entry's DOIs should be
+	values []string
+	// fallback is searched for DOIs when values don't have any e.g. a reference's URL
+	fallback []string
+}
+
+func (e entry) dois() []string {
+	dois := []string{}
+	for _, v := range e.values {
+		if d, err := Normalize(v); err == nil {
+			dois = append(dois, d)
+		} else {
+			dois = append(dois, Extract(v)...)
+		}
+	}
+	if len(dois) > 0 {
+		return dois
+	}
+	for _, v := range e.fallback {
+		dois = append(dois, Extract(v)...)
+	}
+
+	return dois
+}
+
+func readLines(r io.Reader) ([]entry, error) {
+	entries := []entry{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			entries = append(entries, entry{name: line, values: []string{line}})
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+func readCSV(r io.Reader, column string) ([]entry, error) {
+	rd := csv.NewReader(r)
+	rd.FieldsPerRecord = -1
+	header, err := rd.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the CSV header: %v", err)
+	}
+	columnIndex := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name) {
+				return i
+			}
+		}
+		return -1
+	}
+	// without a column, rows are read from id and fall back to doi when their id isn't a DOI e.g. an arXiv ID
+	index, fallback := columnIndex(column), -1
+	if column == "" {
+		index, fallback = columnIndex("id"), columnIndex("doi")
+		if index < 0 {
+			index, fallback = fallback, -1
+		}
+		column = "id or doi"
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("the CSV has no %s column (it has %s)", column, strings.Join(header, ", "))
+	}
+
+	entries := []entry{}
+	for line := 2; ; line++ {
+		row, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		e := entry{name: fmt.Sprintf("line %d", line)}
+		if index < len(row) && strings.TrimSpace(row[index]) != "" {
+			e.name = row[index]
+			e.values = []string{row[index]}
+		}
+		if fallback >= 0 && fallback < len(row) && strings.TrimSpace(row[fallback]) != "" {
+			e.fallback = []string{row[fallback]}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+var (
+	bibtexEntry = regexp.MustCompile(`@(\w+)\s*[{(]\s*([^,\s]*)\s*,`)
+	bibtexField = regexp.MustCompile(`(?i)\b(doi|url)\s*=\s*(?:\{((?:[^{}]|\{[^{}]*\})*)\}|"([^"]*)")`)
+	// bibtexEscape is how reference managers escape LaTeX's special characters e.g. 10.1000/abc\_def
+	bibtexEscape = regexp.MustCompile(`\\([_%&#$])`)
+)
+
+// unescapeBibTeX turns a BibTeX field value back into plain text
+func unescapeBibTeX(value string) string {
+	value = bibtexEscape.ReplaceAllString(value, "$1")
+	return strings.NewReplacer("{", "", "}", "").Replace(value)
+}
+
+// readBibTeX reads the doi field of each entry, or a DOI in its url field if it doesn't have one
+func readBibTeX(r io.Reader) ([]entry, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := string(b)
+
+	entries := []entry{}
+	starts := bibtexEntry.FindAllStringSubmatchIndex(text, -1)
+	for i, s := range starts {
+		kind := strings.ToLower(text[s[2]:s[3]])
+		if kind == "comment" || kind == "string" || kind == "preamble" {
+			continue
+		}
+		end := len(text)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		e := entry{name: text[s[4]:s[5]]}
+		for _, f := range bibtexField.FindAllStringSubmatch(text[s[1]:end], -1) {
+			value := unescapeBibTeX(f[2] + f[3])
+			if strings.EqualFold(f[1], "doi") {
+				e.values = append(e.values, value)
+			} else {
+				e.fallback = append(e.fallback, value)
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+var risTag = regexp.MustCompile(`^([A-Z][A-Z0-9])  -\s?(.*)$`)
+
+// readRIS reads the DO tag of each reference, or a DOI in its UR or L3 tags if it doesn't have one
+func readRIS(r io.Reader) ([]entry, error) {
+	entries := []entry{}
+	e := entry{}
+	inRecord := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := risTag.FindStringSubmatch(strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r"))
+		if m == nil {
+			continue
+		}
+		tag, value := m[1], strings.TrimSpace(m[2])
+		switch tag {
+		case "TY":
+			e = entry{name: fmt.Sprintf("reference %d", len(entries)+1)}
+			inRecord = true
+		case "ER":
+			if inRecord {
+				entries = append(entries, e)
+			}
+			inRecord = false
+		case "DO":
+			e.values = append(e.values, value)
+		case "UR", "L3":
+			e.fallback = append(e.fallback, value)
+		case "TI", "T1":
+			e.name = value
+		}
+	}
+
+	return entries, scanner.Err()
+}
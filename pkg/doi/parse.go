@@ -0,0 +1,87 @@
+package doi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+var (
+	doiPattern = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+	// doiInText finds DOIs in free text, which are cleaned up by Normalize
+	doiInText = regexp.MustCompile(`(?i)\b10\.\d{4,9}/[^\s"<>]+`)
+	// doiPrefix matches the ways DOIs are written as URLs and URIs
+	doiPrefix = regexp.MustCompile(`(?i)^(?:(?:https?://)?(?:dx\.|www\.)?doi\.org/|doi:\s*|info:doi/|urn:doi:)`)
+)
+
+// Normalize turns a DOI written in any of the usual ways into its bare, lowercase form
+// e.g. https://doi.org/10.1000/XYZ123. and doi:10.1000/xyz123 are both 10.1000/xyz123
+// DOIs are case insensitive so lowercasing them keeps them from being cached twice
+func Normalize(s string) (string, error) {
+	d := strings.Trim(strings.TrimSpace(s), `<>"'`)
+	d = doiPrefix.ReplaceAllString(d, "")
+	if strings.Contains(d, "%") {
+		if unescaped, err := url.PathUnescape(d); err == nil {
+			d = unescaped
+		}
+	}
+	d = strings.ToLower(trimPunctuation(strings.TrimSpace(d)))
+	if !doiPattern.MatchString(d) {
+		return "", fmt.Errorf("%q isn't a DOI", s)
+	}
+
+	return d, nil
+}
+
+// Valid reports whether s is a DOI in any of the forms Normalize takes
+func Valid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// Extract returns the normalized DOIs found in free text like a citation, in the order they're found
+func Extract(text string) []string {
+	dois := []string{}
+	for _, m := range doiInText.FindAllString(text, -1) {
+		d, err := Normalize(m)
+		if err != nil {
+			continue
+		}
+		if !utils.StrInSlice(d, dois) {
+			dois = append(dois, d)
+		}
+	}
+
+	return dois
+}
+
+// trimPunctuation drops the punctuation that ends up after a DOI in a sentence or a list
+// closing brackets are only dropped when they don't close one in the DOI e.g. 10.1016/0002-9378(84)90121-0
+func trimPunctuation(d string) string {
+	for d != "" {
+		last := d[len(d)-1]
+		switch last {
+		case '.', ',', ';', ':', '\'', '"', '>':
+		case ')':
+			if strings.Count(d, "(") >= strings.Count(d, ")") {
+				return d
+			}
+		case ']':
+			if strings.Count(d, "[") >= strings.Count(d, "]") {
+				return d
+			}
+		case '}':
+			if strings.Count(d, "{") >= strings.Count(d, "}") {
+				return d
+			}
+		default:
+			return d
+		}
+		d = d[:len(d)-1]
+	}
+
+	return d
+}
@@ -0,0 +1,164 @@
+package doi_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"10.1000/xyz123":                          "10.1000/xyz123",
+		"  10.1000/XYZ123  ":                      "10.1000/xyz123",
+		"https://doi.org/10.1000/xyz123":          "10.1000/xyz123",
+		"http://dx.doi.org/10.1000/xyz123":        "10.1000/xyz123",
+		"doi.org/10.1000/xyz123":                  "10.1000/xyz123",
+		"doi:10.1000/xyz123":                      "10.1000/xyz123",
+		"DOI: 10.1000/xyz123":                     "10.1000/xyz123",
+		"info:doi/10.1000/xyz123":                 "10.1000/xyz123",
+		"https://doi.org/10.1000%2Fxyz123":        "10.1000/xyz123",
+		"<10.1000/xyz123>":                        "10.1000/xyz123",
+		"10.1000/xyz123.":                         "10.1000/xyz123",
+		"10.1000/xyz123),":                        "10.1000/xyz123",
+		"10.1016/0002-9378(84)90121-0":            "10.1016/0002-9378(84)90121-0",
+		"10.1002/(SICI)1097-4636(199706)35:4;2-5": "10.1002/(sici)1097-4636(199706)35:4;2-5",
+	}
+	for input, expected := range tests {
+		d, err := doi.Normalize(input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", input, err)
+			continue
+		}
+		if d != expected {
+			t.Errorf("expected %q to be %s, got %s", input, expected, d)
+		}
+	}
+
+	for _, input := range []string{"", "xyz123", "10.1/too-short-a-prefix", "https://example.org/10.1000", "10.1000/"} {
+		if d, err := doi.Normalize(input); err == nil {
+			t.Errorf("expected %q not to be a DOI, got %s", input, d)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	text := `Smith, J. (2020). Ranking. SIGIR. https://doi.org/10.1145/3397271.3401001. See also doi:10.1000/XYZ123; and (10.1000/xyz123).`
+	expected := []string{"10.1145/3397271.3401001", "10.1000/xyz123"}
+	if dois := doi.Extract(text); !reflect.DeepEqual(dois, expected) {
+		t.Errorf("expected %v, got %v", expected, dois)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  doi.Format
+		input   string
+		dois    []string
+		skipped []string
+	}{
+		{
+			name:   "lines",
+			format: doi.Lines,
+			input: `10.1000/xyz123
+https://doi.org/10.1000/XYZ123
+
+Smith, J. Ranking. doi:10.1145/3397271.3401001.
+not a doi
+`,
+			dois:    []string{"10.1000/xyz123", "10.1145/3397271.3401001"},
+			skipped: []string{"not a doi"},
+		},
+		{
+			name:   "csv",
+			format: doi.CSV,
+			input: "\ufefftitle,DOI\n" +
+				"Ranking,https://doi.org/10.1145/3397271.3401001\n" +
+				"Nothing,\n" +
+				`"Quoted, title",doi:10.1000/xyz123` + "\n",
+			dois:    []string{"10.1145/3397271.3401001", "10.1000/xyz123"},
+			skipped: []string{"line 3"},
+		},
+		{
+			name:   "bibtex",
+			format: doi.BibTeX,
+			input: `@comment{exported from a reference manager}
+@inproceedings{smith2020,
+  title = {Ranking {Everything}},
+  doi = {10.1145/3397271.3401001},
+}
+@article{doe2021,
+  title = "A Preprint",
+  url = "https://doi.org/10.1000/XYZ123",
+}
+@misc{nodoi, title = {No DOI}, url = {https://example.org}}
+@article{escaped,
+  doi = {10.1000/abc\_def\&{G}h},
+}
+`,
+			dois:    []string{"10.1145/3397271.3401001", "10.1000/xyz123", "10.1000/abc_def&gh"},
+			skipped: []string{"nodoi"},
+		},
+		{
+			name:   "ris",
+			format: doi.RIS,
+			input: "TY  - JOUR\r\nTI  - Ranking\r\nDO  - 10.1145/3397271.3401001\r\nER  - \r\n" +
+				"TY  - JOUR\nTI  - A Preprint\nUR  - https://doi.org/10.1000/xyz123\nER  -\n" +
+				"TY  - BOOK\nTI  - No DOI\nER  - \n",
+			dois:    []string{"10.1145/3397271.3401001", "10.1000/xyz123"},
+			skipped: []string{"No DOI"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dois, skipped, err := doi.Read(strings.NewReader(tt.input), tt.format, "doi")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dois, tt.dois) {
+				t.Errorf("expected %v, got %v", tt.dois, dois)
+			}
+			if !reflect.DeepEqual(skipped, tt.skipped) {
+				t.Errorf("expected %v to be skipped, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+
+	if _, _, err := doi.Read(strings.NewReader("title,id\n"), doi.CSV, "doi"); err == nil {
+		t.Error("expected an error for a CSV without the column")
+	}
+
+	// like a CSV written by papercut, where rows without a DOI id can have a doi column
+	dois, skipped, err := doi.Read(strings.NewReader("id,title,doi\n10.1000/abc,One,\n2101.00001,Two,10.48550/arXiv.2101.00001\n,Three,10.1000/def\n"), doi.CSV, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"10.1000/abc", "10.48550/arxiv.2101.00001", "10.1000/def"}
+	if !reflect.DeepEqual(dois, expected) || len(skipped) != 0 {
+		t.Errorf("expected %v from id and doi, got %v, skipping %v", expected, dois, skipped)
+	}
+	if _, _, err := doi.Read(strings.NewReader("title,DOI\nOne,10.1000/abc\n"), doi.CSV, ""); err != nil {
+		t.Errorf("expected the doi column to be used without an id column, got %v", err)
+	}
+	if _, _, err := doi.Read(strings.NewReader("title,url\n"), doi.CSV, ""); err == nil {
+		t.Error("expected an error for a CSV without an id or doi column")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]doi.Format{
+		"dois.txt":        doi.Lines,
+		"dois":            doi.Lines,
+		"articles.CSV":    doi.CSV,
+		"library.bib":     doi.BibTeX,
+		"export.ris":      doi.RIS,
+		"/tmp/x.y/list.b": doi.Lines,
+	}
+	for path, expected := range tests {
+		if f := doi.FormatOf(path); f != expected {
+			t.Errorf("expected %s to be %s, got %s", path, expected, f)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// node types
+const (
+	Faculty = "faculty"
+	Cited   = "cited"
+	Citing  = "citing"
+)
+
+type Node struct {
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	Type  string `json:"type"`
+}
+
+// Edge points from the citing work to the cited work
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Graph is a citation network keyed by lowercased DOI
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	nodes map[string]int
+	edges map[Edge]bool
+}
+
+func New() *Graph {
+	return &Graph{
+		Nodes: []Node{},
+		Edges: []Edge{},
+		nodes: map[string]int{},
+		edges: map[Edge]bool{},
+	}
+}
+
+// AddNode adds a work to the graph
+// a faculty node replaces a cited or citing node with the same ID
+func (g *Graph) AddNode(id, label, nodeType string) {
+	id = strings.ToLower(id)
+	if i, ok := g.nodes[id]; ok {
+		if nodeType == Faculty {
+			g.Nodes[i].Type = Faculty
+		}
+		if g.Nodes[i].Label == "" {
+			g.Nodes[i].Label = label
+		}
+		return
+	}
+
+	g.nodes[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, Node{
+		ID:    id,
+		Label: label,
+		Type:  nodeType,
+	})
+}
+
+// AddCitation adds an edge from the citing to the cited work
+// adding nodes for either work if they're not in the graph yet
+func (g *Graph) AddCitation(citing, cited string) {
+	g.AddNode(citing, "", Citing)
+	g.AddNode(cited, "", Cited)
+
+	e := Edge{
+		Source: strings.ToLower(citing),
+		Target: strings.ToLower(cited),
+	}
+	if g.edges[e] || e.Source == e.Target {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+// WriteEdges writes the graph as a CSV edge list
+func (g *Graph) WriteEdges(w io.Writer) error {
+	wr := csv.NewWriter(w)
+	err := wr.Write([]string{"source", "target"})
+	if err != nil {
+		return err
+	}
+	for _, e := range g.Edges {
+		err = wr.Write([]string{e.Source, e.Target})
+		if err != nil {
+			return err
+		}
+	}
+	wr.Flush()
+
+	return wr.Error()
+}
+
+// WriteNodes writes the graph's nodes as CSV
+func (g *Graph) WriteNodes(w io.Writer) error {
+	wr := csv.NewWriter(w)
+	err := wr.Write([]string{"id", "label", "type"})
+	if err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		err = wr.Write([]string{n.ID, n.Label, n.Type})
+		if err != nil {
+			return err
+		}
+	}
+	wr.Flush()
+
+	return wr.Error()
+}
+
+// WriteJSON writes the graph as a JSON object of nodes and edges
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(g)
+}
+
+type graphML struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graph   graphMLContent `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLContent struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as a directed GraphML document
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+		},
+		Graph: graphMLContent{
+			ID:          "citations",
+			EdgeDefault: "directed",
+		},
+	}
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "label", Value: n.Label},
+				{Key: "type", Value: n.Type},
+			},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge(e))
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,91 @@
+package graph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/graph"
+)
+
+func testGraph() *graph.Graph {
+	g := graph.New()
+	g.AddNode("10.1000/ABC", "Faculty paper", graph.Faculty)
+	g.AddCitation("10.1000/abc", "10.1000/cited")
+	g.AddCitation("10.1000/abc", "10.1000/cited")
+	g.AddCitation("10.1000/citing", "10.1000/ABC")
+
+	return g
+}
+
+func TestAddCitation(t *testing.T) {
+	g := testGraph()
+
+	if len(g.Nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d: %v", len(g.Nodes), g.Nodes)
+	}
+	if len(g.Edges) != 2 {
+		t.Fatalf("Expected duplicate edges to be dropped, got %v", g.Edges)
+	}
+	expected := []graph.Node{
+		{ID: "10.1000/abc", Label: "Faculty paper", Type: graph.Faculty},
+		{ID: "10.1000/cited", Type: graph.Cited},
+		{ID: "10.1000/citing", Type: graph.Citing},
+	}
+	for i, n := range expected {
+		if g.Nodes[i] != n {
+			t.Errorf("Expected node %v, got %v", n, g.Nodes[i])
+		}
+	}
+}
+
+func TestWriteEdges(t *testing.T) {
+	var b bytes.Buffer
+	if err := testGraph().WriteEdges(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "source,target\n10.1000/abc,10.1000/cited\n10.1000/citing,10.1000/abc\n"
+	if b.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := testGraph().WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var g graph.Graph
+	if err := json.Unmarshal(b.Bytes(), &g); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 3 || len(g.Edges) != 2 {
+		t.Errorf("Unexpected graph %v", g)
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var b bytes.Buffer
+	if err := testGraph().WriteGraphML(&b); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Nodes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+		} `xml:"graph>edge"`
+	}
+	if err := xml.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("Unable to parse GraphML: %v", err)
+	}
+	if len(doc.Nodes) != 3 || len(doc.Edges) != 2 {
+		t.Errorf("Unexpected GraphML %s", b.String())
+	}
+	if doc.Edges[1].Source != "10.1000/citing" || doc.Edges[1].Target != "10.1000/abc" {
+		t.Errorf("Unexpected edge %v", doc.Edges[1])
+	}
+}
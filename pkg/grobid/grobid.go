@@ -0,0 +1,203 @@
+package grobid
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// Document is the structured data GROBID was able to extract from a PDF
+type Document struct {
+	Title      string      `json:"title"`
+	Abstract   string      `json:"abstract"`
+	Authors    []Author    `json:"authors"`
+	Sections   []string    `json:"sections"`
+	References []Reference `json:"references"`
+}
+
+type Author struct {
+	Name         string   `json:"name"`
+	Surname      string   `json:"surname"`
+	Affiliations []string `json:"affiliations,omitempty"`
+}
+
+type Reference struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Authors   []string `json:"authors,omitempty"`
+	Container string   `json:"container,omitempty"`
+	Date      string   `json:"date,omitempty"`
+	DOI       string   `json:"doi,omitempty"`
+	Raw       string   `json:"raw,omitempty"`
+}
+
+// ProcessPdf sends a PDF to a GROBID server's processFulltextDocument endpoint
+// caching the TEI response in cacheFile
+func ProcessPdf(url, pdf, cacheFile string) (Document, error) {
+	tei := utils.CheckCachedFile(cacheFile)
+	if tei == nil {
+		var err error
+		tei, err = postPdf(url, pdf)
+		if err != nil {
+			return Document{}, err
+		}
+		utils.WriteCachedFile(cacheFile, string(tei))
+	}
+
+	return ParseTEI(tei)
+}
+
+func postPdf(url, pdf string) ([]byte, error) {
+	file, err := os.Open(pdf)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("input", filepath.Base(pdf))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(part, file); err != nil {
+		return nil, err
+	}
+	if err = w.WriteField("includeRawCitations", "1"); err != nil {
+		return nil, err
+	}
+	if err = w.WriteField("includeRawAffiliations", "1"); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/api/processFulltextDocument", strings.TrimSuffix(url, "/"))
+	req, err := http.NewRequest("POST", endpoint, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned a non-200 status code: %d", endpoint, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// ParseTEI reads the header, section headings and bibliography from a TEI document
+func ParseTEI(body []byte) (Document, error) {
+	var tei TEI
+	err := xml.Unmarshal(body, &tei)
+	if err != nil {
+		return Document{}, fmt.Errorf("could not unmarshal TEI: %v", err)
+	}
+
+	doc := Document{
+		Title:    strings.TrimSpace(tei.Title),
+		Abstract: tei.Abstract.String(),
+	}
+	for _, a := range tei.Authors {
+		doc.Authors = append(doc.Authors, newAuthor(a))
+	}
+	for _, s := range tei.Sections {
+		head := strings.TrimSpace(s.Head.Value)
+		if head == "" {
+			continue
+		}
+		if s.Head.N != "" {
+			head = fmt.Sprintf("%s %s", s.Head.N, head)
+		}
+		doc.Sections = append(doc.Sections, head)
+	}
+	for _, b := range tei.References {
+		doc.References = append(doc.References, newReference(b))
+	}
+
+	return doc, nil
+}
+
+// AffiliationsFor returns the affiliations GROBID found for an author with the given surname
+func (d Document) AffiliationsFor(surname string) []string {
+	for _, a := range d.Authors {
+		if strings.EqualFold(a.Surname, surname) {
+			return a.Affiliations
+		}
+	}
+
+	return nil
+}
+
+func newAuthor(a TeiAuthor) Author {
+	author := Author{
+		Name:    a.PersName.Name(),
+		Surname: a.PersName.Surname,
+	}
+	for _, aff := range a.Affiliations {
+		name := aff.Name()
+		if name != "" && !utils.StrInSlice(name, author.Affiliations) {
+			author.Affiliations = append(author.Affiliations, name)
+		}
+	}
+
+	return author
+}
+
+func newReference(b BiblStruct) Reference {
+	r := Reference{
+		ID:   b.ID,
+		Date: b.Monogr.Date.When,
+	}
+
+	// articles have an analytic title and the journal in monogr
+	// books only have monogr
+	part := b.Analytic
+	if len(part.Titles) == 0 {
+		part = b.Monogr
+	} else if len(b.Monogr.Titles) > 0 {
+		r.Container = strings.TrimSpace(b.Monogr.Titles[0].Value)
+	}
+	if len(part.Titles) > 0 {
+		r.Title = strings.TrimSpace(part.Titles[0].Value)
+	}
+	for _, a := range part.Authors {
+		if a.PersName.Surname != "" {
+			r.Authors = append(r.Authors, a.PersName.Name())
+		}
+	}
+
+	idnos := []Idno{}
+	idnos = append(idnos, b.Analytic.Idnos...)
+	idnos = append(idnos, b.Monogr.Idnos...)
+	idnos = append(idnos, b.Idnos...)
+	for _, i := range idnos {
+		if strings.EqualFold(i.Type, "DOI") {
+			r.DOI = strings.TrimSpace(i.Value)
+			break
+		}
+	}
+	for _, n := range b.Notes {
+		if n.Type == "raw_reference" {
+			r.Raw = strings.TrimSpace(n.Value)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,152 @@
+package grobid_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/grobid"
+)
+
+const tei = `<?xml version="1.0" encoding="UTF-8"?>
+<TEI xmlns="http://www.tei-c.org/ns/1.0">
+	<teiHeader>
+		<fileDesc>
+			<titleStmt>
+				<title level="a" type="main">A Test Paper</title>
+			</titleStmt>
+			<sourceDesc>
+				<biblStruct>
+					<analytic>
+						<author>
+							<persName><forename type="first">Jane</forename><forename type="middle">Q</forename><surname>Doe</surname></persName>
+							<affiliation key="aff0">
+								<orgName type="department">Libraries</orgName>
+								<orgName type="institution">Lehigh University</orgName>
+								<address><country key="US">USA</country></address>
+							</affiliation>
+						</author>
+						<author>
+							<persName><forename type="first">John</forename><surname>Smith</surname></persName>
+						</author>
+					</analytic>
+				</biblStruct>
+			</sourceDesc>
+		</fileDesc>
+		<profileDesc>
+			<abstract>
+				<div><p>This is the <hi rend="italic">abstract</hi>.</p>
+				<p>Second paragraph.</p></div>
+			</abstract>
+		</profileDesc>
+	</teiHeader>
+	<text>
+		<body>
+			<div><head n="1">Introduction</head><p>text</p></div>
+			<div><head>Conclusion</head><p>text</p></div>
+		</body>
+		<back>
+			<div type="references">
+				<listBibl>
+					<biblStruct xml:id="b0">
+						<analytic>
+							<title level="a" type="main">Cited article</title>
+							<author><persName><forename type="first">A</forename><surname>Author</surname></persName></author>
+							<idno type="DOI">10.1000/xyz</idno>
+						</analytic>
+						<monogr>
+							<title level="j">Journal of Tests</title>
+							<imprint><date type="published" when="2019"/></imprint>
+						</monogr>
+						<note type="raw_reference">A. Author. Cited article. Journal of Tests, 2019.</note>
+					</biblStruct>
+					<biblStruct xml:id="b1">
+						<monogr>
+							<title level="m">A Book</title>
+							<author><persName><surname>Writer</surname></persName></author>
+							<imprint><date type="published" when="2001"/></imprint>
+						</monogr>
+					</biblStruct>
+				</listBibl>
+			</div>
+		</back>
+	</text>
+</TEI>`
+
+func TestProcessPdf(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/processFulltextDocument" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if _, _, err := r.FormFile("input"); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintln(w, tei)
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	pdf := filepath.Join(dir, "test.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cacheFile := filepath.Join(dir, "grobid.tei.xml")
+
+	doc, err := grobid.ProcessPdf(ts.URL, pdf, cacheFile)
+	if err != nil {
+		t.Fatalf("ProcessPdf returned error: %v", err)
+	}
+
+	if doc.Title != "A Test Paper" {
+		t.Errorf("Expected title 'A Test Paper', got %q", doc.Title)
+	}
+	if doc.Abstract != "This is the abstract. Second paragraph." {
+		t.Errorf("Unexpected abstract %q", doc.Abstract)
+	}
+	if !reflect.DeepEqual(doc.Sections, []string{"1 Introduction", "Conclusion"}) {
+		t.Errorf("Unexpected sections %v", doc.Sections)
+	}
+	expectedAuthors := []grobid.Author{
+		{Name: "Doe, Jane Q", Surname: "Doe", Affiliations: []string{"Libraries, Lehigh University, USA"}},
+		{Name: "Smith, John", Surname: "Smith"},
+	}
+	if !reflect.DeepEqual(doc.Authors, expectedAuthors) {
+		t.Errorf("Expected authors %v, got %v", expectedAuthors, doc.Authors)
+	}
+	if got := doc.AffiliationsFor("doe"); len(got) != 1 {
+		t.Errorf("Expected one affiliation for Doe, got %v", got)
+	}
+	expectedRefs := []grobid.Reference{
+		{
+			ID:        "b0",
+			Title:     "Cited article",
+			Authors:   []string{"Author, A"},
+			Container: "Journal of Tests",
+			Date:      "2019",
+			DOI:       "10.1000/xyz",
+			Raw:       "A. Author. Cited article. Journal of Tests, 2019.",
+		},
+		{
+			ID:      "b1",
+			Title:   "A Book",
+			Authors: []string{"Writer"},
+			Date:    "2001",
+		},
+	}
+	if !reflect.DeepEqual(doc.References, expectedRefs) {
+		t.Errorf("Expected references %v, got %v", expectedRefs, doc.References)
+	}
+
+	// the TEI should be cached so a second call doesn't hit the server
+	ts.Close()
+	if _, err := grobid.ProcessPdf(ts.URL, pdf, cacheFile); err != nil {
+		t.Errorf("Expected cached TEI to be used, got error: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package grobid
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// TEI represents the parts of a GROBID TEI document we make use of
+type TEI struct {
+	XMLName    xml.Name     `xml:"http://www.tei-c.org/ns/1.0 TEI"`
+	Title      string       `xml:"teiHeader>fileDesc>titleStmt>title"`
+	Authors    []TeiAuthor  `xml:"teiHeader>fileDesc>sourceDesc>biblStruct>analytic>author"`
+	Abstract   Text         `xml:"teiHeader>profileDesc>abstract"`
+	Sections   []TeiSection `xml:"text>body>div"`
+	References []BiblStruct `xml:"text>back>div>listBibl>biblStruct"`
+}
+
+type TeiAuthor struct {
+	PersName     PersName      `xml:"persName"`
+	Email        string        `xml:"email"`
+	Affiliations []Affiliation `xml:"affiliation"`
+}
+
+type PersName struct {
+	Forenames []string `xml:"forename"`
+	Surname   string   `xml:"surname"`
+}
+
+type Affiliation struct {
+	Key      string    `xml:"key,attr"`
+	OrgNames []OrgName `xml:"orgName"`
+	Country  string    `xml:"address>country"`
+}
+
+type OrgName struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type TeiSection struct {
+	Head Head `xml:"head"`
+}
+
+type Head struct {
+	N     string `xml:"n,attr"`
+	Value string `xml:",chardata"`
+}
+
+type BiblStruct struct {
+	ID       string   `xml:"http://www.w3.org/XML/1998/namespace id,attr"`
+	Analytic BiblPart `xml:"analytic"`
+	Monogr   BiblPart `xml:"monogr"`
+	Notes    []Note   `xml:"note"`
+	Idnos    []Idno   `xml:"idno"`
+}
+
+type BiblPart struct {
+	Titles  []Title     `xml:"title"`
+	Authors []TeiAuthor `xml:"author"`
+	Idnos   []Idno      `xml:"idno"`
+	Date    ImprintDate `xml:"imprint>date"`
+}
+
+type Title struct {
+	Level string `xml:"level,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Idno struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Note struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type ImprintDate struct {
+	When string `xml:"when,attr"`
+}
+
+// Text collects all character data below an element, e.g. the <p> tags of an abstract
+type Text struct {
+	Value string `xml:",innerxml"`
+}
+
+// String strips any markup from the element's contents
+func (t Text) String() string {
+	d := xml.NewDecoder(strings.NewReader(t.Value))
+	var b strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if c, ok := tok.(xml.CharData); ok {
+			b.Write(c)
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Name returns the author name as "Family, Given"
+func (p PersName) Name() string {
+	given := strings.Join(p.Forenames, " ")
+	if given == "" {
+		return p.Surname
+	}
+	return p.Surname + ", " + given
+}
+
+// Name returns the affiliation as a comma separated list of its organizations
+func (a Affiliation) Name() string {
+	var parts []string
+	for _, o := range a.OrgNames {
+		o.Value = strings.TrimSpace(o.Value)
+		if o.Value != "" {
+			parts = append(parts, o.Value)
+		}
+	}
+	if a.Country != "" {
+		parts = append(parts, strings.TrimSpace(a.Country))
+	}
+
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,39 @@
+package latex
+
+// accents maps a LaTeX accent command and base letter to the precomposed character
+var accents = map[string]map[string]string{
+	"\"": {"A": "Ä", "E": "Ë", "H": "Ḧ", "I": "Ï", "O": "Ö", "U": "Ü", "W": "Ẅ", "X": "Ẍ", "Y": "Ÿ", "a": "ä", "e": "ë", "h": "ḧ", "i": "ï", "o": "ö", "t": "ẗ", "u": "ü", "w": "ẅ", "x": "ẍ", "y": "ÿ", "ı": "ï"},
+	"'":  {"A": "Á", "C": "Ć", "E": "É", "G": "Ǵ", "I": "Í", "K": "Ḱ", "L": "Ĺ", "M": "Ḿ", "N": "Ń", "O": "Ó", "P": "Ṕ", "R": "Ŕ", "S": "Ś", "U": "Ú", "W": "Ẃ", "Y": "Ý", "Z": "Ź", "a": "á", "c": "ć", "e": "é", "g": "ǵ", "i": "í", "k": "ḱ", "l": "ĺ", "m": "ḿ", "n": "ń", "o": "ó", "p": "ṕ", "r": "ŕ", "s": "ś", "u": "ú", "w": "ẃ", "y": "ý", "z": "ź", "ı": "í"},
+	"`":  {"A": "À", "E": "È", "I": "Ì", "N": "Ǹ", "O": "Ò", "U": "Ù", "W": "Ẁ", "Y": "Ỳ", "a": "à", "e": "è", "i": "ì", "n": "ǹ", "o": "ò", "u": "ù", "w": "ẁ", "y": "ỳ", "ı": "ì"},
+	"^":  {"A": "Â", "C": "Ĉ", "E": "Ê", "G": "Ĝ", "H": "Ĥ", "I": "Î", "J": "Ĵ", "O": "Ô", "S": "Ŝ", "U": "Û", "W": "Ŵ", "Y": "Ŷ", "Z": "Ẑ", "a": "â", "c": "ĉ", "e": "ê", "g": "ĝ", "h": "ĥ", "i": "î", "j": "ĵ", "o": "ô", "s": "ŝ", "u": "û", "w": "ŵ", "y": "ŷ", "z": "ẑ", "ı": "î"},
+	"~":  {"A": "Ã", "E": "Ẽ", "I": "Ĩ", "N": "Ñ", "O": "Õ", "U": "Ũ", "V": "Ṽ", "Y": "Ỹ", "a": "ã", "e": "ẽ", "i": "ĩ", "n": "ñ", "o": "õ", "u": "ũ", "v": "ṽ", "y": "ỹ", "ı": "ĩ"},
+	"=":  {"A": "Ā", "E": "Ē", "G": "Ḡ", "I": "Ī", "O": "Ō", "U": "Ū", "Y": "Ȳ", "a": "ā", "e": "ē", "g": "ḡ", "i": "ī", "o": "ō", "u": "ū", "y": "ȳ", "ı": "ī"},
+	".":  {"A": "Ȧ", "B": "Ḃ", "C": "Ċ", "D": "Ḋ", "E": "Ė", "F": "Ḟ", "G": "Ġ", "H": "Ḣ", "I": "İ", "M": "Ṁ", "N": "Ṅ", "O": "Ȯ", "P": "Ṗ", "R": "Ṙ", "S": "Ṡ", "T": "Ṫ", "W": "Ẇ", "X": "Ẋ", "Y": "Ẏ", "Z": "Ż", "a": "ȧ", "b": "ḃ", "c": "ċ", "d": "ḋ", "e": "ė", "f": "ḟ", "g": "ġ", "h": "ḣ", "m": "ṁ", "n": "ṅ", "o": "ȯ", "p": "ṗ", "r": "ṙ", "s": "ṡ", "t": "ṫ", "w": "ẇ", "x": "ẋ", "y": "ẏ", "z": "ż"},
+	"u":  {"A": "Ă", "E": "Ĕ", "G": "Ğ", "I": "Ĭ", "O": "Ŏ", "U": "Ŭ", "a": "ă", "e": "ĕ", "g": "ğ", "i": "ĭ", "o": "ŏ", "u": "ŭ", "ı": "ĭ"},
+	"v":  {"A": "Ǎ", "C": "Č", "D": "Ď", "E": "Ě", "G": "Ǧ", "H": "Ȟ", "I": "Ǐ", "K": "Ǩ", "L": "Ľ", "N": "Ň", "O": "Ǒ", "R": "Ř", "S": "Š", "T": "Ť", "U": "Ǔ", "Z": "Ž", "a": "ǎ", "c": "č", "d": "ď", "e": "ě", "g": "ǧ", "h": "ȟ", "i": "ǐ", "j": "ǰ", "k": "ǩ", "l": "ľ", "n": "ň", "o": "ǒ", "r": "ř", "s": "š", "t": "ť", "u": "ǔ", "z": "ž", "ı": "ǐ"},
+	"H":  {"O": "Ő", "U": "Ű", "o": "ő", "u": "ű"},
+	"c":  {"C": "Ç", "D": "Ḑ", "E": "Ȩ", "G": "Ģ", "H": "Ḩ", "K": "Ķ", "L": "Ļ", "N": "Ņ", "R": "Ŗ", "S": "Ş", "T": "Ţ", "c": "ç", "d": "ḑ", "e": "ȩ", "g": "ģ", "h": "ḩ", "k": "ķ", "l": "ļ", "n": "ņ", "r": "ŗ", "s": "ş", "t": "ţ"},
+	"k":  {"A": "Ą", "E": "Ę", "I": "Į", "O": "Ǫ", "U": "Ų", "a": "ą", "e": "ę", "i": "į", "o": "ǫ", "u": "ų"},
+	"r":  {"A": "Å", "U": "Ů", "a": "å", "u": "ů", "w": "ẘ", "y": "ẙ"},
+	"d":  {"A": "Ạ", "B": "Ḅ", "D": "Ḍ", "E": "Ẹ", "H": "Ḥ", "I": "Ị", "K": "Ḳ", "L": "Ḷ", "M": "Ṃ", "N": "Ṇ", "O": "Ọ", "R": "Ṛ", "S": "Ṣ", "T": "Ṭ", "U": "Ụ", "V": "Ṿ", "W": "Ẉ", "Y": "Ỵ", "Z": "Ẓ", "a": "ạ", "b": "ḅ", "d": "ḍ", "e": "ẹ", "h": "ḥ", "i": "ị", "k": "ḳ", "l": "ḷ", "m": "ṃ", "n": "ṇ", "o": "ọ", "r": "ṛ", "s": "ṣ", "t": "ṭ", "u": "ụ", "v": "ṿ", "w": "ẉ", "y": "ỵ", "z": "ẓ"},
+	"b":  {"B": "Ḇ", "D": "Ḏ", "K": "Ḵ", "L": "Ḻ", "N": "Ṉ", "R": "Ṟ", "T": "Ṯ", "Z": "Ẕ", "b": "ḇ", "d": "ḏ", "h": "ẖ", "k": "ḵ", "l": "ḻ", "n": "ṉ", "r": "ṟ", "t": "ṯ", "z": "ẕ"},
+}
+
+// combining is used for accents we don't have a precomposed character for
+var combining = map[string]string{
+	"\"": "\u0308",
+	"'":  "\u0301",
+	"`":  "\u0300",
+	"^":  "\u0302",
+	"~":  "\u0303",
+	"=":  "\u0304",
+	".":  "\u0307",
+	"u":  "\u0306",
+	"v":  "\u030c",
+	"H":  "\u030b",
+	"c":  "\u0327",
+	"k":  "\u0328",
+	"r":  "\u030a",
+	"d":  "\u0323",
+	"b":  "\u0331",
+}
@@ -0,0 +1,330 @@
+package latex
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MathMode controls what happens to $...$ math when converting LaTeX
+type MathMode string
+
+const (
+	// Unicode converts math into (approximate) plain Unicode text e.g. $\alpha^2$ becomes α²
+	Unicode MathMode = "unicode"
+	// TeX leaves math as $...$
+	TeX MathMode = "tex"
+	// MathML converts math into an inline MathML <math> element
+	MathML MathMode = "mathml"
+)
+
+func ParseMathMode(s string) (MathMode, error) {
+	switch m := MathMode(strings.ToLower(s)); m {
+	case Unicode, TeX, MathML:
+		return m, nil
+	}
+
+	return "", fmt.Errorf("unknown math mode %q (expected unicode, tex or mathml)", s)
+}
+
+// ToUnicode converts LaTeX accents, macros and font commands in s into Unicode text
+// whitespace (including new lines) is left untouched
+func ToUnicode(s string, mode MathMode) string {
+	c := converter{
+		runes: []rune(s),
+		mode:  mode,
+	}
+
+	return c.text(false)
+}
+
+type converter struct {
+	runes []rune
+	pos   int
+	mode  MathMode
+	// literal is set inside \texttt{...} where ~, -- and quotes are typed as they are e.g. code or CLI flags
+	literal bool
+}
+
+func (c *converter) peek(offset int) rune {
+	if c.pos+offset >= len(c.runes) {
+		return 0
+	}
+	return c.runes[c.pos+offset]
+}
+
+// text converts text mode LaTeX until the end of input or, in a group, the closing brace
+func (c *converter) text(inGroup bool) string {
+	var b strings.Builder
+	for c.pos < len(c.runes) {
+		r := c.runes[c.pos]
+		switch {
+		case r == '}':
+			c.pos++
+			if inGroup {
+				return b.String()
+			}
+		case r == '{':
+			c.pos++
+			b.WriteString(c.text(true))
+		case r == '$' || (r == '\\' && (c.peek(1) == '(' || c.peek(1) == '[')):
+			math, ok := c.math()
+			if !ok {
+				b.WriteRune(r)
+				c.pos++
+				continue
+			}
+			b.WriteString(math)
+		case r == '\\':
+			b.WriteString(c.command())
+		case c.literal:
+			c.pos++
+			b.WriteRune(r)
+		case c.atURL():
+			b.WriteString(c.url())
+		case r == '~':
+			c.pos++
+			b.WriteRune(' ')
+		case r == '-' && c.peek(1) == '-' && c.peek(2) != '-' && c.atFlag():
+			// a command line flag e.g. --verbose
+			c.pos += 2
+			b.WriteString("--")
+		case r == '-' && c.peek(1) == '-':
+			if c.peek(2) == '-' {
+				c.pos += 3
+				b.WriteRune('—')
+			} else {
+				c.pos += 2
+				b.WriteRune('–')
+			}
+		case r == '`' && c.peek(1) == '`':
+			c.pos += 2
+			b.WriteRune('“')
+		case r == '\'' && c.peek(1) == '\'':
+			c.pos += 2
+			b.WriteRune('”')
+		default:
+			c.pos++
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// name reads a command name after a backslash
+func (c *converter) name() string {
+	start := c.pos
+	if c.pos < len(c.runes) && !isLetter(c.runes[c.pos]) {
+		c.pos++
+		return string(c.runes[start:c.pos])
+	}
+	for c.pos < len(c.runes) && isLetter(c.runes[c.pos]) {
+		c.pos++
+	}
+
+	return string(c.runes[start:c.pos])
+}
+
+func (c *converter) skipSpaces() {
+	for c.pos < len(c.runes) && c.runes[c.pos] == ' ' {
+		c.pos++
+	}
+}
+
+// argument reads a command argument in text mode: a {group}, a command or a single character
+func (c *converter) argument() string {
+	c.skipSpaces()
+	if c.pos >= len(c.runes) {
+		return ""
+	}
+	switch r := c.runes[c.pos]; r {
+	case '{':
+		c.pos++
+		return c.text(true)
+	case '\\':
+		return c.command()
+	default:
+		c.pos++
+		return string(r)
+	}
+}
+
+// command converts a text mode command starting at the backslash
+func (c *converter) command() string {
+	start := c.pos
+	c.pos++
+	name := c.name()
+	letters := name != "" && isLetter([]rune(name)[0])
+
+	if _, ok := combining[name]; ok {
+		return accent(name, c.argument())
+	}
+	if s, ok := textSymbols[name]; ok {
+		if letters {
+			c.swallowSpace()
+		}
+		return s
+	}
+	if fontSwitches[name] {
+		c.swallowSpace()
+		return ""
+	}
+	if textArguments[name] {
+		return c.argument()
+	}
+
+	switch name {
+	case "url", "path":
+		return c.verbatim()
+	case "texttt":
+		literal := c.literal
+		c.literal = true
+		arg := c.argument()
+		c.literal = literal
+		return arg
+	case "href":
+		c.argument()
+		return c.argument()
+	case "\\", " ", "/":
+		return " "
+	case "&", "%", "_", "#", "$", "{", "}":
+		return name
+	case "-":
+		// discretionary hyphen
+		return ""
+	}
+
+	// leave unknown commands as we found them
+	return string(c.runes[start:c.pos])
+}
+
+// verbatim reads a {group} as it's written, except for escaped characters, e.g. the URL in \url{...}
+func (c *converter) verbatim() string {
+	c.skipSpaces()
+	if c.peek(0) != '{' {
+		return c.argument()
+	}
+	c.pos++
+	var b strings.Builder
+	for depth := 0; c.pos < len(c.runes); c.pos++ {
+		r := c.runes[c.pos]
+		switch {
+		case r == '\\' && strings.ContainsRune(`%#&_~{}$`, c.peek(1)):
+			c.pos++
+			r = c.runes[c.pos]
+		case r == '{':
+			depth++
+		case r == '}':
+			if depth == 0 {
+				c.pos++
+				return b.String()
+			}
+			depth--
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// atURL reports whether a URL starts at the current position e.g. https://example.org/~user
+func (c *converter) atURL() bool {
+	if c.pos > 0 && (isLetter(c.runes[c.pos-1]) || unicode.IsDigit(c.runes[c.pos-1])) {
+		return false
+	}
+	rest := strings.ToLower(string(c.runes[c.pos:min(c.pos+8, len(c.runes))]))
+	for _, prefix := range []string{"https://", "http://", "ftp://", "www."} {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// url copies a URL in the text as it is, up to the next space or brace
+func (c *converter) url() string {
+	start := c.pos
+	for c.pos < len(c.runes) && !unicode.IsSpace(c.runes[c.pos]) && !strings.ContainsRune("{}", c.runes[c.pos]) {
+		c.pos++
+	}
+
+	return string(c.runes[start:c.pos])
+}
+
+// atFlag reports whether the -- at the current position starts a command line flag
+// rather than being a dash i.e. it starts a word and is followed by a letter
+func (c *converter) atFlag() bool {
+	if c.pos > 0 && !unicode.IsSpace(c.runes[c.pos-1]) && !strings.ContainsRune("([`'\"", c.runes[c.pos-1]) {
+		return false
+	}
+
+	return isLetter(c.peek(2))
+}
+
+// swallowSpace drops the space that follows a control word e.g. "\ss e"
+func (c *converter) swallowSpace() {
+	if c.peek(0) == ' ' {
+		c.pos++
+	}
+}
+
+// accent applies a LaTeX accent to the first character of base
+func accent(cmd, base string) string {
+	runes := []rune(base)
+	if len(runes) == 0 {
+		return combining[cmd]
+	}
+	if s, ok := accents[cmd][string(runes[0])]; ok {
+		return s + string(runes[1:])
+	}
+
+	return string(runes[0]) + combining[cmd] + string(runes[1:])
+}
+
+// math converts a $...$, $$...$$, \(...\) or \[...\] span starting at the current position
+// false is returned if the span isn't closed
+func (c *converter) math() (string, bool) {
+	open, close := "$", "$"
+	switch {
+	case c.peek(0) == '$' && c.peek(1) == '$':
+		open, close = "$$", "$$"
+	case c.peek(0) == '\\' && c.peek(1) == '(':
+		open, close = `\(`, `\)`
+	case c.peek(0) == '\\' && c.peek(1) == '[':
+		open, close = `\[`, `\]`
+	}
+
+	rest := string(c.runes[c.pos+len(open):])
+	end := -1
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '\\' && strings.HasPrefix(close, "$") {
+			// skip escaped characters e.g. \$
+			i++
+			continue
+		}
+		if strings.HasPrefix(rest[i:], close) {
+			end = i
+			break
+		}
+	}
+	if end <= 0 {
+		return "", false
+	}
+
+	tex := rest[:end]
+	c.pos += len(open) + len([]rune(tex)) + len(close)
+	switch c.mode {
+	case TeX:
+		return open + tex + close, true
+	case MathML:
+		return MathToMathML(tex), true
+	default:
+		return MathToUnicode(tex), true
+	}
+}
+
+func isLetter(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
@@ -0,0 +1,47 @@
+package latex_test
+
+import (
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/latex"
+)
+
+func TestToUnicode(t *testing.T) {
+	tests := []struct {
+		input    string
+		mode     latex.MathMode
+		expected string
+	}{
+		{`Schr\"{o}dinger and M\"uller`, latex.Unicode, "Schrödinger and Müller"},
+		{`Erd\H{o}s, Ma\~nana, \v{C}ech, G\"{\i}del, Fran\c{c}ois`, latex.Unicode, "Erdős, Mañana, Čech, Gïdel, François"},
+		{`{\em Emphasis} and \emph{more} and \textbf{bold}`, latex.Unicode, "Emphasis and more and bold"},
+		{`Stra\ss e, \AE sop, 10\% of \$5`, latex.Unicode, "Straße, Æsop, 10% of $5"},
+		{"pages 1--10 ``quoted''", latex.Unicode, "pages 1–10 “quoted”"},
+		{`The $\alpha$-decay of $x^2 + y_1$ in $\mathbb{R}^n$`, latex.Unicode, "The α-decay of x² + y₁ in ℝⁿ"},
+		{`Bounds $O(n^{3/2})$ and $\frac{1}{2}$ with $\sqrt{n \log n}$`, latex.Unicode, "Bounds O(n^(3/2)) and 1/2 with √(n log n)"},
+		{`$a \leq b \neq c$`, latex.Unicode, "a ≤ b ≠ c"},
+		{`The $\alpha$-decay`, latex.TeX, `The $\alpha$-decay`},
+		{`with $x^2$`, latex.MathML, `with <math xmlns="http://www.w3.org/1998/Math/MathML"><msup><mi>x</mi><mn>2</mn></msup></math>`},
+		{`costs $5`, latex.Unicode, "costs $5"},
+		{"a line\n  and an \\unknown{command}", latex.Unicode, "a line\n  and an \\unknowncommand"},
+		{`Code at \url{https://example.org/~user/a\_b--c} and https://example.org/~other--x, see~\cite`, latex.Unicode, "Code at https://example.org/~user/a_b--c and https://example.org/~other--x, see \\cite"},
+		{"Run \\texttt{papercut --format ``csv''} or tool --verbose -- it works", latex.Unicode, "Run papercut --format ``csv'' or tool --verbose – it works"},
+		{`See {www.example.org/~me} or \path{/home/~me}`, latex.Unicode, "See www.example.org/~me or /home/~me"},
+	}
+
+	for _, test := range tests {
+		got := latex.ToUnicode(test.input, test.mode)
+		if got != test.expected {
+			t.Errorf("ToUnicode(%q, %s) = %q; want %q", test.input, test.mode, got, test.expected)
+		}
+	}
+}
+
+func TestParseMathMode(t *testing.T) {
+	if m, err := latex.ParseMathMode("MathML"); err != nil || m != latex.MathML {
+		t.Errorf("ParseMathMode(MathML) = %v, %v", m, err)
+	}
+	if _, err := latex.ParseMathMode("png"); err == nil {
+		t.Error("Expected an error for an unknown math mode")
+	}
+}
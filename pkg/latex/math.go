@@ -0,0 +1,329 @@
+package latex
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// spacedOperators are surrounded by spaces when converting math to Unicode
+var spacedOperators = map[string]bool{
+	"=": true, "<": true, ">": true, "+": true, "−": true,
+	"±": true, "∓": true, "×": true, "÷": true,
+	"≤": true, "≥": true, "≠": true, "≈": true, "≃": true, "∼": true, "≅": true, "≡": true, "∝": true,
+	"≪": true, "≫": true, "≲": true, "≳": true,
+	"→": true, "←": true, "↔": true, "⇒": true, "⇐": true, "⇔": true, "↦": true, "⟶": true, "⟹": true, "⟺": true,
+	"∈": true, "∉": true, "∋": true, "⊂": true, "⊆": true, "⊃": true, "⊇": true, "∪": true, "∩": true, "∖": true,
+	"∧": true, "∨": true,
+}
+
+type mathKind int
+
+const (
+	mathIdent mathKind = iota
+	mathNumber
+	mathOp
+	mathText
+	mathSpace
+	mathGroup
+	mathSup
+	mathSub
+	mathFrac
+	mathSqrt
+)
+
+// mathNode is a node in a (very) small subset of TeX math.
+// Scripts and fractions have two children, groups any number
+type mathNode struct {
+	kind     mathKind
+	value    string
+	children []*mathNode
+}
+
+type mathParser struct {
+	runes []rune
+	pos   int
+}
+
+// MathToUnicode converts the contents of a TeX math span into Unicode text
+func MathToUnicode(tex string) string {
+	p := mathParser{runes: []rune(tex)}
+
+	return joinUnicode(p.list())
+}
+
+// joinUnicode renders a list of nodes, spacing out relations, binary operators and function names
+func joinUnicode(nodes []*mathNode) string {
+	var b strings.Builder
+	for i, n := range nodes {
+		s := n.unicode()
+		switch {
+		case n.kind == mathIdent && mathFunctions[n.value]:
+			s = " " + s + " "
+		case n.kind == mathOp && spacedOperators[n.value]:
+			// leave unary operators e.g. -x alone
+			if i > 0 && nodes[i-1].kind != mathOp {
+				s = " " + s + " "
+			}
+		}
+		b.WriteString(s)
+	}
+
+	return strings.TrimSpace(whitespace.ReplaceAllString(b.String(), " "))
+}
+
+// MathToMathML converts the contents of a TeX math span into an inline MathML element
+func MathToMathML(tex string) string {
+	p := mathParser{runes: []rune(tex)}
+	nodes := p.list()
+
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML">`)
+	for _, n := range nodes {
+		b.WriteString(n.mathML())
+	}
+	b.WriteString("</math>")
+
+	return b.String()
+}
+
+// list parses nodes until the end of input or a closing brace
+func (p *mathParser) list() []*mathNode {
+	nodes := []*mathNode{}
+	for p.pos < len(p.runes) {
+		r := p.runes[p.pos]
+		switch {
+		case r == '}':
+			p.pos++
+			return nodes
+		case r == '^' || r == '_':
+			p.pos++
+			kind := mathSup
+			if r == '_' {
+				kind = mathSub
+			}
+			base := &mathNode{kind: mathGroup}
+			if len(nodes) > 0 {
+				base = nodes[len(nodes)-1]
+				nodes = nodes[:len(nodes)-1]
+			}
+			nodes = append(nodes, &mathNode{kind: kind, children: []*mathNode{base, p.script()}})
+		default:
+			nodes = append(nodes, p.atom())
+		}
+	}
+
+	return nodes
+}
+
+// atom parses a single node: a {group}, a command, a number or a character
+func (p *mathParser) atom() *mathNode {
+	for p.pos < len(p.runes) && unicode.IsSpace(p.runes[p.pos]) {
+		p.pos++
+	}
+	if p.pos >= len(p.runes) {
+		return &mathNode{kind: mathGroup}
+	}
+
+	r := p.runes[p.pos]
+	switch {
+	case r == '{':
+		p.pos++
+		return &mathNode{kind: mathGroup, children: p.list()}
+	case r == '\\':
+		return p.command()
+	case unicode.IsDigit(r):
+		start := p.pos
+		for p.pos < len(p.runes) && (unicode.IsDigit(p.runes[p.pos]) || p.runes[p.pos] == '.') {
+			p.pos++
+		}
+		return &mathNode{kind: mathNumber, value: string(p.runes[start:p.pos])}
+	case unicode.IsLetter(r):
+		p.pos++
+		return &mathNode{kind: mathIdent, value: string(r)}
+	case r == '-':
+		p.pos++
+		return &mathNode{kind: mathOp, value: "−"}
+	default:
+		p.pos++
+		return &mathNode{kind: mathOp, value: string(r)}
+	}
+}
+
+// script parses the argument of ^ or _, which like TeX is a single digit for x^23
+func (p *mathParser) script() *mathNode {
+	if p.pos < len(p.runes) && unicode.IsDigit(p.runes[p.pos]) {
+		p.pos++
+		return &mathNode{kind: mathNumber, value: string(p.runes[p.pos-1])}
+	}
+
+	return p.atom()
+}
+
+// command parses a math command starting at the backslash
+func (p *mathParser) command() *mathNode {
+	p.pos++
+	start := p.pos
+	if p.pos < len(p.runes) && !isLetter(p.runes[p.pos]) {
+		p.pos++
+	} else {
+		for p.pos < len(p.runes) && isLetter(p.runes[p.pos]) {
+			p.pos++
+		}
+	}
+	name := string(p.runes[start:p.pos])
+
+	if s, ok := mathLetters[name]; ok {
+		return &mathNode{kind: mathIdent, value: s}
+	}
+	if s, ok := mathOperators[name]; ok && name != "sqrt" {
+		return &mathNode{kind: mathOp, value: s}
+	}
+	if mathFunctions[name] {
+		return &mathNode{kind: mathIdent, value: name}
+	}
+	if mathSpaces[name] {
+		return &mathNode{kind: mathSpace, value: " "}
+	}
+	if mathSizes[name] {
+		// \left. and \right. are invisible delimiters
+		if p.pos < len(p.runes) && p.runes[p.pos] == '.' {
+			p.pos++
+		}
+		return p.atom()
+	}
+
+	switch name {
+	case "frac", "dfrac", "tfrac":
+		return &mathNode{kind: mathFrac, children: []*mathNode{p.atom(), p.atom()}}
+	case "sqrt":
+		// the optional root e.g. \sqrt[3]{x} is dropped
+		if p.pos < len(p.runes) && p.runes[p.pos] == '[' {
+			for p.pos < len(p.runes) && p.runes[p.pos] != ']' {
+				p.pos++
+			}
+			p.pos++
+		}
+		return &mathNode{kind: mathSqrt, children: []*mathNode{p.atom()}}
+	case "text", "textrm", "textit", "textbf", "mbox", "operatorname":
+		return &mathNode{kind: mathText, value: rawText(p.atom())}
+	case "mathbb":
+		n := p.atom()
+		t := rawText(n)
+		var b strings.Builder
+		for _, r := range t {
+			if s, ok := doubleStruck[r]; ok {
+				b.WriteString(s)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		return &mathNode{kind: mathIdent, value: b.String()}
+	case "mathrm", "mathit", "mathbf", "mathsf", "mathtt", "mathcal", "mathfrak", "mathscr", "boldsymbol", "bm",
+		"hat", "bar", "tilde", "vec", "dot", "ddot", "overline", "widehat", "widetilde":
+		return p.atom()
+	}
+
+	return &mathNode{kind: mathIdent, value: `\` + name}
+}
+
+// rawText returns the characters of a node e.g. the argument of \text{}
+func rawText(n *mathNode) string {
+	if n.kind != mathGroup {
+		return n.value
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(rawText(c))
+	}
+
+	return b.String()
+}
+
+func (n *mathNode) unicode() string {
+	switch n.kind {
+	case mathGroup:
+		return joinUnicode(n.children)
+	case mathSup, mathSub:
+		base := n.children[0].unicode()
+		script := n.children[1].unicode()
+		table := superscripts
+		mark := "^"
+		if n.kind == mathSub {
+			table = subscripts
+			mark = "_"
+		}
+		if s, ok := mapRunes(script, table); ok {
+			return base + s
+		}
+		return base + mark + parenthesize(script)
+	case mathFrac:
+		return parenthesize(n.children[0].unicode()) + "/" + parenthesize(n.children[1].unicode())
+	case mathSqrt:
+		return "√" + parenthesize(n.children[0].unicode())
+	}
+
+	return n.value
+}
+
+func (n *mathNode) mathML() string {
+	switch n.kind {
+	case mathIdent:
+		return "<mi>" + html.EscapeString(n.value) + "</mi>"
+	case mathNumber:
+		return "<mn>" + html.EscapeString(n.value) + "</mn>"
+	case mathOp:
+		return "<mo>" + html.EscapeString(n.value) + "</mo>"
+	case mathText:
+		return "<mtext>" + html.EscapeString(n.value) + "</mtext>"
+	case mathSpace:
+		return ""
+	case mathGroup:
+		if len(n.children) == 1 {
+			return n.children[0].mathML()
+		}
+		var b strings.Builder
+		b.WriteString("<mrow>")
+		for _, c := range n.children {
+			b.WriteString(c.mathML())
+		}
+		b.WriteString("</mrow>")
+		return b.String()
+	case mathSup:
+		return "<msup>" + n.children[0].mathML() + n.children[1].mathML() + "</msup>"
+	case mathSub:
+		return "<msub>" + n.children[0].mathML() + n.children[1].mathML() + "</msub>"
+	case mathFrac:
+		return "<mfrac>" + n.children[0].mathML() + n.children[1].mathML() + "</mfrac>"
+	case mathSqrt:
+		return "<msqrt>" + n.children[0].mathML() + "</msqrt>"
+	}
+
+	return ""
+}
+
+// mapRunes converts every rune in s using table
+// false is returned if any rune has no equivalent
+func mapRunes(s string, table map[rune]rune) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		m, ok := table[r]
+		if !ok {
+			return "", false
+		}
+		b.WriteRune(m)
+	}
+
+	return b.String(), s != ""
+}
+
+func parenthesize(s string) string {
+	if len([]rune(s)) <= 1 || strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		return s
+	}
+
+	return "(" + s + ")"
+}
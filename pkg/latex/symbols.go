@@ -0,0 +1,332 @@
+package latex
+
+// textSymbols are commands that produce a character in text mode
+var textSymbols = map[string]string{
+	"ss":             "ß",
+	"SS":             "SS",
+	"o":              "ø",
+	"O":              "Ø",
+	"ae":             "æ",
+	"AE":             "Æ",
+	"oe":             "œ",
+	"OE":             "Œ",
+	"aa":             "å",
+	"AA":             "Å",
+	"l":              "ł",
+	"L":              "Ł",
+	"i":              "ı",
+	"j":              "ȷ",
+	"dh":             "ð",
+	"DH":             "Ð",
+	"th":             "þ",
+	"TH":             "Þ",
+	"ldots":          "…",
+	"dots":           "…",
+	"textellipsis":   "…",
+	"textendash":     "–",
+	"textemdash":     "—",
+	"textquoteleft":  "‘",
+	"textquoteright": "’",
+	"textquotedbl":   "\"",
+	"copyright":      "©",
+	"textcopyright":  "©",
+	"textregistered": "®",
+	"texttrademark":  "™",
+	"textdegree":     "°",
+	"S":              "§",
+	"P":              "¶",
+	"dag":            "†",
+	"ddag":           "‡",
+	"pounds":         "£",
+	"euro":           "€",
+	"LaTeX":          "LaTeX",
+	"TeX":            "TeX",
+	"BibTeX":         "BibTeX",
+}
+
+// fontSwitches change the font of the rest of the group e.g. {\em ...} and are dropped
+var fontSwitches = map[string]bool{
+	"em":           true,
+	"it":           true,
+	"bf":           true,
+	"sl":           true,
+	"sc":           true,
+	"rm":           true,
+	"tt":           true,
+	"sf":           true,
+	"normalfont":   true,
+	"itshape":      true,
+	"bfseries":     true,
+	"mdseries":     true,
+	"upshape":      true,
+	"scshape":      true,
+	"slshape":      true,
+	"rmfamily":     true,
+	"sffamily":     true,
+	"ttfamily":     true,
+	"small":        true,
+	"large":        true,
+	"Large":        true,
+	"footnotesize": true,
+	"noindent":     true,
+	"relax":        true,
+}
+
+// textArguments are commands whose argument is kept as is e.g. \emph{...}
+var textArguments = map[string]bool{
+	"emph":       true,
+	"textit":     true,
+	"textbf":     true,
+	"textrm":     true,
+	"textsc":     true,
+	"textsf":     true,
+	"textsl":     true,
+	"textup":     true,
+	"textnormal": true,
+	"underline":  true,
+	"mbox":       true,
+	"hbox":       true,
+	"text":       true,
+}
+
+// mathLetters are identifiers in math mode
+var mathLetters = map[string]string{
+	"alpha":      "α",
+	"beta":       "β",
+	"gamma":      "γ",
+	"delta":      "δ",
+	"epsilon":    "ϵ",
+	"varepsilon": "ε",
+	"zeta":       "ζ",
+	"eta":        "η",
+	"theta":      "θ",
+	"vartheta":   "ϑ",
+	"iota":       "ι",
+	"kappa":      "κ",
+	"lambda":     "λ",
+	"mu":         "μ",
+	"nu":         "ν",
+	"xi":         "ξ",
+	"pi":         "π",
+	"varpi":      "ϖ",
+	"rho":        "ρ",
+	"varrho":     "ϱ",
+	"sigma":      "σ",
+	"varsigma":   "ς",
+	"tau":        "τ",
+	"upsilon":    "υ",
+	"phi":        "ϕ",
+	"varphi":     "φ",
+	"chi":        "χ",
+	"psi":        "ψ",
+	"omega":      "ω",
+	"Gamma":      "Γ",
+	"Delta":      "Δ",
+	"Theta":      "Θ",
+	"Lambda":     "Λ",
+	"Xi":         "Ξ",
+	"Pi":         "Π",
+	"Sigma":      "Σ",
+	"Upsilon":    "Υ",
+	"Phi":        "Φ",
+	"Psi":        "Ψ",
+	"Omega":      "Ω",
+	"ell":        "ℓ",
+	"hbar":       "ℏ",
+	"infty":      "∞",
+	"partial":    "∂",
+	"nabla":      "∇",
+	"emptyset":   "∅",
+	"varnothing": "∅",
+	"aleph":      "ℵ",
+	"Re":         "ℜ",
+	"Im":         "ℑ",
+	"wp":         "℘",
+	"prime":      "′",
+	"odot":       "⊙",
+	"star":       "⋆",
+}
+
+// mathOperators are operators, relations and delimiters in math mode
+var mathOperators = map[string]string{
+	"pm":             "±",
+	"mp":             "∓",
+	"times":          "×",
+	"div":            "÷",
+	"cdot":           "·",
+	"cdots":          "⋯",
+	"ldots":          "…",
+	"dots":           "…",
+	"ast":            "∗",
+	"circ":           "∘",
+	"bullet":         "•",
+	"oplus":          "⊕",
+	"otimes":         "⊗",
+	"leq":            "≤",
+	"le":             "≤",
+	"geq":            "≥",
+	"ge":             "≥",
+	"lesssim":        "≲",
+	"gtrsim":         "≳",
+	"ll":             "≪",
+	"gg":             "≫",
+	"neq":            "≠",
+	"ne":             "≠",
+	"approx":         "≈",
+	"simeq":          "≃",
+	"sim":            "∼",
+	"cong":           "≅",
+	"equiv":          "≡",
+	"propto":         "∝",
+	"to":             "→",
+	"rightarrow":     "→",
+	"leftarrow":      "←",
+	"gets":           "←",
+	"leftrightarrow": "↔",
+	"Rightarrow":     "⇒",
+	"Leftarrow":      "⇐",
+	"Leftrightarrow": "⇔",
+	"mapsto":         "↦",
+	"longrightarrow": "⟶",
+	"implies":        "⟹",
+	"iff":            "⟺",
+	"in":             "∈",
+	"notin":          "∉",
+	"ni":             "∋",
+	"subset":         "⊂",
+	"subseteq":       "⊆",
+	"supset":         "⊃",
+	"supseteq":       "⊇",
+	"cup":            "∪",
+	"cap":            "∩",
+	"setminus":       "∖",
+	"wedge":          "∧",
+	"land":           "∧",
+	"vee":            "∨",
+	"lor":            "∨",
+	"neg":            "¬",
+	"lnot":           "¬",
+	"forall":         "∀",
+	"exists":         "∃",
+	"sum":            "∑",
+	"prod":           "∏",
+	"coprod":         "∐",
+	"int":            "∫",
+	"oint":           "∮",
+	"sqrt":           "√",
+	"perp":           "⊥",
+	"parallel":       "∥",
+	"mid":            "∣",
+	"langle":         "⟨",
+	"rangle":         "⟩",
+	"lceil":          "⌈",
+	"rceil":          "⌉",
+	"lfloor":         "⌊",
+	"rfloor":         "⌋",
+	"vert":           "|",
+	"Vert":           "‖",
+	"|":              "‖",
+	"{":              "{",
+	"}":              "}",
+	"%":              "%",
+	"&":              "&",
+	"#":              "#",
+	"_":              "_",
+	"$":              "$",
+	"dagger":         "†",
+	"degree":         "°",
+}
+
+// mathFunctions are upright function names e.g. \sin
+var mathFunctions = map[string]bool{
+	"sin":    true,
+	"cos":    true,
+	"tan":    true,
+	"cot":    true,
+	"sec":    true,
+	"csc":    true,
+	"sinh":   true,
+	"cosh":   true,
+	"tanh":   true,
+	"arcsin": true,
+	"arccos": true,
+	"arctan": true,
+	"log":    true,
+	"ln":     true,
+	"lg":     true,
+	"exp":    true,
+	"lim":    true,
+	"liminf": true,
+	"limsup": true,
+	"max":    true,
+	"min":    true,
+	"sup":    true,
+	"inf":    true,
+	"det":    true,
+	"dim":    true,
+	"ker":    true,
+	"deg":    true,
+	"gcd":    true,
+	"arg":    true,
+	"Pr":     true,
+	"mod":    true,
+	"bmod":   true,
+}
+
+// mathSpaces are spacing commands in math mode
+var mathSpaces = map[string]bool{
+	",":     true,
+	";":     true,
+	":":     true,
+	"!":     true,
+	" ":     true,
+	"quad":  true,
+	"qquad": true,
+}
+
+// sizing commands in math mode are dropped, the delimiter that follows them is kept
+var mathSizes = map[string]bool{
+	"left":         true,
+	"right":        true,
+	"big":          true,
+	"Big":          true,
+	"bigg":         true,
+	"Bigg":         true,
+	"bigl":         true,
+	"bigr":         true,
+	"Bigl":         true,
+	"Bigr":         true,
+	"displaystyle": true,
+	"textstyle":    true,
+	"limits":       true,
+	"nolimits":     true,
+}
+
+var doubleStruck = map[rune]string{
+	'C': "ℂ",
+	'H': "ℍ",
+	'N': "ℕ",
+	'P': "ℙ",
+	'Q': "ℚ",
+	'R': "ℝ",
+	'Z': "ℤ",
+	'E': "𝔼",
+	'F': "𝔽",
+	'1': "𝟙",
+}
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴',
+	'5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+	'+': '⁺', '-': '⁻', '−': '⁻', '=': '⁼', '(': '⁽', ')': '⁾',
+	'n': 'ⁿ', 'i': 'ⁱ', '′': '′',
+}
+
+var subscripts = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄',
+	'5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+	'+': '₊', '-': '₋', '−': '₋', '=': '₌', '(': '₍', ')': '₎',
+	'a': 'ₐ', 'e': 'ₑ', 'o': 'ₒ', 'x': 'ₓ', 'h': 'ₕ', 'k': 'ₖ',
+	'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ', 'p': 'ₚ', 's': 'ₛ', 't': 'ₜ',
+	'i': 'ᵢ', 'j': 'ⱼ', 'r': 'ᵣ', 'u': 'ᵤ', 'v': 'ᵥ',
+}
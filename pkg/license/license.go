@@ -0,0 +1,180 @@
+package license
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doab"
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+	"github.com/lehigh-university-libraries/papercut/pkg/unpaywall"
+)
+
+// Sources of a rights statement, recorded in the rights_source column
+const (
+	Sherpa    = "sherpa"
+	DOAB      = "doab"
+	Crossref  = "crossref"
+	Unpaywall = "unpaywall"
+	Arxiv     = "arxiv"
+	PMC       = "pmc"
+	OpenAlex  = "openalex"
+	Biorxiv   = "biorxiv"
+)
+
+// Rights is a rights statement and the source that supplied it
+type Rights struct {
+	URI    string `json:"uri"`
+	Source string `json:"source"`
+}
+
+// Step is a source to ask for a rights statement
+// Find returns "" when the source doesn't have one
+type Step struct {
+	Source string
+	Find   func() (string, error)
+}
+
+// Resolve asks each step in order for a rights statement, returning the first one found
+func Resolve(steps ...Step) Rights {
+	for _, s := range steps {
+		uri, err := s.Find()
+		if err != nil {
+			log.Printf("Unable to get rights from %s: %v", s.Source, err)
+			continue
+		}
+		if uri != "" {
+			return Rights{URI: uri, Source: s.Source}
+		}
+	}
+
+	return Rights{}
+}
+
+// Options configure the sources ForArticle asks
+type Options struct {
+	// UnpaywallEmail is sent with Unpaywall requests, which are skipped without it
+	UnpaywallEmail string
+	// ArxivRecord fetches the OAI record of an arXiv paper, which is skipped if nil
+	ArxivRecord func(id string) map[string]string
+	// Now is when Crossref licenses need to have started by, defaulting to the current time
+	Now time.Time
+	// SkipSherpa leaves Sherpa Romeo out e.g. when there's no API key for it
+	SkipSherpa bool
+}
+
+// ForArticle finds the rights statement for an article by asking, in order,
+// Sherpa Romeo for its ISSNs, DOAB for its ISBNs, Crossref's license metadata,
+// Unpaywall, then arXiv if it's an arXiv paper or has an arXiv preprint
+func ForArticle(a doi.Article, o Options) Rights {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	steps := []Step{}
+	if !o.SkipSherpa {
+		for _, issn := range a.ISSN {
+			steps = append(steps, Step{Sherpa, func() (string, error) {
+				return romeo.FindIssnLicense(issn), nil
+			}})
+		}
+	}
+	for _, isbn := range a.ISBN {
+		steps = append(steps, Step{DOAB, func() (string, error) {
+			return doab.GetIsbnLicense(isbn)
+		}})
+	}
+	steps = append(steps, Step{Crossref, func() (string, error) {
+		return a.CurrentLicense(o.Now), nil
+	}})
+	if o.UnpaywallEmail != "" {
+		steps = append(steps, Step{Unpaywall, func() (string, error) {
+			r, err := unpaywall.GetDoi(a.DOI, o.UnpaywallEmail)
+			if err != nil {
+				return "", err
+			}
+			// the best location's license may not be a Creative Commons one when another location's is
+			for _, l := range r.Licenses() {
+				if uri := CreativeCommonsURI(l); uri != "" {
+					return uri, nil
+				}
+			}
+			return "", nil
+		}})
+	}
+	if id := ArxivID(a); id != "" && o.ArxivRecord != nil {
+		steps = append(steps, Step{Arxiv, func() (string, error) {
+			oai := o.ArxivRecord(id)
+			if oai == nil {
+				return "", fmt.Errorf("could not get the OAI record for %s", id)
+			}
+			return oai["field_rights"], nil
+		}})
+	}
+
+	return Resolve(steps...)
+}
+
+var arxivDoi = regexp.MustCompile(`(?i)^10\.48550/arxiv\.(.+)$`)
+
+// ArxivID returns the arXiv ID of an article minted a DOI by arXiv
+// or of the arXiv preprint Crossref relates it to
+func ArxivID(a doi.Article) string {
+	if m := arxivDoi.FindStringSubmatch(a.DOI); m != nil {
+		return m[1]
+	}
+	for _, r := range a.Relation["has-preprint"] {
+		switch strings.ToLower(r.IDType) {
+		case "arxiv":
+			// only the prefix's case varies, old-style IDs like math.GT/0309136 need theirs
+			id := strings.TrimSpace(r.ID)
+			if len(id) > 6 && strings.EqualFold(id[:6], "arxiv:") {
+				id = id[6:]
+			}
+			return id
+		case "doi":
+			if m := arxivDoi.FindStringSubmatch(r.ID); m != nil {
+				return m[1]
+			}
+		}
+	}
+
+	return ""
+}
+
+// CreativeCommonsURI turns a license name like cc-by-nc, CC BY 4.0 or cc0 into its URI
+// returning "" for anything that isn't a Creative Commons license
+func CreativeCommonsURI(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == ' '
+	})
+	if len(fields) == 0 || fields[0] != "cc" && fields[0] != "cc0" {
+		return ""
+	}
+	if fields[0] == "cc0" || (len(fields) > 1 && fields[1] == "0") {
+		return "https://creativecommons.org/publicdomain/zero/1.0/"
+	}
+
+	version := "4.0"
+	terms := []string{}
+	for _, f := range fields[1:] {
+		switch f {
+		case "by", "sa", "nc", "nd":
+			terms = append(terms, f)
+		default:
+			if strings.ContainsAny(f[:1], "0123456789") {
+				version = f
+			} else {
+				return ""
+			}
+		}
+	}
+	if len(terms) == 0 || terms[0] != "by" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://creativecommons.org/licenses/%s/%s/", strings.Join(terms, "-"), version)
+}
@@ -0,0 +1,108 @@
+package license_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/license"
+)
+
+func TestResolve(t *testing.T) {
+	asked := []string{}
+	step := func(source, uri string, err error) license.Step {
+		return license.Step{Source: source, Find: func() (string, error) {
+			asked = append(asked, source)
+			return uri, err
+		}}
+	}
+
+	r := license.Resolve(
+		step(license.Sherpa, "", nil),
+		step(license.DOAB, "https://example.com/broken", errors.New("unavailable")),
+		step(license.Crossref, "https://creativecommons.org/licenses/by/4.0/", nil),
+		step(license.Unpaywall, "https://creativecommons.org/licenses/by-nc/4.0/", nil),
+	)
+	expected := license.Rights{URI: "https://creativecommons.org/licenses/by/4.0/", Source: license.Crossref}
+	if r != expected {
+		t.Errorf("expected %+v, got %+v", expected, r)
+	}
+	if len(asked) != 3 {
+		t.Errorf("expected the steps after the first rights statement to be skipped, asked %v", asked)
+	}
+
+	if r := license.Resolve(step(license.Sherpa, "", nil)); r != (license.Rights{}) {
+		t.Errorf("expected no rights, got %+v", r)
+	}
+}
+
+func TestForArticle(t *testing.T) {
+	var a doi.Article
+	err := json.Unmarshal([]byte(`{
+		"DOI": "10.1000/proceedings.1",
+		"type": "proceedings-article",
+		"license": [{"start": {"date-parts": [[2030, 1, 1]]}, "content-version": "vor", "URL": "https://creativecommons.org/licenses/by/4.0/"}],
+		"relation": {"has-preprint": [{"id-type": "doi", "id": "10.48550/arXiv.2401.00001", "asserted-by": "subject"}]}
+	}`), &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requested := ""
+	o := license.Options{
+		ArxivRecord: func(id string) map[string]string {
+			requested = id
+			return map[string]string{"field_rights": "http://arxiv.org/licenses/nonexclusive-distrib/1.0/"}
+		},
+		Now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	r := license.ForArticle(a, o)
+	if r.Source != license.Arxiv || r.URI != "http://arxiv.org/licenses/nonexclusive-distrib/1.0/" || requested != "2401.00001" {
+		t.Errorf("expected the arXiv preprint's license while Crossref's is embargoed, got %+v for %q", r, requested)
+	}
+
+	o.Now = time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	r = license.ForArticle(a, o)
+	if r.Source != license.Crossref || r.URI != "https://creativecommons.org/licenses/by/4.0/" {
+		t.Errorf("expected Crossref's license once it started, got %+v", r)
+	}
+}
+
+func TestArxivID(t *testing.T) {
+	tests := map[string]doi.Article{
+		"2401.00001": {DOI: "10.48550/arXiv.2401.00001"},
+		"2402.00002": {DOI: "10.1000/abc", Relation: map[string][]doi.Relation{
+			"has-preprint": {{IDType: "arxiv", ID: "arXiv:2402.00002"}},
+		}},
+		"math.GT/0309136": {DOI: "10.1000/def", Relation: map[string][]doi.Relation{
+			"has-preprint": {{IDType: "arxiv", ID: "ARXIV:math.GT/0309136"}},
+		}},
+		"": {DOI: "10.1000/abc"},
+	}
+	for expected, a := range tests {
+		if id := license.ArxivID(a); id != expected {
+			t.Errorf("%s: expected %q, got %q", a.DOI, expected, id)
+		}
+	}
+}
+
+func TestCreativeCommonsURI(t *testing.T) {
+	tests := map[string]string{
+		"cc-by":                 "https://creativecommons.org/licenses/by/4.0/",
+		"cc-by-nc-nd":           "https://creativecommons.org/licenses/by-nc-nd/4.0/",
+		"CC BY-SA 3.0":          "https://creativecommons.org/licenses/by-sa/3.0/",
+		"cc_by_nc":              "https://creativecommons.org/licenses/by-nc/4.0/",
+		"cc0":                   "https://creativecommons.org/publicdomain/zero/1.0/",
+		"publisher-specific-oa": "",
+		"implied-oa":            "",
+		"cc-nc":                 "",
+		"":                      "",
+	}
+	for name, expected := range tests {
+		if uri := license.CreativeCommonsURI(name); uri != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, uri)
+		}
+	}
+}
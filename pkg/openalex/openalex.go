@@ -0,0 +1,367 @@
+package openalex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// URL is the OpenAlex API
+var URL = "https://api.openalex.org"
+
+// MaxPerPage is the most works OpenAlex returns in a page
+const MaxPerPage = 200
+
+// Work is OpenAlex's record of a scholarly work
+type Work struct {
+	// Raw is the work's JSON as OpenAlex returned it
+	Raw             json.RawMessage  `json:"-"`
+	ID              string           `json:"id"`
+	DOI             string           `json:"doi"`
+	Title           string           `json:"title"`
+	PublicationYear int              `json:"publication_year"`
+	PublicationDate string           `json:"publication_date"`
+	Language        string           `json:"language"`
+	Type            string           `json:"type"`
+	CitedByCount    int              `json:"cited_by_count"`
+	OpenAccess      OpenAccess       `json:"open_access"`
+	Authorships     []Authorship     `json:"authorships"`
+	PrimaryLocation *Location        `json:"primary_location"`
+	BestOALocation  *Location        `json:"best_oa_location"`
+	Topics          []Topic          `json:"topics"`
+	Concepts        []Concept        `json:"concepts"`
+	Biblio          Biblio           `json:"biblio"`
+	IDs             IDs              `json:"ids"`
+	AbstractIndex   map[string][]int `json:"abstract_inverted_index"`
+}
+
+type OpenAccess struct {
+	IsOA bool `json:"is_oa"`
+	// OAStatus is one of diamond, gold, green, hybrid, bronze or closed
+	OAStatus string `json:"oa_status"`
+	OAURL    string `json:"oa_url"`
+}
+
+type Authorship struct {
+	Position              string        `json:"author_position"`
+	Author                Author        `json:"author"`
+	Institutions          []Institution `json:"institutions"`
+	RawAffiliationStrings []string      `json:"raw_affiliation_strings"`
+}
+
+type Author struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	ORCID       string `json:"orcid"`
+}
+
+// Name returns the author's display name as Family, Given
+func (a Author) Name() string {
+	return utils.InvertName(a.DisplayName)
+}
+
+type Institution struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	ROR         string `json:"ror"`
+	CountryCode string `json:"country_code"`
+	Type        string `json:"type"`
+}
+
+// Location is somewhere the work is hosted e.g. a journal or a repository
+type Location struct {
+	IsOA           bool   `json:"is_oa"`
+	LandingPageURL string `json:"landing_page_url"`
+	PDFURL         string `json:"pdf_url"`
+	Source         Source `json:"source"`
+	// License is a short name like cc-by or publisher-specific-oa
+	License string `json:"license"`
+	Version string `json:"version"`
+}
+
+type Source struct {
+	ID                   string   `json:"id"`
+	DisplayName          string   `json:"display_name"`
+	ISSNL                string   `json:"issn_l"`
+	ISSN                 []string `json:"issn"`
+	HostOrganizationName string   `json:"host_organization_name"`
+	Type                 string   `json:"type"`
+}
+
+// Topic is one of the topics OpenAlex assigned the work, with its place in the topic hierarchy
+type Topic struct {
+	ID          string  `json:"id"`
+	DisplayName string  `json:"display_name"`
+	Score       float64 `json:"score"`
+	Subfield    Term    `json:"subfield"`
+	Field       Term    `json:"field"`
+	Domain      Term    `json:"domain"`
+}
+
+type Term struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+}
+
+// Concept is one of the older, deprecated OpenAlex concepts assigned to the work
+type Concept struct {
+	ID          string  `json:"id"`
+	DisplayName string  `json:"display_name"`
+	Level       int     `json:"level"`
+	Score       float64 `json:"score"`
+}
+
+// IDs are the work's identifiers as URLs e.g. https://pubmed.ncbi.nlm.nih.gov/12345
+type IDs struct {
+	OpenAlex string `json:"openalex"`
+	DOI      string `json:"doi"`
+	PMID     string `json:"pmid"`
+	PMCID    string `json:"pmcid"`
+}
+
+type Biblio struct {
+	Volume    string `json:"volume"`
+	Issue     string `json:"issue"`
+	FirstPage string `json:"first_page"`
+	LastPage  string `json:"last_page"`
+}
+
+// Page is a page of search results
+type Page struct {
+	Meta struct {
+		Count   int `json:"count"`
+		PerPage int `json:"per_page"`
+		// NextCursor is empty on the last page
+		NextCursor string `json:"next_cursor"`
+	} `json:"meta"`
+	Results []Work `json:"results"`
+}
+
+func (w *Work) UnmarshalJSON(b []byte) error {
+	type work Work
+	var v work
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*w = Work(v)
+	w.Raw = append(json.RawMessage{}, b...)
+
+	return nil
+}
+
+// Filter is an OpenAlex works filter
+// values of the same kind are ORed together and kinds are ANDed
+type Filter struct {
+	// Institutions are ROR IDs e.g. 012afjb06 or https://ror.org/012afjb06
+	Institutions []string
+	// Authors are OpenAlex author IDs e.g. A5023888391 or ORCID iDs, but not a mix of the two
+	// since OpenAlex filters them separately, which would AND them
+	Authors []string
+	// Concepts and Topics are OpenAlex IDs e.g. C41008148 or T10102
+	Concepts []string
+	Topics   []string
+	// From and To are publication dates formatted as YYYY-MM-DD
+	From string
+	To   string
+}
+
+var orcidPattern = regexp.MustCompile(`^\d{4}-\d{4}-\d{4}-\d{3}[\dX]$`)
+
+// Encode formats the filter for the works API's filter parameter
+// e.g. institutions.ror:https://ror.org/012afjb06,from_publication_date:2024-01-01
+func (f Filter) Encode() (string, error) {
+	filters := []string{}
+	if len(f.Institutions) > 0 {
+		rors := []string{}
+		for _, i := range f.Institutions {
+			rors = append(rors, NormalizeROR(i))
+		}
+		filters = append(filters, "institutions.ror:"+strings.Join(rors, "|"))
+	}
+
+	authorIDs, orcids := []string{}, []string{}
+	for _, a := range f.Authors {
+		a = strings.TrimSpace(a)
+		orcid := strings.TrimPrefix(strings.TrimPrefix(a, "https://orcid.org/"), "http://orcid.org/")
+		if orcidPattern.MatchString(orcid) {
+			orcids = append(orcids, "https://orcid.org/"+orcid)
+		} else {
+			authorIDs = append(authorIDs, ShortID(a))
+		}
+	}
+	if len(authorIDs) > 0 && len(orcids) > 0 {
+		return "", fmt.Errorf("authors must all be OpenAlex author IDs or all be ORCID iDs, since OpenAlex can only AND the two")
+	}
+	if len(authorIDs) > 0 {
+		filters = append(filters, "authorships.author.id:"+strings.Join(authorIDs, "|"))
+	}
+	if len(orcids) > 0 {
+		filters = append(filters, "authorships.author.orcid:"+strings.Join(orcids, "|"))
+	}
+
+	for key, ids := range map[string][]string{"concepts.id": f.Concepts, "topics.id": f.Topics} {
+		if len(ids) == 0 {
+			continue
+		}
+		short := []string{}
+		for _, id := range ids {
+			short = append(short, ShortID(id))
+		}
+		filters = append(filters, key+":"+strings.Join(short, "|"))
+	}
+
+	for key, d := range map[string]string{"from_publication_date": f.From, "to_publication_date": f.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return "", fmt.Errorf("%s must be a date like 2024-01-31, got %q", key, d)
+		}
+		filters = append(filters, key+":"+d)
+	}
+
+	if len(filters) == 0 {
+		return "", fmt.Errorf("an institution, author, concept, topic or date is required to search OpenAlex")
+	}
+	// the maps above are unordered so sort the filters to keep them the same from run to run
+	sort.Strings(filters)
+
+	return strings.Join(filters, ","), nil
+}
+
+// NormalizeROR formats a ROR ID as a URL e.g. https://ror.org/012afjb06
+func NormalizeROR(id string) string {
+	id = strings.TrimSpace(id)
+	id = strings.TrimPrefix(strings.TrimPrefix(id, "https://ror.org/"), "http://ror.org/")
+
+	return "https://ror.org/" + strings.ToLower(id)
+}
+
+// ShortID returns the last part of an OpenAlex ID e.g. W2741809807 for https://openalex.org/W2741809807
+func ShortID(id string) string {
+	id = strings.TrimSpace(id)
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+
+	return id
+}
+
+// Search returns a page of works matching the filter
+// start with the cursor * and pass each page's NextCursor to get the next one
+func Search(filter, cursor string, perPage int, mailto string) (*Page, error) {
+	params := url.Values{}
+	params.Set("filter", filter)
+	params.Set("cursor", cursor)
+	params.Set("per-page", strconv.Itoa(perPage))
+	if mailto != "" {
+		params.Set("mailto", mailto)
+	}
+
+	body, err := utils.Fetch(fmt.Sprintf("%s/works?%s", URL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var p Page
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("could not unmarshal OpenAlex results: %v", err)
+	}
+
+	return &p, nil
+}
+
+// GetDoi returns OpenAlex's record of a DOI, caching it with the DOI's other responses
+// mailto puts the requests in OpenAlex's polite pool
+func GetDoi(d, mailto string) (*Work, error) {
+	dirPath, err := utils.MkTmpDir(filepath.Join("dois", d))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cached file directory: %v", err)
+	}
+
+	u := fmt.Sprintf("%s/works/doi:%s", URL, d)
+	if mailto != "" {
+		u = fmt.Sprintf("%s?%s", u, url.Values{"mailto": {mailto}}.Encode())
+	}
+	result := utils.GetResult(filepath.Join(dirPath, "openalex.json"), u, "application/json")
+	if result == nil {
+		return nil, fmt.Errorf("could not find DOI %s in OpenAlex", d)
+	}
+
+	var w Work
+	if err := json.Unmarshal(result, &w); err != nil {
+		return nil, fmt.Errorf("could not unmarshal OpenAlex JSON for %s: %v", d, err)
+	}
+
+	return &w, nil
+}
+
+// DOIName returns the work's DOI without the https://doi.org/ prefix OpenAlex gives it
+func (w Work) DOIName() string {
+	return strings.TrimPrefix(strings.TrimPrefix(w.DOI, "https://doi.org/"), "http://doi.org/")
+}
+
+// Abstract puts the words of the abstract back in order
+// OpenAlex only publishes abstracts as an index of each word's positions
+func (w Work) Abstract() string {
+	words := []string{}
+	for word, positions := range w.AbstractIndex {
+		for _, p := range positions {
+			if p < 0 {
+				continue
+			}
+			for len(words) <= p {
+				words = append(words, "")
+			}
+			words[p] = word
+		}
+	}
+
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
+
+// License returns the license of the best open access location, or else the primary location
+func (w Work) License() string {
+	for _, l := range []*Location{w.BestOALocation, w.PrimaryLocation} {
+		if l != nil && l.License != "" {
+			return l.License
+		}
+	}
+
+	return ""
+}
+
+// PDFURL returns the URL of an open access PDF of the work, if OpenAlex knows one
+func (w Work) PDFURL() string {
+	for _, l := range []*Location{w.BestOALocation, w.PrimaryLocation} {
+		if l != nil && l.IsOA && l.PDFURL != "" {
+			return l.PDFURL
+		}
+	}
+
+	return ""
+}
+
+// Institutions returns the institutions of the work's authors without duplicates
+func (w Work) Institutions() []Institution {
+	seen := map[string]bool{}
+	institutions := []Institution{}
+	for _, a := range w.Authorships {
+		for _, i := range a.Institutions {
+			if i.ID == "" || seen[i.ID] {
+				continue
+			}
+			seen[i.ID] = true
+			institutions = append(institutions, i)
+		}
+	}
+
+	return institutions
+}
@@ -0,0 +1,150 @@
+package openalex_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/openalex"
+)
+
+const work = `{
+	"id": "https://openalex.org/W2741809807",
+	"doi": "https://doi.org/10.7717/peerj.4375",
+	"title": "The state of OA",
+	"publication_year": 2018,
+	"publication_date": "2018-02-13",
+	"language": "en",
+	"type": "article",
+	"cited_by_count": 812,
+	"ids": {"openalex": "https://openalex.org/W2741809807", "doi": "https://doi.org/10.7717/peerj.4375", "pmid": "https://pubmed.ncbi.nlm.nih.gov/29456894", "mag": 2741809807},
+	"open_access": {"is_oa": true, "oa_status": "gold", "oa_url": "https://peerj.com/articles/4375.pdf"},
+	"authorships": [
+		{"author_position": "first", "author": {"id": "https://openalex.org/A5023888391", "display_name": "Heather Piwowar", "orcid": null},
+		 "institutions": [{"id": "https://openalex.org/I4200000001", "display_name": "OurResearch", "ror": "https://ror.org/02nr0ka47", "country_code": "US", "type": "nonprofit"}]},
+		{"author_position": "last", "author": {"id": "https://openalex.org/A5000000002", "display_name": "Stefanie Haustein", "orcid": "https://orcid.org/0000-0003-0157-1430"},
+		 "institutions": [
+			{"id": "https://openalex.org/I4200000001", "display_name": "OurResearch", "ror": "https://ror.org/02nr0ka47", "country_code": "US", "type": "nonprofit"},
+			{"id": "https://openalex.org/I153718931", "display_name": "University of Ottawa", "ror": "https://ror.org/03c4mmv16", "country_code": "CA", "type": "education"}
+		 ]}
+	],
+	"primary_location": {"is_oa": true, "landing_page_url": "https://doi.org/10.7717/peerj.4375", "pdf_url": "https://peerj.com/articles/4375.pdf", "license": "cc-by", "version": "publishedVersion",
+		"source": {"id": "https://openalex.org/S1983995261", "display_name": "PeerJ", "issn_l": "2167-8359", "issn": ["2167-8359"]}},
+	"best_oa_location": null,
+	"topics": [{"id": "https://openalex.org/T10102", "display_name": "scientometrics and bibliometrics research", "score": 0.99,
+		"subfield": {"id": "https://openalex.org/subfields/1804", "display_name": "Statistics, Probability and Uncertainty"}}],
+	"biblio": {"volume": "6", "issue": null, "first_page": "e4375", "last_page": "e4375"},
+	"abstract_inverted_index": {"Despite": [0], "growing": [1], "interest": [2], "in": [3, 5], "OA": [6], "research": [4]}
+}`
+
+func TestWork(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("mailto") != "library@example.edu" {
+			t.Errorf("unexpected query %v", r.URL.Query())
+		}
+		if r.URL.Path != "/works/doi:10.7717/peerj.4375" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, work)
+	}))
+	defer ts.Close()
+	openalex.URL = ts.URL
+
+	w, err := openalex.GetDoi("10.7717/peerj.4375", "library@example.edu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.DOIName() != "10.7717/peerj.4375" || w.CitedByCount != 812 || w.OpenAccess.OAStatus != "gold" {
+		t.Errorf("unexpected work %+v", w)
+	}
+	if w.IDs.PMID != "https://pubmed.ncbi.nlm.nih.gov/29456894" {
+		t.Errorf("unexpected PMID %q", w.IDs.PMID)
+	}
+	if abstract := w.Abstract(); abstract != "Despite growing interest in research in OA" {
+		t.Errorf("unexpected abstract %q", abstract)
+	}
+	if w.License() != "cc-by" || w.PDFURL() != "https://peerj.com/articles/4375.pdf" {
+		t.Errorf("expected the primary location's license and PDF, got %q %q", w.License(), w.PDFURL())
+	}
+	institutions := w.Institutions()
+	if len(institutions) != 2 || institutions[1].ROR != "https://ror.org/03c4mmv16" {
+		t.Errorf("expected each institution once, got %+v", institutions)
+	}
+	if !strings.Contains(string(w.Raw), `"mag": 2741809807`) {
+		t.Error("expected the raw JSON to be kept")
+	}
+
+	if _, err := openalex.GetDoi("10.1000/missing", "library@example.edu"); err == nil {
+		t.Error("expected an error for a DOI OpenAlex doesn't have")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f := openalex.Filter{
+		Institutions: []string{"012AFJB06", "https://ror.org/03c4mmv16"},
+		Authors:      []string{"https://openalex.org/A5023888391", "A5000000001"},
+		Topics:       []string{"T10102"},
+		From:         "2024-01-01",
+	}
+	filter, err := f.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "authorships.author.id:A5023888391|A5000000001,from_publication_date:2024-01-01,institutions.ror:https://ror.org/012afjb06|https://ror.org/03c4mmv16,topics.id:T10102"
+	if filter != expected {
+		t.Errorf("expected %s, got %s", expected, filter)
+	}
+
+	filter, err = (openalex.Filter{Authors: []string{"0000-0003-0157-1430", "https://orcid.org/0000-0002-1825-0097"}}).Encode()
+	if err != nil || filter != "authorships.author.orcid:https://orcid.org/0000-0003-0157-1430|https://orcid.org/0000-0002-1825-0097" {
+		t.Errorf("expected the ORCID iDs to be ORed, got %s %v", filter, err)
+	}
+	// OpenAlex would AND the ID and ORCID filters, finding only works by both people
+	if _, err := (openalex.Filter{Authors: []string{"A5023888391", "0000-0003-0157-1430"}}).Encode(); err == nil {
+		t.Error("expected an error for a mix of author IDs and ORCID iDs")
+	}
+	if _, err := (openalex.Filter{To: "2024"}).Encode(); err == nil {
+		t.Error("expected an error for a date without a month and day")
+	}
+	if _, err := (openalex.Filter{}).Encode(); err == nil {
+		t.Error("expected an error for an empty filter")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	pages := map[string]string{
+		"*":  fmt.Sprintf(`{"meta": {"count": 2, "per_page": 1, "next_cursor": "c2"}, "results": [%s]}`, work),
+		"c2": `{"meta": {"count": 2, "per_page": 1, "next_cursor": "c3"}, "results": [{"id": "https://openalex.org/W2", "doi": null}]}`,
+		"c3": `{"meta": {"count": 2, "per_page": 1, "next_cursor": null}, "results": []}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/works" || q.Get("filter") != "institutions.ror:https://ror.org/012afjb06" || q.Get("per-page") != "1" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprintln(w, pages[q.Get("cursor")])
+	}))
+	defer ts.Close()
+	openalex.URL = ts.URL
+
+	ids := []string{}
+	cursor := "*"
+	for cursor != "" {
+		p, err := openalex.Search("institutions.ror:https://ror.org/012afjb06", cursor, 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range p.Results {
+			ids = append(ids, openalex.ShortID(w.ID))
+		}
+		cursor = p.Meta.NextCursor
+	}
+	if strings.Join(ids, ",") != "W2741809807,W2" {
+		t.Errorf("unexpected works %v", ids)
+	}
+}
@@ -0,0 +1,163 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcomes of harvesting a record
+const (
+	Harvested = "harvested"
+	Failed    = "failed"
+	Skipped   = "skipped"
+	Updated   = "updated"
+	Unchanged = "unchanged"
+)
+
+// Tracker counts the records of a run as they're harvested
+// printing a progress line for each one and summarizing the run at the end
+type Tracker struct {
+	mu      sync.Mutex
+	out     io.Writer
+	now     func() time.Time
+	summary Summary
+}
+
+// Summary is the machine readable account of a run written to run-summary.json
+type Summary struct {
+	Command         string                    `json:"command"`
+	Started         time.Time                 `json:"started"`
+	Finished        time.Time                 `json:"finished"`
+	DurationSeconds float64                   `json:"duration_seconds"`
+	Total           int                       `json:"total"`
+	Done            int                       `json:"done"`
+	Outcomes        map[string]int            `json:"outcomes"`
+	Sources         map[string]map[string]int `json:"sources"`
+	PDFs            Hits                      `json:"pdfs"`
+	Licenses        Hits                      `json:"licenses"`
+}
+
+// Hits counts how often something (a PDF, a license) was found for a record
+type Hits struct {
+	Found   int     `json:"found"`
+	Missing int     `json:"missing"`
+	Rate    float64 `json:"rate"`
+}
+
+func (h *Hits) add(found bool) {
+	if found {
+		h.Found++
+	} else {
+		h.Missing++
+	}
+	h.Rate = float64(h.Found) / float64(h.Found+h.Missing)
+}
+
+// New starts tracking a run of command, writing progress to out
+// out can be nil to only collect the summary
+func New(command string, out io.Writer) *Tracker {
+	return newTracker(command, out, time.Now)
+}
+
+func newTracker(command string, out io.Writer, now func() time.Time) *Tracker {
+	return &Tracker{
+		out: out,
+		now: now,
+		summary: Summary{
+			Command:  command,
+			Started:  now(),
+			Outcomes: map[string]int{},
+			Sources:  map[string]map[string]int{},
+		},
+	}
+}
+
+// AddTotal adds to the number of records the run expects to harvest
+// e.g. once the first page of a query says how many results it has
+func (t *Tracker) AddTotal(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.summary.Total += n
+}
+
+// PDF records whether a PDF was found for the current record
+func (t *Tracker) PDF(found bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.summary.PDFs.add(found)
+}
+
+// License records whether a license was found for the current record
+func (t *Tracker) License(found bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.summary.Licenses.add(found)
+}
+
+// Done counts a record from source with its outcome and prints the progress line
+func (t *Tracker) Done(source, outcome string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &t.summary
+	s.Done++
+	if s.Done > s.Total {
+		s.Total = s.Done
+	}
+	s.Outcomes[outcome]++
+	if s.Sources[source] == nil {
+		s.Sources[source] = map[string]int{}
+	}
+	s.Sources[source][outcome]++
+
+	if t.out != nil {
+		fmt.Fprintln(t.out, t.line())
+	}
+}
+
+// line formats the progress so far
+// e.g. [12/40] 30% ETA 1m30s | PDFs 10 found, 2 missing | licenses 8/12 (67%)
+func (t *Tracker) line() string {
+	s := t.summary
+	eta := "unknown"
+	if s.Done > 0 {
+		elapsed := t.now().Sub(s.Started)
+		remaining := time.Duration(float64(elapsed) / float64(s.Done) * float64(s.Total-s.Done))
+		eta = remaining.Round(time.Second).String()
+	}
+	line := fmt.Sprintf("[%d/%d] %d%% ETA %s", s.Done, s.Total, s.Done*100/s.Total, eta)
+	if p := s.PDFs; p.Found+p.Missing > 0 {
+		line += fmt.Sprintf(" | PDFs %d found, %d missing", p.Found, p.Missing)
+	}
+	if l := s.Licenses; l.Found+l.Missing > 0 {
+		line += fmt.Sprintf(" | licenses %d/%d (%.0f%%)", l.Found, l.Found+l.Missing, l.Rate*100)
+	}
+
+	return line
+}
+
+// Summary finishes the run, returning its summary
+func (t *Tracker) Summary() Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.summary
+	s.Finished = t.now()
+	s.DurationSeconds = s.Finished.Sub(s.Started).Seconds()
+
+	return s
+}
+
+// WriteSummary writes the run's summary as JSON to path
+func (t *Tracker) WriteSummary(path string) error {
+	b, err := json.MarshalIndent(t.Summary(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
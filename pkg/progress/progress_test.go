@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+
+	var out bytes.Buffer
+	tracker := newTracker("get doi", &out, now)
+	tracker.AddTotal(4)
+
+	clock = clock.Add(10 * time.Second)
+	tracker.PDF(true)
+	tracker.License(true)
+	tracker.Done("crossref", Harvested)
+
+	clock = clock.Add(10 * time.Second)
+	tracker.PDF(false)
+	tracker.License(false)
+	tracker.Done("crossref", Harvested)
+
+	clock = clock.Add(10 * time.Second)
+	tracker.Done("crossref", Failed)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{
+		"[1/4] 25% ETA 30s | PDFs 1 found, 0 missing | licenses 1/1 (100%)",
+		"[2/4] 50% ETA 20s | PDFs 1 found, 1 missing | licenses 1/2 (50%)",
+		"[3/4] 75% ETA 10s | PDFs 1 found, 1 missing | licenses 1/2 (50%)",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected progress\n%s\ngot\n%s", strings.Join(expected, "\n"), out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "run-summary.json")
+	if err := tracker.WriteSummary(path); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Summary
+	if err := json.Unmarshal(b, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Command != "get doi" || s.Total != 4 || s.Done != 3 || s.DurationSeconds != 30 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if s.Outcomes[Harvested] != 2 || s.Outcomes[Failed] != 1 || s.Sources["crossref"][Failed] != 1 {
+		t.Errorf("unexpected outcomes %v %v", s.Outcomes, s.Sources)
+	}
+	if s.PDFs.Found != 1 || s.PDFs.Missing != 1 || s.PDFs.Rate != 0.5 {
+		t.Errorf("unexpected PDF hits %+v", s.PDFs)
+	}
+}
+
+func TestTrackerWithoutTotal(t *testing.T) {
+	var out bytes.Buffer
+	tracker := New("search arxiv", &out)
+	tracker.Done("arxiv", Skipped)
+	if !strings.HasPrefix(out.String(), "[1/1] 100%") {
+		t.Errorf("expected the total to grow with the records done, got %q", out.String())
+	}
+}
@@ -0,0 +1,236 @@
+package pubmed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArticleSet is the PubMed XML returned by efetch
+type ArticleSet struct {
+	XMLName  xml.Name  `xml:"PubmedArticleSet"`
+	Articles []Article `xml:"PubmedArticle"`
+}
+
+// Article is a PubMed record
+type Article struct {
+	// Raw is the article's XML, without the PubmedArticle element
+	Raw              string         `xml:",innerxml"`
+	PMID             string         `xml:"MedlineCitation>PMID"`
+	Journal          Journal        `xml:"MedlineCitation>Article>Journal"`
+	Title            Markup         `xml:"MedlineCitation>Article>ArticleTitle"`
+	Pagination       string         `xml:"MedlineCitation>Article>Pagination>MedlinePgn"`
+	AbstractTexts    []AbstractText `xml:"MedlineCitation>Article>Abstract>AbstractText"`
+	Authors          []Author       `xml:"MedlineCitation>Article>AuthorList>Author"`
+	Languages        []string       `xml:"MedlineCitation>Article>Language"`
+	PublicationTypes []string       `xml:"MedlineCitation>Article>PublicationTypeList>PublicationType"`
+	ArticleDates     []Date         `xml:"MedlineCitation>Article>ArticleDate"`
+	MeshHeadings     []MeshHeading  `xml:"MedlineCitation>MeshHeadingList>MeshHeading"`
+	Keywords         []string       `xml:"MedlineCitation>KeywordList>Keyword"`
+	ArticleIDs       []ArticleID    `xml:"PubmedData>ArticleIdList>ArticleId"`
+}
+
+type Journal struct {
+	ISSNs           []ISSN       `xml:"ISSN"`
+	Title           string       `xml:"Title"`
+	ISOAbbreviation string       `xml:"ISOAbbreviation"`
+	Issue           JournalIssue `xml:"JournalIssue"`
+}
+
+type ISSN struct {
+	// Type is Print or Electronic
+	Type  string `xml:"IssnType,attr"`
+	Value string `xml:",chardata"`
+}
+
+type JournalIssue struct {
+	Volume  string `xml:"Volume"`
+	Issue   string `xml:"Issue"`
+	PubDate Date   `xml:"PubDate"`
+}
+
+// Date is a PubMed date, whose month may be a number or an abbreviation like Jan
+// MedlineDate holds dates that don't fit e.g. 2019 Jan-Feb
+type Date struct {
+	Year        string `xml:"Year"`
+	Month       string `xml:"Month"`
+	Day         string `xml:"Day"`
+	Season      string `xml:"Season"`
+	MedlineDate string `xml:"MedlineDate"`
+}
+
+// Markup is text that can contain inline elements like <i> or <sup>
+type Markup struct {
+	Inner string `xml:",innerxml"`
+}
+
+type AbstractText struct {
+	Label string `xml:"Label,attr"`
+	Markup
+}
+
+type Author struct {
+	LastName       string       `xml:"LastName"`
+	ForeName       string       `xml:"ForeName"`
+	Initials       string       `xml:"Initials"`
+	CollectiveName string       `xml:"CollectiveName"`
+	Affiliations   []string     `xml:"AffiliationInfo>Affiliation"`
+	Identifiers    []Identifier `xml:"Identifier"`
+}
+
+type Identifier struct {
+	Source string `xml:"Source,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// MeshHeading is a Medical Subject Heading the article was indexed with
+type MeshHeading struct {
+	Descriptor MeshTerm   `xml:"DescriptorName"`
+	Qualifiers []MeshTerm `xml:"QualifierName"`
+}
+
+type MeshTerm struct {
+	// UI is the term's MeSH ID e.g. D006801
+	UI         string `xml:"UI,attr"`
+	MajorTopic string `xml:"MajorTopicYN,attr"`
+	Name       string `xml:",chardata"`
+}
+
+// ArticleID is one of the article's identifiers e.g. its doi, pmc or pii
+type ArticleID struct {
+	Type  string `xml:"IdType,attr"`
+	Value string `xml:",chardata"`
+}
+
+var tags = regexp.MustCompile(`<[^>]+>`)
+
+// jatsInline maps PubMed's inline elements to their JATS names, sup and sub are the same in both
+var jatsInline = strings.NewReplacer(
+	"<i>", "<italic>", "</i>", "</italic>",
+	"<b>", "<bold>", "</b>", "</bold>",
+	"<u>", "<underline>", "</u>", "</underline>",
+)
+
+// Text strips the markup
+func (m Markup) Text() string {
+	return strings.TrimSpace(html.UnescapeString(tags.ReplaceAllString(m.Inner, "")))
+}
+
+// ID returns the article's identifier of the given type e.g. doi or pmc
+func (a Article) ID(idType string) string {
+	for _, id := range a.ArticleIDs {
+		if strings.EqualFold(id.Type, idType) {
+			return strings.TrimSpace(id.Value)
+		}
+	}
+
+	return ""
+}
+
+func (a Article) DOI() string {
+	return a.ID("doi")
+}
+
+// PMCID is the article's PubMed Central ID e.g. PMC1234567, if it's in PMC
+func (a Article) PMCID() string {
+	return a.ID("pmc")
+}
+
+// AbstractJATS returns the abstract as JATS so it can be converted like a Crossref abstract
+// the labels of structured abstracts (e.g. BACKGROUND, METHODS) become section titles
+func (a Article) AbstractJATS() string {
+	if len(a.AbstractTexts) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<abstract>")
+	for _, t := range a.AbstractTexts {
+		b.WriteString("<sec>")
+		if t.Label != "" {
+			fmt.Fprintf(&b, "<title>%s</title>", html.EscapeString(t.Label))
+		}
+		fmt.Fprintf(&b, "<p>%s</p>", jatsInline.Replace(t.Inner))
+		b.WriteString("</sec>")
+	}
+	b.WriteString("</abstract>")
+
+	return b.String()
+}
+
+// Issued returns the article's publication date as an EDTF date
+// e.g. 2024, 2024-03 or 2024-03-15, falling back to its electronic publication date
+func (a Article) Issued() string {
+	if d := a.Journal.Issue.PubDate.EDTF(); d != "" {
+		return d
+	}
+	for _, d := range a.ArticleDates {
+		if edtf := d.EDTF(); edtf != "" {
+			return edtf
+		}
+	}
+
+	return ""
+}
+
+// EDTF formats the date as YYYY, YYYY-MM or YYYY-MM-DD
+func (d Date) EDTF() string {
+	year := d.Year
+	if year == "" && len(d.MedlineDate) >= 4 {
+		year = d.MedlineDate[:4]
+	}
+	if _, err := strconv.Atoi(year); err != nil {
+		return ""
+	}
+
+	month := 0
+	if m, err := strconv.Atoi(d.Month); err == nil {
+		month = m
+	} else if t, err := time.Parse("Jan", d.Month); err == nil {
+		month = int(t.Month())
+	}
+	if month < 1 || month > 12 {
+		return year
+	}
+	day, err := strconv.Atoi(d.Day)
+	if err != nil || day < 1 {
+		return fmt.Sprintf("%s-%02d", year, month)
+	}
+
+	return fmt.Sprintf("%s-%02d-%02d", year, month, day)
+}
+
+// Name returns the author's name as Family, Given or the name of a group author
+func (a Author) Name() string {
+	if a.LastName == "" {
+		return a.CollectiveName
+	}
+	given := a.ForeName
+	if given == "" {
+		given = a.Initials
+	}
+	if given == "" {
+		return a.LastName
+	}
+
+	return fmt.Sprintf("%s, %s", a.LastName, given)
+}
+
+// ORCID returns the author's ORCID iD e.g. 0000-0002-1825-0097, if PubMed has it
+func (a Author) ORCID() string {
+	for _, id := range a.Identifiers {
+		if strings.EqualFold(id.Source, "ORCID") {
+			v := strings.TrimSpace(id.Value)
+			for _, prefix := range []string{"https://orcid.org/", "http://orcid.org/"} {
+				v = strings.TrimPrefix(v, prefix)
+			}
+			return v
+		}
+	}
+
+	return ""
+}
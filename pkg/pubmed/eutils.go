@@ -0,0 +1,131 @@
+package pubmed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// EutilsURL is the base URL of NCBI's E-utilities
+var EutilsURL = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+
+// Credentials identify papercut to NCBI
+// an API key raises the rate limit from three to ten requests a second
+type Credentials struct {
+	APIKey string
+	Email  string
+}
+
+func (c Credentials) params() url.Values {
+	params := url.Values{}
+	params.Set("tool", "papercut")
+	if c.APIKey != "" {
+		params.Set("api_key", c.APIKey)
+	}
+	if c.Email != "" {
+		params.Set("email", c.Email)
+	}
+
+	return params
+}
+
+// Delay is how long NCBI asks us to wait between requests
+func (c Credentials) Delay() time.Duration {
+	if c.APIKey != "" {
+		return 100 * time.Millisecond
+	}
+
+	return 334 * time.Millisecond
+}
+
+// Search is a search whose results NCBI keeps on its history server
+// for them to be fetched a page at a time
+type Search struct {
+	Count    int
+	QueryKey string
+	WebEnv   string
+}
+
+type esearchResponse struct {
+	Result struct {
+		Count    string `json:"count"`
+		QueryKey string `json:"querykey"`
+		WebEnv   string `json:"webenv"`
+		// ErrorList holds problems like phrases that weren't found
+		ErrorList map[string][]string `json:"errorlist"`
+	} `json:"esearchresult"`
+	Error string `json:"error"`
+}
+
+// NewSearch runs a PubMed search e.g. "Smith J[au] AND Lehigh University[ad]"
+// keeping the results on NCBI's history server
+func NewSearch(eutilsURL, term string, c Credentials) (*Search, error) {
+	params := c.params()
+	params.Set("db", "pubmed")
+	params.Set("term", term)
+	params.Set("usehistory", "y")
+	params.Set("retmax", "0")
+	params.Set("retmode", "json")
+
+	body, err := utils.Fetch(fmt.Sprintf("%s/esearch.fcgi?%s", eutilsURL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var r esearchResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal search results: %v", err)
+	}
+	if r.Error != "" {
+		return nil, fmt.Errorf("PubMed search failed: %s", r.Error)
+	}
+
+	count, err := strconv.Atoi(r.Result.Count)
+	if err != nil {
+		return nil, fmt.Errorf("PubMed search returned an invalid count %q", r.Result.Count)
+	}
+
+	return &Search{Count: count, QueryKey: r.Result.QueryKey, WebEnv: r.Result.WebEnv}, nil
+}
+
+// Fetch returns a page of the search's results starting from start
+func (s *Search) Fetch(eutilsURL string, start, max int, c Credentials) (*ArticleSet, error) {
+	params := c.params()
+	params.Set("db", "pubmed")
+	params.Set("query_key", s.QueryKey)
+	params.Set("WebEnv", s.WebEnv)
+	params.Set("retstart", strconv.Itoa(start))
+	params.Set("retmax", strconv.Itoa(max))
+	params.Set("retmode", "xml")
+
+	body, err := utils.Fetch(fmt.Sprintf("%s/efetch.fcgi?%s", eutilsURL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseArticleSet(body)
+}
+
+// ParseArticleSet reads PubMed XML
+func ParseArticleSet(body []byte) (*ArticleSet, error) {
+	var set ArticleSet
+	if err := xml.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("could not unmarshal PubMed XML: %v", err)
+	}
+
+	return &set, nil
+}
+
+// FetchPMC returns the JATS XML of an article in PubMed Central
+func FetchPMC(eutilsURL, pmcid string, c Credentials) ([]byte, error) {
+	params := c.params()
+	params.Set("db", "pmc")
+	params.Set("id", NormalizePMCID(pmcid)[3:])
+	params.Set("retmode", "xml")
+
+	return utils.Fetch(fmt.Sprintf("%s/efetch.fcgi?%s", eutilsURL, params.Encode()), nil)
+}
@@ -0,0 +1,79 @@
+package pubmed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OAURL is the PMC Open Access Web Service
+var OAURL = "https://www.ncbi.nlm.nih.gov/pmc/utils/oa/oa.fcgi"
+
+// OAResponse is the PMC Open Access Web Service's answer for an article
+type OAResponse struct {
+	XMLName xml.Name `xml:"OA"`
+	Error   struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"error"`
+	Records []OARecord `xml:"records>record"`
+}
+
+// OARecord is an article in the PMC Open Access Subset
+type OARecord struct {
+	ID       string `xml:"id,attr"`
+	Citation string `xml:"citation,attr"`
+	// License is a short name like CC BY or NO-CC CODE
+	License   string   `xml:"license,attr"`
+	Retracted string   `xml:"retracted,attr"`
+	Links     []OALink `xml:"link"`
+}
+
+// OALink is a file of the article, either a PDF or a tgz package of its JATS XML, PDF and figures
+type OALink struct {
+	Format  string `xml:"format,attr"`
+	Updated string `xml:"updated,attr"`
+	Href    string `xml:"href,attr"`
+}
+
+// NormalizePMCID formats a PubMed Central ID as PMC followed by its number
+func NormalizePMCID(id string) string {
+	id = strings.ToUpper(strings.TrimSpace(id))
+
+	return "PMC" + strings.TrimPrefix(id, "PMC")
+}
+
+// ParseOAResponse reads a PMC Open Access Web Service response
+// returning an error if the article isn't in the Open Access Subset
+func ParseOAResponse(body []byte) (*OARecord, error) {
+	var r OAResponse
+	if err := xml.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal PMC OA response: %v", err)
+	}
+	if r.Error.Code != "" {
+		return nil, fmt.Errorf("%s: %s", r.Error.Code, strings.TrimSpace(r.Error.Message))
+	}
+	if len(r.Records) == 0 {
+		return nil, fmt.Errorf("PMC OA response has no records")
+	}
+
+	return &r.Records[0], nil
+}
+
+// OAQueryURL is the PMC Open Access Web Service URL for an article
+func OAQueryURL(oaURL, pmcid string) string {
+	return fmt.Sprintf("%s?%s", oaURL, url.Values{"id": {NormalizePMCID(pmcid)}}.Encode())
+}
+
+// Link returns the URL of the article's file in a format (pdf or tgz), or "" if there isn't one
+// the service lists FTP URLs, which NCBI also serves over HTTPS
+func (r OARecord) Link(format string) string {
+	for _, l := range r.Links {
+		if l.Format == format {
+			return strings.Replace(l.Href, "ftp://ftp.ncbi.nlm.nih.gov/", "https://ftp.ncbi.nlm.nih.gov/", 1)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,205 @@
+package pubmed_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/abstract"
+	"github.com/lehigh-university-libraries/papercut/pkg/pubmed"
+)
+
+const articleSet = `<?xml version="1.0" ?>
+<!DOCTYPE PubmedArticleSet PUBLIC "-//NLM//DTD PubMedArticle, 1st January 2024//EN" "https://dtd.nlm.nih.gov/ncbi/pubmed/out/pubmed_240101.dtd">
+<PubmedArticleSet>
+<PubmedArticle>
+  <MedlineCitation Status="MEDLINE" Owner="NLM">
+    <PMID Version="1">38000001</PMID>
+    <Article PubModel="Print-Electronic">
+      <Journal>
+        <ISSN IssnType="Electronic">1476-4687</ISSN>
+        <JournalIssue CitedMedium="Internet">
+          <Volume>625</Volume>
+          <Issue>7994</Issue>
+          <PubDate><Year>2024</Year><Month>Jan</Month></PubDate>
+        </JournalIssue>
+        <Title>Nature</Title>
+        <ISOAbbreviation>Nature</ISOAbbreviation>
+      </Journal>
+      <ArticleTitle>Gene expression in <i>Drosophila</i> &amp; mice.</ArticleTitle>
+      <Pagination><MedlinePgn>100-110</MedlinePgn></Pagination>
+      <Abstract>
+        <AbstractText Label="BACKGROUND" NlmCategory="BACKGROUND">Flies are <i>small</i>.</AbstractText>
+        <AbstractText Label="RESULTS" NlmCategory="RESULTS">Mice are bigger.</AbstractText>
+      </Abstract>
+      <AuthorList CompleteYN="Y">
+        <Author ValidYN="Y">
+          <LastName>Smith</LastName><ForeName>Jane</ForeName><Initials>J</Initials>
+          <Identifier Source="ORCID">https://orcid.org/0000-0002-1825-0097</Identifier>
+          <AffiliationInfo><Affiliation>Lehigh University, Bethlehem, PA, USA.</Affiliation></AffiliationInfo>
+        </Author>
+        <Author ValidYN="Y"><CollectiveName>The Fly Consortium</CollectiveName></Author>
+      </AuthorList>
+      <Language>eng</Language>
+      <PublicationTypeList><PublicationType UI="D016428">Journal Article</PublicationType></PublicationTypeList>
+      <ArticleDate DateType="Electronic"><Year>2023</Year><Month>12</Month><Day>20</Day></ArticleDate>
+    </Article>
+    <MeshHeadingList>
+      <MeshHeading><DescriptorName UI="D004331" MajorTopicYN="N">Drosophila</DescriptorName></MeshHeading>
+      <MeshHeading>
+        <DescriptorName UI="D015870" MajorTopicYN="Y">Gene Expression</DescriptorName>
+        <QualifierName UI="Q000502" MajorTopicYN="N">physiology</QualifierName>
+      </MeshHeading>
+    </MeshHeadingList>
+  </MedlineCitation>
+  <PubmedData>
+    <ArticleIdList>
+      <ArticleId IdType="pubmed">38000001</ArticleId>
+      <ArticleId IdType="doi">10.1038/s41586-023-00001-1</ArticleId>
+      <ArticleId IdType="pmc">PMC10000001</ArticleId>
+    </ArticleIdList>
+    <ReferenceList>
+      <Reference><Citation>A cited paper.</Citation><ArticleIdList><ArticleId IdType="doi">10.1000/cited</ArticleId></ArticleIdList></Reference>
+    </ReferenceList>
+  </PubmedData>
+</PubmedArticle>
+</PubmedArticleSet>`
+
+func TestParseArticleSet(t *testing.T) {
+	set, err := pubmed.ParseArticleSet([]byte(articleSet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Articles) != 1 {
+		t.Fatalf("expected one article, got %d", len(set.Articles))
+	}
+	a := set.Articles[0]
+
+	if a.PMID != "38000001" || a.DOI() != "10.1038/s41586-023-00001-1" || a.PMCID() != "PMC10000001" {
+		t.Errorf("unexpected identifiers %s %s %s", a.PMID, a.DOI(), a.PMCID())
+	}
+	if title := a.Title.Text(); title != "Gene expression in Drosophila & mice." {
+		t.Errorf("unexpected title %q", title)
+	}
+	if issued := a.Issued(); issued != "2024-01" {
+		t.Errorf("expected the journal issue's date, got %q", issued)
+	}
+	names := []string{}
+	for _, author := range a.Authors {
+		names = append(names, author.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"Smith, Jane", "The Fly Consortium"}) {
+		t.Errorf("unexpected authors %v", names)
+	}
+	if orcid := a.Authors[0].ORCID(); orcid != "0000-0002-1825-0097" {
+		t.Errorf("unexpected ORCID %q", orcid)
+	}
+	if len(a.MeshHeadings) != 2 || a.MeshHeadings[1].Descriptor.UI != "D015870" || a.MeshHeadings[1].Qualifiers[0].Name != "physiology" {
+		t.Errorf("unexpected MeSH headings %+v", a.MeshHeadings)
+	}
+	if !strings.Contains(a.Raw, "<PMID Version=\"1\">38000001</PMID>") {
+		t.Error("expected the raw XML to be kept")
+	}
+
+	expected := "<h3>BACKGROUND</h3>\n<p>Flies are <em>small</em>.</p>\n<h3>RESULTS</h3>\n<p>Mice are bigger.</p>"
+	if got := abstract.FromJATS(a.AbstractJATS(), abstract.HTML); got != expected {
+		t.Errorf("expected abstract %q, got %q", expected, got)
+	}
+}
+
+func TestDateEDTF(t *testing.T) {
+	tests := map[pubmed.Date]string{
+		{Year: "2024", Month: "Mar", Day: "5"}: "2024-03-05",
+		{Year: "2024", Month: "03"}:            "2024-03",
+		{Year: "2024", Season: "Spring"}:       "2024",
+		{MedlineDate: "2019 Jan-Feb"}:          "2019",
+		{}:                                     "",
+	}
+	for d, expected := range tests {
+		if got := d.EDTF(); got != expected {
+			t.Errorf("%+v: expected %q, got %q", d, expected, got)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "secret" || q.Get("tool") != "papercut" {
+			t.Errorf("unexpected credentials %v", q)
+		}
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			if q.Get("term") != "Smith J[au]" || q.Get("usehistory") != "y" {
+				t.Errorf("unexpected search %v", q)
+			}
+			fmt.Fprintln(w, `{"header":{},"esearchresult":{"count":"1","retmax":"0","retstart":"0","querykey":"1","webenv":"MCID_1"}}`)
+		case "/efetch.fcgi":
+			if q.Get("WebEnv") != "MCID_1" || q.Get("query_key") != "1" || q.Get("retstart") != "0" || q.Get("retmax") != "100" {
+				t.Errorf("unexpected fetch %v", q)
+			}
+			fmt.Fprintln(w, articleSet)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := pubmed.Credentials{APIKey: "secret"}
+	s, err := pubmed.NewSearch(ts.URL, "Smith J[au]", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count != 1 {
+		t.Errorf("expected one result, got %d", s.Count)
+	}
+	set, err := s.Fetch(ts.URL, 0, 100, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Articles) != 1 || set.Articles[0].PMID != "38000001" {
+		t.Errorf("unexpected articles %+v", set.Articles)
+	}
+
+	if _, err := pubmed.NewSearch(ts.URL+"/missing", "Smith J[au]", c); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected an error without the API key, got %v", err)
+	}
+}
+
+func TestParseOAResponse(t *testing.T) {
+	r, err := pubmed.ParseOAResponse([]byte(`<OA><responseDate>2024-01-01 00:00:00</responseDate>
+		<request id="PMC10000001">https://www.ncbi.nlm.nih.gov/pmc/utils/oa/oa.fcgi?id=PMC10000001</request>
+		<records returned-count="1" total-count="1">
+			<record id="PMC10000001" citation="Nature. 2024; 625:100" license="CC BY" retracted="no">
+				<link format="tgz" updated="2024-01-02 10:00:00" href="ftp://ftp.ncbi.nlm.nih.gov/pub/pmc/oa_package/00/01/PMC10000001.tar.gz" />
+				<link format="pdf" updated="2024-01-02 10:00:00" href="ftp://ftp.ncbi.nlm.nih.gov/pub/pmc/oa_pdf/00/01/nature.PMC10000001.pdf" />
+			</record>
+		</records></OA>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.License != "CC BY" || r.Retracted != "no" {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if pdf := r.Link("pdf"); pdf != "https://ftp.ncbi.nlm.nih.gov/pub/pmc/oa_pdf/00/01/nature.PMC10000001.pdf" {
+		t.Errorf("unexpected PDF link %q", pdf)
+	}
+	if r.Link("xml") != "" {
+		t.Error("expected no link for a format the record doesn't have")
+	}
+
+	_, err = pubmed.ParseOAResponse([]byte(`<OA><error code="idIsNotOpenAccess">identifier 'PMC1' is not Open Access</error></OA>`))
+	if err == nil || !strings.Contains(err.Error(), "idIsNotOpenAccess") {
+		t.Errorf("expected an error for an article outside the Open Access Subset, got %v", err)
+	}
+
+	if id := pubmed.NormalizePMCID(" pmc123 "); id != "PMC123" {
+		t.Errorf("unexpected PMCID %q", id)
+	}
+	if id := pubmed.NormalizePMCID("123"); id != "PMC123" {
+		t.Errorf("unexpected PMCID %q", id)
+	}
+}
@@ -0,0 +1,144 @@
+package record
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Record is a row of papercut's CSV output keyed by its column names
+// the column order of the CSV is kept when the record is marshalled to JSON
+type Record struct {
+	Columns []string
+	Values  map[string]string
+}
+
+// New pairs a CSV header with one of its rows
+// any values without a column are dropped
+func New(header, row []string) Record {
+	r := Record{
+		Columns: []string{},
+		Values:  map[string]string{},
+	}
+	for i, column := range header {
+		value := ""
+		if i < len(row) {
+			value = row[i]
+		}
+		r.Set(column, value)
+	}
+
+	return r
+}
+
+// Get returns the value of a column or an empty string if the record doesn't have it
+func (r Record) Get(column string) string {
+	return r.Values[column]
+}
+
+// Has reports whether the record has a column, even if its value is empty
+func (r Record) Has(column string) bool {
+	_, ok := r.Values[column]
+	return ok
+}
+
+// Set updates the value of a column, adding it to the end of the record if it's new
+func (r *Record) Set(column, value string) {
+	if r.Values == nil {
+		r.Values = map[string]string{}
+	}
+	if _, ok := r.Values[column]; !ok {
+		r.Columns = append(r.Columns, column)
+	}
+	r.Values[column] = value
+}
+
+// Row returns the record's values in column order
+func (r Record) Row() []string {
+	row := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		row[i] = r.Values[c]
+	}
+
+	return row
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, c := range r.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.Values[c])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (r *Record) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("expected a JSON object for a record")
+	}
+
+	*r = Record{Columns: []string{}, Values: map[string]string{}}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		column, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("expected a column name, got %v", t)
+		}
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("unable to decode %s: %v", column, err)
+		}
+		r.Set(column, value)
+	}
+
+	return nil
+}
+
+// ReadCSV reads every row of a CSV written by papercut into records
+func ReadCSV(rd io.Reader) ([]Record, error) {
+	cr := csv.NewReader(rd)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSV header: %v", err)
+	}
+
+	records := []Record{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CSV: %v", err)
+		}
+		records = append(records, New(header, row))
+	}
+
+	return records, nil
+}
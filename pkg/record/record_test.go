@@ -0,0 +1,50 @@
+package record_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+)
+
+func TestReadCSV(t *testing.T) {
+	csv := "id,title,field_subject,file\n" +
+		"10.1000/xyz123,\"A \"\"quoted\"\" title\",Physics|Chemistry,papers/dois/x.pdf\n" +
+		"2101.00001,Short row\n"
+
+	records, err := record.ReadCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if got := records[0].Get("title"); got != `A "quoted" title` {
+		t.Errorf("unexpected title %q", got)
+	}
+	if !records[1].Has("file") || records[1].Get("file") != "" {
+		t.Errorf("expected missing values to be empty, got %v", records[1].Values)
+	}
+
+	b, err := json.Marshal(records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":"10.1000/xyz123","title":"A \"quoted\" title","field_subject":"Physics|Chemistry","file":"papers/dois/x.pdf"}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	var r record.Record
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, records[0]) {
+		t.Errorf("expected %v after a round trip, got %v", records[0], r)
+	}
+	if !reflect.DeepEqual(r.Row(), []string{"10.1000/xyz123", `A "quoted" title`, "Physics|Chemistry", "papers/dois/x.pdf"}) {
+		t.Errorf("unexpected row %v", r.Row())
+	}
+}
@@ -0,0 +1,233 @@
+package retraction
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/record"
+)
+
+// Statuses of an article, from most to least serious
+const (
+	Retracted           = "retracted"
+	ExpressionOfConcern = "expression_of_concern"
+	Corrected           = "corrected"
+	// Reinstated is an article whose retraction was reversed
+	Reinstated = "reinstated"
+	// Notice is an article that is itself a retraction, correction or expression of concern
+	Notice = "notice"
+)
+
+var severity = map[string]int{
+	Retracted:           5,
+	ExpressionOfConcern: 4,
+	Corrected:           3,
+	Reinstated:          2,
+	Notice:              1,
+}
+
+// Sources of notices
+const (
+	Crossref        = "crossref"
+	RetractionWatch = "retraction-watch"
+)
+
+// Update is a notice about an article
+type Update struct {
+	// DOI is the notice's DOI or, for a Notice, the DOI of the article it updates
+	DOI    string
+	Status string
+	Date   string
+	Source string
+}
+
+// crossrefStatus maps Crossref's update types to a status
+// types like new_version aren't a problem with the article so aren't mapped
+var crossrefStatus = map[string]string{
+	"retraction":            Retracted,
+	"partial_retraction":    Retracted,
+	"withdrawal":            Retracted,
+	"removal":               Retracted,
+	"expression_of_concern": ExpressionOfConcern,
+	"correction":            Corrected,
+	"corrigendum":           Corrected,
+	"erratum":               Corrected,
+	"addendum":              Corrected,
+	"clarification":         Corrected,
+}
+
+// CrossrefUpdates returns the notices Crossref has about an article
+// from its updated-by relations and, if it's a notice itself, its update-to relations
+func CrossrefUpdates(a doi.Article) []Update {
+	updates := []Update{}
+	for _, u := range a.UpdatedBy {
+		status, ok := crossrefStatus[normalizeType(u.Type)]
+		if !ok {
+			continue
+		}
+		updates = append(updates, Update{DOI: u.DOI, Status: status, Date: joinDate(u.Updated), Source: Crossref})
+	}
+	for _, u := range a.UpdateTo {
+		if _, ok := crossrefStatus[normalizeType(u.Type)]; ok {
+			updates = append(updates, Update{DOI: u.DOI, Status: Notice, Date: joinDate(u.Updated), Source: Crossref})
+		}
+	}
+
+	return updates
+}
+
+func normalizeType(t string) string {
+	return strings.ReplaceAll(strings.ToLower(t), "-", "_")
+}
+
+func joinDate(d doi.DateParts) string {
+	if len(d.Dates) == 0 || len(d.Dates[0]) == 0 {
+		return ""
+	}
+
+	return doi.JoinDate(d)
+}
+
+// Dataset is a Retraction Watch dataset keyed by the lower cased DOI of the original paper
+type Dataset map[string][]Update
+
+// retractionWatchStatus maps the dataset's RetractionNature column to a status
+var retractionWatchStatus = map[string]string{
+	"retraction":            Retracted,
+	"expression of concern": ExpressionOfConcern,
+	"correction":            Corrected,
+	"reinstatement":         Reinstated,
+}
+
+// LoadRetractionWatch reads a Retraction Watch dataset CSV
+// e.g. retraction_watch.csv from https://gitlab.com/crossref/retraction-watch-data
+func LoadRetractionWatch(path string) (Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := record.ReadCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", path, err)
+	}
+
+	d := Dataset{}
+	for _, r := range records {
+		original := strings.ToLower(strings.TrimSpace(r.Get("OriginalPaperDOI")))
+		status, ok := retractionWatchStatus[strings.ToLower(strings.TrimSpace(r.Get("RetractionNature")))]
+		if original == "" || original == "unavailable" || !ok {
+			continue
+		}
+		notice := strings.TrimSpace(r.Get("RetractionDOI"))
+		if strings.EqualFold(notice, "unavailable") {
+			notice = ""
+		}
+		d[original] = append(d[original], Update{
+			DOI:    notice,
+			Status: status,
+			Date:   strings.TrimSpace(r.Get("RetractionDate")),
+			Source: RetractionWatch,
+		})
+	}
+
+	return d, nil
+}
+
+// Updates returns the notices in the dataset about the article with DOI id
+func (d Dataset) Updates(id string) []Update {
+	return d[strings.ToLower(id)]
+}
+
+// Check returns the notices about an article from Crossref and, if it's not nil, the dataset
+func (d Dataset) Check(a doi.Article) []Update {
+	updates := CrossrefUpdates(a)
+	for _, u := range d.Updates(a.DOI) {
+		duplicate := false
+		for _, c := range updates {
+			if u.DOI != "" && strings.EqualFold(u.DOI, c.DOI) && u.Status == c.Status {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			updates = append(updates, u)
+		}
+	}
+
+	return updates
+}
+
+// Status is the most serious status of the notices, or "" if there are none
+// retractions followed by a reinstatement no longer count, leaving the article reinstated
+// unless it has other notices
+func Status(updates []Update) string {
+	reinstated := []Update{}
+	for _, u := range updates {
+		if u.Status == Reinstated {
+			reinstated = append(reinstated, u)
+		}
+	}
+
+	status := ""
+	for _, u := range updates {
+		if u.Status == Retracted && reversed(u, reinstated) {
+			continue
+		}
+		if severity[u.Status] > severity[status] {
+			status = u.Status
+		}
+	}
+
+	return status
+}
+
+// reversed reports whether a retraction was followed by one of the reinstatements
+// a reinstatement without a date, or of a retraction without one, is taken to have come after it
+func reversed(retraction Update, reinstatements []Update) bool {
+	retracted, ok := parseDate(retraction.Date)
+	for _, r := range reinstatements {
+		reinstated, known := parseDate(r.Date)
+		if !ok || !known || !reinstated.Before(retracted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dateLayouts are how Crossref (2024-05-01) and Retraction Watch (5/1/2024 0:00) write dates
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006", "1/2/2006 15:04", "1/2/2006"}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// DOIs returns the distinct DOIs of the notices
+func DOIs(updates []Update) []string {
+	dois := []string{}
+	for _, u := range updates {
+		if u.DOI == "" {
+			continue
+		}
+		seen := false
+		for _, d := range dois {
+			seen = seen || strings.EqualFold(d, u.DOI)
+		}
+		if !seen {
+			dois = append(dois, u.DOI)
+		}
+	}
+
+	return dois
+}
@@ -0,0 +1,108 @@
+package retraction_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/doi"
+	"github.com/lehigh-university-libraries/papercut/pkg/retraction"
+)
+
+func article(t *testing.T, body string) doi.Article {
+	t.Helper()
+	var a doi.Article
+	if err := json.Unmarshal([]byte(body), &a); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestCrossrefUpdates(t *testing.T) {
+	a := article(t, `{
+		"DOI": "10.1000/original",
+		"updated-by": [
+			{"DOI": "10.1000/correction", "type": "correction", "updated": {"date-parts": [[2023, 2, 1]]}},
+			{"DOI": "10.1000/v2", "type": "new_version"},
+			{"DOI": "10.1000/retraction", "type": "retraction", "updated": {"date-parts": [[2024, 5, 1]]}}
+		]
+	}`)
+	updates := retraction.CrossrefUpdates(a)
+	expected := []retraction.Update{
+		{DOI: "10.1000/correction", Status: retraction.Corrected, Date: "2023-02-01", Source: retraction.Crossref},
+		{DOI: "10.1000/retraction", Status: retraction.Retracted, Date: "2024-05-01", Source: retraction.Crossref},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("expected %+v, got %+v", expected, updates)
+	}
+	if status := retraction.Status(updates); status != retraction.Retracted {
+		t.Errorf("expected the most serious status, got %q", status)
+	}
+
+	notice := article(t, `{"DOI": "10.1000/retraction", "update-to": [{"DOI": "10.1000/original", "type": "retraction"}]}`)
+	if status := retraction.Status(retraction.CrossrefUpdates(notice)); status != retraction.Notice {
+		t.Errorf("expected a retraction notice to be flagged as a notice, got %q", status)
+	}
+
+	if status := retraction.Status(retraction.CrossrefUpdates(doi.Article{DOI: "10.1000/fine"})); status != "" {
+		t.Errorf("expected no status, got %q", status)
+	}
+}
+
+func TestRetractionWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "retraction_watch.csv")
+	csv := "\ufeffRecord ID,Title,RetractionDate,RetractionDOI,OriginalPaperDOI,RetractionNature,Reason\n" +
+		"1,A Paper,5/1/2024 0:00,10.1000/retraction,10.1000/ORIGINAL,Retraction,+Fake data;\n" +
+		"2,Another Paper,6/1/2024 0:00,10.1000/eoc,10.1000/other,Expression of concern,\n" +
+		"3,Reinstated,3/1/2021 0:00,10.1000/reinstatement,10.1000/reinstated,Reinstatement,\n" +
+		"4,No DOI,7/1/2024 0:00,unavailable,unavailable,Retraction,\n" +
+		"5,Reinstated,1/15/2020 0:00,10.1000/retraction-2020,10.1000/reinstated,Retraction,\n" +
+		"6,Retracted Again,9/1/2022 0:00,10.1000/retraction-2022,10.1000/retracted-again,Retraction,\n" +
+		"7,Retracted Again,3/1/2021 0:00,10.1000/reinstatement-2021,10.1000/retracted-again,Reinstatement,\n"
+	if err := os.WriteFile(path, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := retraction.LoadRetractionWatch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) != 4 {
+		t.Errorf("expected four papers, got %d", len(d))
+	}
+
+	// Crossref and Retraction Watch both know about the retraction
+	a := article(t, `{"DOI": "10.1000/original", "updated-by": [{"DOI": "10.1000/retraction", "type": "retraction"}]}`)
+	updates := d.Check(a)
+	if len(updates) != 1 || retraction.Status(updates) != retraction.Retracted {
+		t.Errorf("expected one retraction, got %+v", updates)
+	}
+
+	// only Retraction Watch knows about the expression of concern
+	updates = d.Check(doi.Article{DOI: "10.1000/other"})
+	if retraction.Status(updates) != retraction.ExpressionOfConcern || !reflect.DeepEqual(retraction.DOIs(updates), []string{"10.1000/eoc"}) {
+		t.Errorf("expected an expression of concern, got %+v", updates)
+	}
+
+	// the retraction was reversed, even though Crossref has it without a date
+	a = article(t, `{"DOI": "10.1000/reinstated", "updated-by": [{"DOI": "10.1000/retraction-2020", "type": "retraction"}]}`)
+	updates = d.Check(a)
+	if status := retraction.Status(updates); status != retraction.Reinstated {
+		t.Errorf("expected a reinstated paper, got %q from %+v", status, updates)
+	}
+	if dois := retraction.DOIs(updates); !reflect.DeepEqual(dois, []string{"10.1000/retraction-2020", "10.1000/reinstatement"}) {
+		t.Errorf("expected the retraction and reinstatement notices, got %v", dois)
+	}
+
+	// a reinstatement doesn't reverse a later retraction
+	if status := retraction.Status(d.Check(doi.Article{DOI: "10.1000/retracted-again"})); status != retraction.Retracted {
+		t.Errorf("expected a paper retracted after being reinstated to be retracted, got %q", status)
+	}
+
+	var none retraction.Dataset
+	if updates := none.Check(doi.Article{DOI: "10.1000/other"}); len(updates) != 0 {
+		t.Errorf("expected no updates without a dataset, got %+v", updates)
+	}
+}
@@ -0,0 +1,146 @@
+package romeo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// RetrieveURL is the Sherpa Romeo v2 API's retrieve endpoint
+var RetrieveURL = "https://v2.sherpa.ac.uk/cgi/retrieve"
+
+// Limiter spaces out requests to Sherpa Romeo
+// it's shared by every lookup, including the concurrent ones papercut serve makes
+var Limiter = ratelimit.New(time.Second)
+
+// NegativeCacheTTL is how long we remember that no publication has an ISSN
+// before asking Sherpa Romeo again
+var NegativeCacheTTL = 30 * 24 * time.Hour
+
+var (
+	ErrNoApiKey = errors.New("the SHERPA_ROMEO_API_KEY environment variable was not found")
+	ErrNotFound = errors.New("no Sherpa Romeo publication has this ISSN")
+	// ErrCheckDigit is an ISSN whose check digit is wrong, which still gets used in publishers' metadata
+	ErrCheckDigit = errors.New("bad check digit")
+)
+
+// NormalizeIssn formats an ISSN as NNNN-NNNC, checking its check digit
+// an ISSN with the wrong check digit is still returned, along with an error wrapping ErrCheckDigit
+func NormalizeIssn(i string) (string, error) {
+	s := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(i)))
+	if len(s) != 8 {
+		return "", fmt.Errorf("invalid ISSN %q", i)
+	}
+
+	sum := 0
+	for n, c := range s[:7] {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("invalid ISSN %q", i)
+		}
+		sum += int(c-'0') * (8 - n)
+	}
+	check := (11 - sum%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+	issn := s[:4] + "-" + s[4:]
+	if s[7] != expected {
+		return issn, fmt.Errorf("invalid ISSN %q: %w", i, ErrCheckDigit)
+	}
+
+	return issn, nil
+}
+
+// GetIssnPublication returns the Sherpa Romeo publications with ISSN i
+// a journal can have more than one e.g. when a title has been split or merged
+// the response is cached, including when nothing matches, in which case ErrNotFound is returned
+// ISSNs with the wrong check digit are looked up anyway since Sherpa Romeo can have them too
+func GetIssnPublication(i string) (*Response, error) {
+	issn, err := NormalizeIssn(i)
+	if errors.Is(err, ErrCheckDigit) {
+		log.Printf("Looking up %s even though its check digit is wrong", issn)
+	} else if err != nil {
+		return nil, err
+	}
+
+	d, err := utils.MkTmpDir(filepath.Join("issns", "publications"))
+	if err != nil {
+		return nil, err
+	}
+	d = filepath.Join(d, issn+".json")
+
+	r, err := cachedIssnPublication(d)
+	if err != nil {
+		romeoApiKey := os.Getenv("SHERPA_ROMEO_API_KEY")
+		if romeoApiKey == "" {
+			return nil, ErrNoApiKey
+		}
+
+		filter := fmt.Sprintf("[[\"issn\",\"equals\",\"%s\"]]", issn)
+		params := neturl.Values{}
+		params.Set("item-type", "publication")
+		params.Set("format", "Json")
+		params.Set("limit", "10")
+		params.Set("order", "id")
+		params.Set("filter", filter)
+		params.Set("api-key", romeoApiKey)
+		Limiter.Wait()
+		publication, err := utils.Fetch(fmt.Sprintf("%s?%s", RetrieveURL, params.Encode()), map[string]string{"Accept": "application/json"})
+		if err != nil {
+			return nil, fmt.Errorf("could not find publication info for %s: %v", issn, err)
+		}
+
+		r = &Response{}
+		if err := json.Unmarshal(publication, r); err != nil {
+			return nil, fmt.Errorf("unable to read publication: %v", err)
+		}
+		utils.WriteCachedFile(d, string(publication))
+	}
+
+	switch len(r.Publications) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, issn)
+	case 1:
+	default:
+		ids := []string{}
+		for _, p := range r.Publications {
+			ids = append(ids, fmt.Sprint(p.ID))
+		}
+		log.Printf("ISSN %s matches %d Sherpa Romeo publications (%s)", issn, len(r.Publications), strings.Join(ids, ", "))
+	}
+
+	return r, nil
+}
+
+// cachedIssnPublication reads a cached response
+// ignoring ones that found nothing once they're older than NegativeCacheTTL
+func cachedIssnPublication(d string) (*Response, error) {
+	info, err := os.Stat(d)
+	if err != nil {
+		return nil, err
+	}
+	b := utils.CheckCachedFile(d)
+	if b == nil {
+		return nil, fmt.Errorf("could not read %s", d)
+	}
+
+	var r Response
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	if len(r.Publications) == 0 && time.Since(info.ModTime()) > NegativeCacheTTL {
+		return nil, fmt.Errorf("%s has expired", d)
+	}
+
+	return &r, nil
+}
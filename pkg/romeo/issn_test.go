@@ -0,0 +1,145 @@
+package romeo_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/internal/ratelimit"
+	"github.com/lehigh-university-libraries/papercut/pkg/romeo"
+)
+
+func TestNormalizeIssn(t *testing.T) {
+	tests := map[string]string{
+		"0028-0836":   "0028-0836",
+		"00280836":    "0028-0836",
+		" 2434-561x ": "2434-561X",
+	}
+	for in, expected := range tests {
+		got, err := romeo.NormalizeIssn(in)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+		} else if got != expected {
+			t.Errorf("%q: expected %q, got %q", in, expected, got)
+		}
+	}
+
+	for _, in := range []string{"", "0028-083", "002A-0836", "https://v2.sherpa.ac.uk/id/publication/1"} {
+		if _, err := romeo.NormalizeIssn(in); err == nil || errors.Is(err, romeo.ErrCheckDigit) {
+			t.Errorf("expected %q to be invalid, got %v", in, err)
+		}
+	}
+
+	if got, err := romeo.NormalizeIssn("00280837"); !errors.Is(err, romeo.ErrCheckDigit) || got != "0028-0837" {
+		t.Errorf("expected 0028-0837 to be formatted with a check digit error, got %q %v", got, err)
+	}
+}
+
+func TestGetIssnPublication(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("SHERPA_ROMEO_API_KEY", "secret")
+
+	responses := map[string]string{
+		`[["issn","equals","0028-0836"]]`: `{"items":[
+			{"id":1,"issns":[{"issn":"0028-0836","type":"print"}],"publisher_policy":[{"uri":"https://v2.sherpa.ac.uk/id/publisher_policy/1"}]},
+			{"id":2,"issns":[{"issn":"0028-0836","type":"print"}],"publisher_policy":[{"uri":"https://v2.sherpa.ac.uk/id/publisher_policy/2","permitted_oa":[
+				{"article_version":["published"],"location":{"location":["any_website"]},"license":[{"license":"cc_by","version":"4.0"}]}
+			]}]}
+		]}`,
+		`[["issn","equals","0000-0000"]]`: `{"items":[]}`,
+		`[["issn","equals","1234-5678"]]`: `{"items":[{"id":3,"issns":[{"issn":"1234-5678","type":"electronic"}]}]}`,
+	}
+	requests := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("item-type") != "publication" || q.Get("format") != "Json" || q.Get("api-key") != "secret" {
+			t.Errorf("unexpected query %v", q)
+		}
+		if !strings.HasPrefix(r.UserAgent(), "papercut") {
+			t.Errorf("expected papercut's User-Agent, got %q", r.UserAgent())
+		}
+		requests[q.Get("filter")]++
+		body, ok := responses[q.Get("filter")]
+		if !ok {
+			http.Error(w, "unexpected filter", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, body)
+	}))
+	defer ts.Close()
+	romeo.RetrieveURL = ts.URL
+	romeo.Limiter = ratelimit.New(0)
+
+	for range 2 {
+		r, err := romeo.GetIssnPublication("00280836")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Publications) != 2 {
+			t.Fatalf("expected both publications, got %d", len(r.Publications))
+		}
+		if license := r.GetLicense(); license != "https://creativecommons.org/licenses/by/4.0/" {
+			t.Errorf("expected the CC license from the second publication, got %q", license)
+		}
+
+		_, err = romeo.GetIssnPublication("0000-0000")
+		if !errors.Is(err, romeo.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	}
+	for filter, n := range requests {
+		if n != 1 {
+			t.Errorf("expected %s to be requested once then cached, got %d requests", filter, n)
+		}
+	}
+
+	if _, err := romeo.GetIssnPublication("0028-083"); err == nil {
+		t.Error("expected an error for an invalid ISSN")
+	}
+	// 1234-5678 should end in 9 but journals are registered with ISSNs like it
+	if r, err := romeo.GetIssnPublication("1234-5678"); err != nil || len(r.Publications) != 1 {
+		t.Errorf("expected an ISSN with the wrong check digit to be looked up, got %v %v", r, err)
+	}
+	if _, err := romeo.GetIssnPublication("2434-561X"); err == nil || errors.Is(err, romeo.ErrNotFound) {
+		t.Errorf("expected a failed request to not be treated as not found, got %v", err)
+	} else if strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the API key to be left out of %q", err)
+	}
+
+	t.Setenv("SHERPA_ROMEO_API_KEY", "")
+	if _, err := romeo.GetIssnPublication("1476-4687"); !errors.Is(err, romeo.ErrNoApiKey) {
+		t.Errorf("expected ErrNoApiKey, got %v", err)
+	}
+}
+
+func TestGetLicense(t *testing.T) {
+	r := romeo.Response{Publications: []romeo.Publication{
+		{PublisherPolicies: []romeo.PublisherPolicy{{Uri: "https://v2.sherpa.ac.uk/id/publisher_policy/1"}}},
+		{PublisherPolicies: []romeo.PublisherPolicy{{Uri: "https://v2.sherpa.ac.uk/id/publisher_policy/2"}}},
+	}}
+	if license := r.GetLicense(); license != "https://v2.sherpa.ac.uk/id/publisher_policy/1" {
+		t.Errorf("expected the first publisher policy, got %q", license)
+	}
+}
+
+func TestRepositoryEmbargo(t *testing.T) {
+	r := romeo.Response{Publications: []romeo.Publication{{PublisherPolicies: []romeo.PublisherPolicy{{PermittedOa: []romeo.OpenAccess{
+		{ArticleVersion: []string{"submitted"}, Location: romeo.Location{Locations: []string{"any_website"}}},
+		{ArticleVersion: []string{"accepted"}, Location: romeo.Location{Locations: []string{"authors_homepage"}}},
+		{ArticleVersion: []string{"accepted"}, Location: romeo.Location{Locations: []string{"institutional_repository"}}, Embargo: romeo.Embargo{Amount: 2, Units: "years"}},
+		{ArticleVersion: []string{"accepted"}, Location: romeo.Location{Locations: []string{"funder_designated_location"}}, Embargo: romeo.Embargo{Amount: 12, Units: "months"}},
+	}}}}}}
+
+	if months, ok := r.RepositoryEmbargo("accepted"); !ok || months != 12 {
+		t.Errorf("expected a 12 month embargo for the accepted version, got %d, %v", months, ok)
+	}
+	if months, ok := r.RepositoryEmbargo("submitted"); !ok || months != 0 {
+		t.Errorf("expected no embargo for the submitted version, got %d, %v", months, ok)
+	}
+	if _, ok := r.RepositoryEmbargo("published"); ok {
+		t.Error("expected the published version to not be allowed in a repository")
+	}
+}
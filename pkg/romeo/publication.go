@@ -1,14 +1,8 @@
 package romeo
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	neturl "net/url"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/lehigh-university-libraries/papercut/internal/utils"
@@ -19,9 +13,21 @@ type Response struct {
 }
 
 type Publication struct {
+	ID                int               `json:"id"`
+	Titles            []Title           `json:"title"`
+	ISSNs             []ISSN            `json:"issns"`
 	PublisherPolicies []PublisherPolicy `json:"publisher_policy"`
 }
 
+type Title struct {
+	Title string `json:"title"`
+}
+
+type ISSN struct {
+	ISSN string `json:"issn"`
+	Type string `json:"type"`
+}
+
 type PublisherPolicy struct {
 	Uri                  string       `json:"uri"`
 	OpenAccessProhibited string       `json:"open_access_prohibited"`
@@ -46,92 +52,47 @@ type Embargo struct {
 	Units  string `json:"units,omitempty"`
 }
 
-type License struct {
-	Value   string `json:"license"`
-	Version string `json:"version"`
-}
-
-func GetIdFromIssn(i string) string {
-	d, err := utils.MkTmpDir("issns")
-	if err != nil {
-		return ""
-	}
-	d = filepath.Join(d, i)
-	publicationId := utils.CheckCachedFile(d)
-	id := string(publicationId)
-	if publicationId != nil {
-		return id
-	}
-
-	url := fmt.Sprintf("https://v2.sherpa.ac.uk//cgi/romeosearch?publication_title-auto=%s", i)
-	// Create a custom HTTP client with redirection disabled
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse // Prevent automatic redirection
-		},
+// Months is the embargo rounded up to whole months
+func (e Embargo) Months() int {
+	switch e.Units {
+	case "years":
+		return e.Amount * 12
+	case "weeks":
+		return (e.Amount*7 + 29) / 30
+	case "days":
+		return (e.Amount + 29) / 30
 	}
 
-	resp, err := client.Get(url)
-	if err != nil {
-		return ""
-	}
-	defer resp.Body.Close()
-
-	// Check if the response status code is 301 (Moved Permanently)
-	if resp.StatusCode == http.StatusFound {
-		location := strings.Split(resp.Header.Get("Location"), "/")
-		id = location[len(location)-1]
-		utils.WriteCachedFile(d, id)
-		return id
-	}
+	return e.Amount
+}
 
-	return ""
+type License struct {
+	Value   string `json:"license"`
+	Version string `json:"version"`
 }
 
+// GetPublication returns the body of a Sherpa Romeo API response
+// or nil if the request failed
 func GetPublication(url string) []byte {
-	client := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-		},
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Println("Error creating request:", err)
-		return nil
-	}
-
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "application/pdf")
-	req.Header.Set("Accept-Language", "en-US")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Cache-Control", "no-cache")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error downloading PDF:", err)
-		return nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode > 299 {
-		log.Printf("Error: HTTP status %d\n", resp.StatusCode)
-		return nil
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := utils.Fetch(url, map[string]string{"Accept": "application/json"})
 	if err != nil {
+		log.Println("Error accessing Sherpa Romeo:", err)
 		return nil
 	}
 
 	return body
 }
 
+// GetLicense returns the CC license the published version can be shared under
+// from any of the publications in the response
+// falling back to the first publisher policy's URI
 func (r *Response) GetLicense() string {
 	license := ""
 	for _, p := range r.Publications {
 		for _, policy := range p.PublisherPolicies {
-			license = policy.Uri
+			if license == "" {
+				license = policy.Uri
+			}
 			for _, oa := range policy.PermittedOa {
 				if utils.StrInSlice("published", oa.ArticleVersion) {
 					if !utils.StrInSlice("any_website", oa.Location.Locations) && !utils.StrInSlice("non_commercial_website", oa.Location.Locations) && !utils.StrInSlice("institutional_repository", oa.Location.Locations) && !utils.StrInSlice("non_commercial_repository", oa.Location.Locations) {
@@ -154,6 +115,36 @@ func (r *Response) GetLicense() string {
 	return license
 }
 
+// RepositoryEmbargo returns the shortest embargo, in months, after which a version
+// of the article (submitted, accepted or published) may be deposited in a repository
+// ok is false if no publisher policy allows it
+func (r *Response) RepositoryEmbargo(version string) (months int, ok bool) {
+	for _, p := range r.Publications {
+		for _, policy := range p.PublisherPolicies {
+			for _, oa := range policy.PermittedOa {
+				if !utils.StrInSlice(version, oa.ArticleVersion) || !inRepository(oa.Location.Locations) {
+					continue
+				}
+				if m := oa.Embargo.Months(); !ok || m < months {
+					months, ok = m, true
+				}
+			}
+		}
+	}
+
+	return months, ok
+}
+
+func inRepository(locations []string) bool {
+	for _, l := range locations {
+		if strings.Contains(l, "repository") || strings.HasSuffix(l, "website") || l == "funder_designated_location" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (l License) Uri() string {
 	c := strings.Split(l.Value, "_")
 	if c[0] == "cc" {
@@ -174,37 +165,12 @@ func (l License) Uri() string {
 }
 
 func FindIssnLicense(i string) string {
-	id := GetIdFromIssn(i)
-	if id == "" {
-		log.Println("Could not find publication ID for ISSN", i)
-		return ""
+	r, err := GetIssnPublication(i)
+	if err == ErrNoApiKey {
+		log.Fatal(err)
 	}
-
-	filter := fmt.Sprintf("[[\"id\",\"equals\",\"%s\"]]", id)
-	romeoApiKey := os.Getenv("SHERPA_ROMEO_API_KEY")
-	if romeoApiKey == "" {
-		log.Fatal("The SHERPA_ROMEO_API_KEY environment variable was not found.")
-	}
-
-	romeUrl := fmt.Sprintf("https://v2.sherpa.ac.uk/cgi/retrieve?item-type=publication&format=Json&limit=10&offset=0&order=-id&filter=%s&api-key=%s", neturl.QueryEscape(filter), romeoApiKey)
-	d, _ := utils.MkTmpDir(filepath.Join("issns", "ids"))
-	d = filepath.Join(d, id)
-	publication := utils.CheckCachedFile(d)
-	if publication == nil {
-		publication = GetPublication(romeUrl)
-		if publication != nil {
-			utils.WriteCachedFile(d, string(publication))
-		}
-	}
-	if publication == nil {
-		log.Println("Could not find publication info for", i)
-		return ""
-	}
-
-	var r Response
-	err := json.Unmarshal(publication, &r)
 	if err != nil {
-		log.Printf("Unable to read publication: %v", err)
+		log.Println(err)
 		return ""
 	}
 
@@ -0,0 +1,133 @@
+package s2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// URL is the Semantic Scholar Graph API
+var URL = "https://api.semanticscholar.org/graph/v1"
+
+// RequestDelay keeps us under the one request a second an API key is allowed
+const RequestDelay = time.Second
+
+// MaxLimit is the most papers the author papers endpoint returns in a page
+const MaxLimit = 1000
+
+// paperFields are the fields we ask Semantic Scholar for
+var paperFields = []string{
+	"paperId",
+	"externalIds",
+	"url",
+	"title",
+	"abstract",
+	"venue",
+	"year",
+	"publicationDate",
+	"publicationTypes",
+	"journal",
+	"authors",
+	"openAccessPdf",
+}
+
+// Paper is Semantic Scholar's record of a paper
+type Paper struct {
+	PaperID          string         `json:"paperId"`
+	ExternalIDs      ExternalIDs    `json:"externalIds"`
+	URL              string         `json:"url"`
+	Title            string         `json:"title"`
+	Abstract         string         `json:"abstract"`
+	Venue            string         `json:"venue"`
+	Year             int            `json:"year"`
+	PublicationDate  string         `json:"publicationDate"`
+	PublicationTypes []string       `json:"publicationTypes"`
+	Journal          *Journal       `json:"journal"`
+	Authors          []Author       `json:"authors"`
+	OpenAccessPDF    *OpenAccessPDF `json:"openAccessPdf"`
+}
+
+// ExternalIDs are the paper's identifiers in other indexes
+type ExternalIDs struct {
+	DOI      string `json:"DOI"`
+	ArXiv    string `json:"ArXiv"`
+	DBLP     string `json:"DBLP"`
+	PubMed   string `json:"PubMed"`
+	CorpusID int    `json:"CorpusId"`
+}
+
+type Journal struct {
+	Name   string `json:"name"`
+	Volume string `json:"volume"`
+	Pages  string `json:"pages"`
+}
+
+type Author struct {
+	AuthorID string `json:"authorId"`
+	Name     string `json:"name"`
+}
+
+type OpenAccessPDF struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+// Page is a page of an author's papers
+type Page struct {
+	Offset int `json:"offset"`
+	// Next is the offset of the next page, 0 on the last one
+	Next int     `json:"next"`
+	Data []Paper `json:"data"`
+}
+
+// GetAuthorPapers returns a page of the papers by a Semantic Scholar author
+// apiKey is optional but without one requests share a rate limit with everyone else's
+func GetAuthorPapers(authorID string, offset, limit int, apiKey string) (*Page, error) {
+	params := url.Values{}
+	params.Set("fields", strings.Join(paperFields, ","))
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("limit", strconv.Itoa(limit))
+	u := fmt.Sprintf("%s/author/%s/papers?%s", URL, url.PathEscape(strings.TrimSpace(authorID)), params.Encode())
+
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["x-api-key"] = apiKey
+	}
+	body, err := utils.Fetch(u, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Page
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("could not unmarshal Semantic Scholar papers for %s: %v", authorID, err)
+	}
+
+	return &p, nil
+}
+
+// DOI returns the paper's DOI, leaving out arXiv's DOIs
+func (p Paper) DOI() string {
+	if strings.HasPrefix(strings.ToLower(p.ExternalIDs.DOI), "10.48550/arxiv.") {
+		return ""
+	}
+
+	return p.ExternalIDs.DOI
+}
+
+// ArxivID returns the paper's arXiv ID e.g. 2101.00001, if it's on arXiv
+func (p Paper) ArxivID() string {
+	if p.ExternalIDs.ArXiv != "" {
+		return p.ExternalIDs.ArXiv
+	}
+	if d := p.ExternalIDs.DOI; strings.HasPrefix(strings.ToLower(d), "10.48550/arxiv.") {
+		return d[len("10.48550/arxiv."):]
+	}
+
+	return ""
+}
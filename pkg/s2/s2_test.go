@@ -0,0 +1,60 @@
+package s2_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/s2"
+)
+
+func TestGetAuthorPapers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "secret" {
+			t.Errorf("expected the API key in a header, got %v", r.Header)
+		}
+		if r.URL.Path != "/author/1741101/papers" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			fmt.Fprintln(w, `{"offset":0,"next":2,"data":[
+				{"paperId":"abc","externalIds":{"DOI":"10.1145/3397271.3401001","DBLP":"conf/sigir/SmithD20","CorpusId":123},"title":"Ranking","year":2020,
+				 "authors":[{"authorId":"1741101","name":"Jane Smith"}],"openAccessPdf":{"url":"https://example.org/ranking.pdf","status":"GREEN"}},
+				{"paperId":"def","externalIds":{"DOI":"10.48550/arXiv.2101.00001","CorpusId":456},"title":"A Preprint","year":2021,"journal":null,"openAccessPdf":null}
+			]}`)
+		default:
+			fmt.Fprintln(w, `{"offset":2,"data":[{"paperId":"ghi","externalIds":{"CorpusId":789},"title":"Unindexed","venue":"Workshop"}]}`)
+		}
+	}))
+	defer ts.Close()
+	s2.URL = ts.URL
+
+	p, err := s2.GetAuthorPapers("1741101", 0, 2, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Next != 2 || len(p.Data) != 2 {
+		t.Fatalf("unexpected page %+v", p)
+	}
+	if paper := p.Data[0]; paper.DOI() != "10.1145/3397271.3401001" || paper.ArxivID() != "" || paper.ExternalIDs.CorpusID != 123 || paper.OpenAccessPDF.URL == "" {
+		t.Errorf("unexpected paper %+v", paper)
+	}
+	if paper := p.Data[1]; paper.DOI() != "" || paper.ArxivID() != "2101.00001" {
+		t.Errorf("expected an arXiv DOI to be an arXiv ID, got %q %q", paper.DOI(), paper.ArxivID())
+	}
+
+	p, err = s2.GetAuthorPapers("1741101", 2, 2, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Next != 0 || p.Data[0].Venue != "Workshop" {
+		t.Errorf("expected the last page, got %+v", p)
+	}
+
+	if _, err := s2.GetAuthorPapers("missing", 0, 2, "secret"); err == nil {
+		t.Error("expected an error for an author Semantic Scholar doesn't have")
+	}
+}
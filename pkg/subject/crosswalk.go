@@ -0,0 +1,223 @@
+package subject
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Term is a heading in a controlled vocabulary
+type Term struct {
+	Label      string
+	URI        string
+	Vocabulary string
+}
+
+// String formats the term for Islandora Workbench's field_subject
+// prefixing the label with the vocabulary when one is set
+func (t Term) String() string {
+	if t.Vocabulary == "" {
+		return t.Label
+	}
+
+	return fmt.Sprintf("%s:%s", t.Vocabulary, t.Label)
+}
+
+// Terms are the subjects of a record
+type Terms []Term
+
+// Add appends the terms the record doesn't already have
+func (ts Terms) Add(terms ...Term) Terms {
+	for _, t := range terms {
+		if !ts.has(t) {
+			ts = append(ts, t)
+		}
+	}
+
+	return ts
+}
+
+func (ts Terms) has(t Term) bool {
+	for _, existing := range ts {
+		if existing.String() == t.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Labels formats the terms for field_subject
+func (ts Terms) Labels() string {
+	labels := []string{}
+	for _, t := range ts {
+		labels = append(labels, t.String())
+	}
+
+	return strings.Join(labels, "|")
+}
+
+// URIs formats the terms' URIs for field_subject_uri in the same order as Labels
+// terms without a URI are left empty so the two line up, and it's "" if none of them have one
+func (ts Terms) URIs() string {
+	uris := []string{}
+	found := false
+	for _, t := range ts {
+		uris = append(uris, t.URI)
+		found = found || t.URI != ""
+	}
+	if !found {
+		return ""
+	}
+
+	return strings.Join(uris, "|")
+}
+
+// Crosswalk maps source subjects (arXiv categories, Crossref subjects)
+// to terms in controlled vocabularies like LCSH, FAST or a local taxonomy
+// it's safe to share between goroutines
+type Crosswalk struct {
+	terms    map[string][]Term
+	mu       sync.Mutex
+	unmapped map[string]map[string]int
+}
+
+// LoadCrosswalk reads a crosswalk CSV file with the columns
+// source,subject,label,uri,vocabulary
+// source is "arxiv" or "crossref" (or "*" to match either)
+// subject is the arXiv category (e.g. cs.DL) or Crossref subject string,
+// matched case insensitively.
+// A subject can be listed more than once to map it to multiple terms.
+func LoadCrosswalk(path string) (*Crosswalk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadCrosswalk(f)
+}
+
+func ReadCrosswalk(r io.Reader) (*Crosswalk, error) {
+	c := &Crosswalk{
+		terms:    map[string][]Term{},
+		unmapped: map[string]map[string]int{},
+	}
+
+	rd := csv.NewReader(r)
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	header, err := rd.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read crosswalk header: %v", err)
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"source", "subject", "label"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("crosswalk is missing the %q column", required)
+		}
+	}
+	get := func(row []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	for {
+		row, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read crosswalk: %v", err)
+		}
+		subject := get(row, "subject")
+		label := get(row, "label")
+		if subject == "" || label == "" {
+			continue
+		}
+		k := key(get(row, "source"), subject)
+		c.terms[k] = append(c.terms[k], Term{
+			Label:      label,
+			URI:        get(row, "uri"),
+			Vocabulary: get(row, "vocabulary"),
+		})
+	}
+
+	return c, nil
+}
+
+func key(source, subject string) string {
+	return strings.ToLower(source) + "\x00" + strings.ToLower(strings.TrimSpace(subject))
+}
+
+// Map returns the vocabulary terms for a subject from source
+// subjects without a mapping are recorded for the unmapped report
+func (c *Crosswalk) Map(source, subject string) ([]Term, bool) {
+	if terms, ok := c.terms[key(source, subject)]; ok {
+		return terms, true
+	}
+	if terms, ok := c.terms[key("*", subject)]; ok {
+		return terms, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unmapped[source] == nil {
+		c.unmapped[source] = map[string]int{}
+	}
+	c.unmapped[source][subject]++
+
+	return nil, false
+}
+
+// WriteUnmapped writes a CSV of the subjects Map couldn't find a term for
+// and how often they were seen, most frequent first
+func (c *Crosswalk) WriteUnmapped(w io.Writer) error {
+	type row struct {
+		source  string
+		subject string
+		count   int
+	}
+	rows := []row{}
+	c.mu.Lock()
+	for source, subjects := range c.unmapped {
+		for subject, count := range subjects {
+			rows = append(rows, row{source, subject, count})
+		}
+	}
+	c.mu.Unlock()
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].count != rows[j].count {
+			return rows[i].count > rows[j].count
+		}
+		if rows[i].source != rows[j].source {
+			return rows[i].source < rows[j].source
+		}
+		return rows[i].subject < rows[j].subject
+	})
+
+	wr := csv.NewWriter(w)
+	err := wr.Write([]string{"source", "subject", "count"})
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		err = wr.Write([]string{r.source, r.subject, fmt.Sprintf("%d", r.count)})
+		if err != nil {
+			return err
+		}
+	}
+	wr.Flush()
+
+	return wr.Error()
+}
@@ -0,0 +1,93 @@
+package subject_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/subject"
+)
+
+const crosswalk = `source,subject,label,uri,vocabulary
+arxiv,cs.DL,Digital libraries,http://id.loc.gov/authorities/subjects/sh95008857,lcsh
+arxiv,cs.DL,Digital libraries,http://id.worldcat.org/fast/893783,fast
+crossref,General Medicine,Medicine,,
+*,Library and Information Sciences,Library science,http://id.loc.gov/authorities/subjects/sh85076502,lcsh
+`
+
+func TestCrosswalk(t *testing.T) {
+	c, err := subject.ReadCrosswalk(strings.NewReader(crosswalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	terms, ok := c.Map("arxiv", "cs.dl")
+	if !ok || len(terms) != 2 {
+		t.Fatalf("Expected two terms for cs.DL, got %v", terms)
+	}
+	if terms[0].String() != "lcsh:Digital libraries" || terms[1].URI != "http://id.worldcat.org/fast/893783" {
+		t.Errorf("Unexpected terms %v", terms)
+	}
+
+	terms, ok = c.Map("crossref", "General Medicine")
+	if !ok || !reflect.DeepEqual(terms, []subject.Term{{Label: "Medicine"}}) {
+		t.Errorf("Unexpected terms for General Medicine %v", terms)
+	}
+	if terms[0].String() != "Medicine" {
+		t.Errorf("Expected a term without a vocabulary to be its label, got %q", terms[0].String())
+	}
+
+	if _, ok := c.Map("arxiv", "Library and Information Sciences"); !ok {
+		t.Error("Expected a wildcard source to match")
+	}
+	if _, ok := c.Map("crossref", "General Medicine "); !ok {
+		t.Error("Expected subjects to be trimmed")
+	}
+
+	for _, s := range []string{"cs.AI", "cs.AI", "math.CO"} {
+		if _, ok := c.Map("arxiv", s); ok {
+			t.Errorf("Expected %s to be unmapped", s)
+		}
+	}
+	c.Map("crossref", "Oncology")
+
+	var b bytes.Buffer
+	if err := c.WriteUnmapped(&b); err != nil {
+		t.Fatal(err)
+	}
+	expected := "source,subject,count\narxiv,cs.AI,2\narxiv,math.CO,1\ncrossref,Oncology,1\n"
+	if b.String() != expected {
+		t.Errorf("Expected unmapped report %q, got %q", expected, b.String())
+	}
+}
+
+func TestTerms(t *testing.T) {
+	c, err := subject.ReadCrosswalk(strings.NewReader(crosswalk))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	terms := subject.Terms{}
+	for _, s := range [][]string{{"arxiv", "cs.DL"}, {"arxiv", "cs.DL"}, {"crossref", "General Medicine"}} {
+		mapped, _ := c.Map(s[0], s[1])
+		terms = terms.Add(mapped...)
+	}
+	if labels := terms.Labels(); labels != "lcsh:Digital libraries|fast:Digital libraries|Medicine" {
+		t.Errorf("Unexpected field_subject %q", labels)
+	}
+	if uris := terms.URIs(); uris != "http://id.loc.gov/authorities/subjects/sh95008857|http://id.worldcat.org/fast/893783|" {
+		t.Errorf("Unexpected field_subject_uri %q", uris)
+	}
+
+	if uris := (subject.Terms{{Label: "Physics"}}).URIs(); uris != "" {
+		t.Errorf("Expected no field_subject_uri for terms without URIs, got %q", uris)
+	}
+}
+
+func TestReadCrosswalkMissingColumns(t *testing.T) {
+	_, err := subject.ReadCrosswalk(strings.NewReader("subject,label\ncs.DL,Digital libraries\n"))
+	if err == nil {
+		t.Error("Expected an error for a crosswalk without a source column")
+	}
+}
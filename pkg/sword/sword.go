@@ -0,0 +1,257 @@
+package sword
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// BinaryPackaging is how a file is deposited as-is, without being unpacked
+const BinaryPackaging = "http://purl.org/net/sword/package/Binary"
+
+// Credentials are what SWORD requests are authenticated with
+type Credentials struct {
+	Username string
+	Password string
+	// OnBehalfOf is the user to deposit as when depositing through a mediated account
+	OnBehalfOf string
+}
+
+// Entry is the Dublin Core metadata of a deposit
+type Entry struct {
+	Title    string
+	Abstract string
+	// Creators are Family, Given
+	Creators  []string
+	Issued    string
+	Publisher string
+	// Rights is the license's URI
+	Rights string
+	// Identifiers are the article's identifiers e.g. https://doi.org/10.1000/xyz123
+	Identifiers []string
+	// Citation is the journal, volume, issue and pages the article was published in
+	Citation string
+	Subjects []string
+}
+
+// Receipt is the deposit receipt a SWORD server returns for a deposit
+type Receipt struct {
+	ID    string `xml:"id"`
+	Links []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Treatment string `xml:"http://purl.org/net/sword/terms/ treatment"`
+}
+
+// Link returns the href of the receipt's first link with a rel
+func (r Receipt) Link(rel string) string {
+	for _, l := range r.Links {
+		if l.Rel == rel {
+			return l.Href
+		}
+	}
+
+	return ""
+}
+
+// EditIRI is where the deposit's metadata is updated
+func (r Receipt) EditIRI() string {
+	return r.Link("edit")
+}
+
+// EditMediaIRI is where files are added to the deposit
+func (r Receipt) EditMediaIRI() string {
+	return r.Link("edit-media")
+}
+
+// SwordEditIRI is where the deposit is completed, which is usually the EditIRI
+func (r Receipt) SwordEditIRI() string {
+	if iri := r.Link("http://purl.org/net/sword/terms/add"); iri != "" {
+		return iri
+	}
+
+	return r.EditIRI()
+}
+
+// URL is the deposit's page in the repository, if the server says where it is
+func (r Receipt) URL() string {
+	return r.Link("alternate")
+}
+
+// Deposit creates an in progress deposit of an entry in a collection
+// which files can be added to until it's completed
+func Deposit(collection string, e Entry, c Credentials) (*Receipt, error) {
+	body, err := e.MarshalAtom()
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"Content-Type": "application/atom+xml;type=entry",
+		"In-Progress":  "true",
+	}
+
+	return receipt(c, "POST", collection, headers, bytes.NewReader(body))
+}
+
+// AddFile adds a file to a deposit as-is
+func AddFile(r *Receipt, path string, c Credentials) error {
+	iri := r.EditMediaIRI()
+	if iri == "" {
+		return fmt.Errorf("the deposit receipt for %s has no edit-media link to add files to", r.ID)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	headers := map[string]string{
+		"Content-Type":        contentType,
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filepath.Base(path)}),
+		"Packaging":           BinaryPackaging,
+		"In-Progress":         "true",
+	}
+	resp, err := request(c, "POST", iri, headers, f)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Complete tells the server the deposit is finished so it can go into the repository's workflow
+func Complete(r *Receipt, c Credentials) error {
+	iri := r.SwordEditIRI()
+	if iri == "" {
+		return fmt.Errorf("the deposit receipt for %s has no edit link to complete it with", r.ID)
+	}
+	resp, err := request(c, "POST", iri, map[string]string{"In-Progress": "false"}, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// DepositFile deposits an entry with a file, completing the deposit if complete is true
+// when the file can't be added or the deposit completed its receipt is returned along with the error
+// so it can be finished or deleted in the repository instead of being deposited again
+func DepositFile(collection string, e Entry, path string, complete bool, c Credentials) (*Receipt, error) {
+	r, err := Deposit(collection, e, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := AddFile(r, path, c); err != nil {
+		return r, fmt.Errorf("%s was created but the file couldn't be added: %v", r.ID, err)
+	}
+	if complete {
+		if err := Complete(r, c); err != nil {
+			return r, fmt.Errorf("%s was created but couldn't be completed: %v", r.ID, err)
+		}
+	}
+
+	return r, nil
+}
+
+// receipt makes a request that returns a deposit receipt
+// servers that don't send the receipt back point to it in the Location header
+func receipt(c Credentials, method, u string, headers map[string]string, body io.Reader) (*Receipt, error) {
+	resp, err := request(c, method, u, headers, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return nil, fmt.Errorf("%s returned no deposit receipt", u)
+		}
+		return receipt(c, "GET", location, nil, nil)
+	}
+
+	var r Receipt
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the deposit receipt from %s: %v", u, err)
+	}
+
+	return &r, nil
+}
+
+func request(c Credentials, method, u string, headers map[string]string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if c.OnBehalfOf != "" {
+		req.Header.Set("On-Behalf-Of", c.OnBehalfOf)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s returned a non-200 status code: %d", method, u, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// MarshalAtom writes the entry as an Atom entry with its metadata in DC terms
+func (e Entry) MarshalAtom() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<entry xmlns="http://www.w3.org/2005/Atom" xmlns:dcterms="http://purl.org/dc/terms/">` + "\n")
+	elements := []struct {
+		name   string
+		values []string
+	}{
+		{"title", []string{e.Title}},
+		{"dcterms:title", []string{e.Title}},
+		{"dcterms:creator", e.Creators},
+		{"dcterms:abstract", []string{e.Abstract}},
+		{"dcterms:issued", []string{e.Issued}},
+		{"dcterms:publisher", []string{e.Publisher}},
+		{"dcterms:rights", []string{e.Rights}},
+		{"dcterms:identifier", e.Identifiers},
+		{"dcterms:bibliographicCitation", []string{e.Citation}},
+		{"dcterms:subject", e.Subjects},
+	}
+	for _, el := range elements {
+		for _, v := range el.values {
+			if v == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, "  <%s>", el.name)
+			if err := xml.EscapeText(&buf, []byte(v)); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "</%s>\n", el.name)
+		}
+	}
+	buf.WriteString("</entry>\n")
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,116 @@
+package sword_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/sword"
+)
+
+func TestDeposit(t *testing.T) {
+	var ts *httptest.Server
+	completed := false
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "depositor" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/collection/1":
+			b, _ := io.ReadAll(r.Body)
+			if r.Header.Get("In-Progress") != "true" || !strings.Contains(string(b), "<dcterms:title>Ranking &amp; More</dcterms:title>") ||
+				!strings.Contains(string(b), "<dcterms:creator>Smith, Jane</dcterms:creator>") || strings.Contains(string(b), "dcterms:publisher") {
+				t.Errorf("unexpected entry %s", b)
+			}
+			// the receipt is left for the client to fetch
+			w.Header().Set("Location", ts.URL+"/edit/7")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "GET" && r.URL.Path == "/edit/7":
+			fmt.Fprintf(w, `<entry xmlns="http://www.w3.org/2005/Atom" xmlns:sword="http://purl.org/net/sword/terms/">
+				<id>hdl:123/7</id>
+				<link rel="edit" href="%[1]s/edit/7"/>
+				<link rel="edit-media" href="%[1]s/edit-media/7"/>
+				<link rel="alternate" href="https://repository.example.edu/handle/123/7"/>
+				<sword:treatment>Deposited items will undergo review</sword:treatment>
+			</entry>`, ts.URL)
+		case r.Method == "POST" && r.URL.Path == "/edit-media/7":
+			if r.Header.Get("Packaging") != sword.BinaryPackaging || r.Header.Get("Content-Disposition") != "attachment; filename=paper.pdf" || r.Header.Get("Content-Type") != "application/pdf" {
+				t.Errorf("unexpected file headers %v", r.Header)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "POST" && r.URL.Path == "/edit/7":
+			completed = r.Header.Get("In-Progress") == "false"
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	pdf := filepath.Join(t.TempDir(), "paper.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := sword.Credentials{Username: "depositor", Password: "secret"}
+	e := sword.Entry{Title: "Ranking & More", Creators: []string{"Smith, Jane"}}
+
+	if _, err := sword.Deposit(ts.URL+"/collection/1", e, sword.Credentials{}); err == nil {
+		t.Error("expected an error without credentials")
+	}
+	r, err := sword.Deposit(ts.URL+"/collection/1", e, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "hdl:123/7" || r.URL() != "https://repository.example.edu/handle/123/7" || r.SwordEditIRI() != ts.URL+"/edit/7" || r.Treatment == "" {
+		t.Errorf("unexpected receipt %+v", r)
+	}
+	if err := sword.AddFile(r, pdf, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := sword.Complete(r, c); err != nil {
+		t.Fatal(err)
+	}
+	if !completed {
+		t.Error("expected the deposit to be completed")
+	}
+}
+
+func TestDepositFileAddFails(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/collection/1":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `<entry xmlns="http://www.w3.org/2005/Atom">
+				<id>hdl:123/8</id>
+				<link rel="edit" href="%[1]s/edit/8"/>
+				<link rel="edit-media" href="%[1]s/edit-media/8"/>
+			</entry>`, ts.URL)
+		case r.Method == "POST" && r.URL.Path == "/edit-media/8":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	pdf := filepath.Join(t.TempDir(), "paper.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := sword.DepositFile(ts.URL+"/collection/1", sword.Entry{Title: "Ranking"}, pdf, true, sword.Credentials{})
+	if err == nil {
+		t.Fatal("expected an error when the file can't be added")
+	}
+	if r == nil || r.ID != "hdl:123/8" || r.EditIRI() != ts.URL+"/edit/8" {
+		t.Errorf("expected the in progress deposit's receipt to be returned, got %+v", r)
+	}
+}
@@ -0,0 +1,93 @@
+package unpaywall
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/lehigh-university-libraries/papercut/internal/utils"
+)
+
+// URL is the Unpaywall API
+var URL = "https://api.unpaywall.org/v2"
+
+// Response is Unpaywall's record of a DOI's open access copies
+type Response struct {
+	DOI            string     `json:"doi"`
+	IsOA           bool       `json:"is_oa"`
+	OAStatus       string     `json:"oa_status"`
+	BestOALocation *Location  `json:"best_oa_location"`
+	OALocations    []Location `json:"oa_locations"`
+}
+
+// Location is somewhere an open access copy of the article can be found
+type Location struct {
+	URL       string `json:"url"`
+	URLForPdf string `json:"url_for_pdf"`
+	// License is a short name like cc-by, cc0 or publisher-specific-oa
+	License  string `json:"license"`
+	Version  string `json:"version"`
+	HostType string `json:"host_type"`
+	// RepositoryInstitution names the repository of a copy with HostType repository
+	RepositoryInstitution string `json:"repository_institution"`
+}
+
+// GetDoi returns Unpaywall's record of a DOI, caching it with the DOI's other responses
+// Unpaywall requires an email address with every request
+func GetDoi(d, email string) (*Response, error) {
+	if email == "" {
+		return nil, fmt.Errorf("an email address is required to use Unpaywall")
+	}
+	dirPath, err := utils.MkTmpDir(filepath.Join("dois", d))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cached file directory: %v", err)
+	}
+
+	u := fmt.Sprintf("%s/%s?%s", URL, d, url.Values{"email": {email}}.Encode())
+	result := utils.GetResult(filepath.Join(dirPath, "unpaywall.json"), u, "application/json")
+	if result == nil {
+		return nil, fmt.Errorf("could not find DOI %s in Unpaywall", d)
+	}
+
+	var r Response
+	if err := json.Unmarshal(result, &r); err != nil {
+		return nil, fmt.Errorf("could not unmarshal Unpaywall JSON for %s: %v", d, err)
+	}
+
+	return &r, nil
+}
+
+// License returns the license of the best open access location
+// or the first location with one, preferring the published version
+func (r Response) License() string {
+	if licenses := r.Licenses(); len(licenses) > 0 {
+		return licenses[0]
+	}
+
+	return ""
+}
+
+// Licenses returns the licenses of the open access locations without duplicates
+// starting with the best location's, then the published versions' and then the rest
+func (r Response) Licenses() []string {
+	licenses := []string{}
+	add := func(l string) {
+		if l != "" && !utils.StrInSlice(l, licenses) {
+			licenses = append(licenses, l)
+		}
+	}
+	if r.BestOALocation != nil {
+		add(r.BestOALocation.License)
+	}
+	for _, l := range r.OALocations {
+		if l.Version == "publishedVersion" {
+			add(l.License)
+		}
+	}
+	for _, l := range r.OALocations {
+		add(l.License)
+	}
+
+	return licenses
+}
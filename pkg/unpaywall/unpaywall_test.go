@@ -0,0 +1,79 @@
+package unpaywall_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/unpaywall"
+)
+
+func TestGetDoi(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("email") != "library@example.edu" {
+			t.Errorf("unexpected query %v", r.URL.Query())
+		}
+		if r.URL.Path != "/10.1000/abc" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"doi":"10.1000/abc","is_oa":true,"oa_status":"green","best_oa_location":{"url":"https://repo.example.edu/1","license":null,"version":"acceptedVersion"},
+			"oa_locations":[
+				{"url":"https://repo.example.edu/1","license":null,"version":"acceptedVersion"},
+				{"url":"https://preprints.example.org/1","license":"cc-by-nc","version":"submittedVersion"},
+				{"url":"https://journal.example.com/1","license":"cc-by","version":"publishedVersion"}
+			]}`)
+	}))
+	defer ts.Close()
+	unpaywall.URL = ts.URL
+
+	r, err := unpaywall.GetDoi("10.1000/abc", "library@example.edu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsOA || r.OAStatus != "green" {
+		t.Errorf("unexpected response %+v", r)
+	}
+	if license := r.License(); license != "cc-by" {
+		t.Errorf("expected the published version's license, got %q", license)
+	}
+
+	if _, err := unpaywall.GetDoi("10.1000/missing", "library@example.edu"); err == nil {
+		t.Error("expected an error for a DOI Unpaywall doesn't have")
+	}
+	if _, err := unpaywall.GetDoi("10.1000/abc", ""); err == nil {
+		t.Error("expected an error without an email address")
+	}
+}
+
+func TestLicense(t *testing.T) {
+	r := unpaywall.Response{
+		BestOALocation: &unpaywall.Location{License: "cc0"},
+		OALocations:    []unpaywall.Location{{License: "cc-by", Version: "publishedVersion"}},
+	}
+	if license := r.License(); license != "cc0" {
+		t.Errorf("expected the best location's license, got %q", license)
+	}
+
+	r = unpaywall.Response{OALocations: []unpaywall.Location{{License: "cc-by-nd", Version: "acceptedVersion"}}}
+	if license := r.License(); license != "cc-by-nd" {
+		t.Errorf("expected the only license, got %q", license)
+	}
+
+	r = unpaywall.Response{
+		BestOALocation: &unpaywall.Location{License: "publisher-specific-oa"},
+		OALocations: []unpaywall.Location{
+			{License: "publisher-specific-oa", Version: "publishedVersion"},
+			{License: "cc-by-nc", Version: "acceptedVersion"},
+			{License: "cc-by", Version: "publishedVersion"},
+		},
+	}
+	expected := []string{"publisher-specific-oa", "cc-by", "cc-by-nc"}
+	if licenses := r.Licenses(); !reflect.DeepEqual(licenses, expected) {
+		t.Errorf("expected %v, got %v", expected, licenses)
+	}
+}
@@ -0,0 +1,237 @@
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/crossref"
+)
+
+// Job types
+const (
+	// Arxiv searches arXiv with Query
+	Arxiv = "arxiv"
+	// Orcid finds the works Crossref has for each of ORCIDs
+	Orcid = "orcid"
+	// Crossref searches Crossref works by Affiliation and/or Filters
+	Crossref = "crossref"
+)
+
+// Config is the set of harvest jobs papercut watch runs
+type Config struct {
+	// Output is the directory harvests are written to
+	Output string `json:"output"`
+	// Mailto is sent with Crossref requests to use its polite pool
+	Mailto string `json:"mailto"`
+	Jobs   []Job  `json:"jobs"`
+}
+
+// Job is a harvest that's run every Interval
+type Job struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Query       string            `json:"query,omitempty"`
+	ORCIDs      []string          `json:"orcids,omitempty"`
+	Affiliation string            `json:"affiliation,omitempty"`
+	Filters     map[string]string `json:"filters,omitempty"`
+	Interval    Duration          `json:"interval"`
+	// Since limits the first harvest to works from this date (YYYY-MM-DD)
+	// otherwise the first harvest fetches everything
+	Since string `json:"since,omitempty"`
+	// Args are passed on to papercut search arxiv or papercut get doi
+	// e.g. ["--layout", "{source}/{year}/{id}"]
+	Args []string `json:"args,omitempty"`
+}
+
+// Duration is a time.Duration written like "12h" or, in days, "7d"
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("durations must be strings like \"24h\" or \"7d\": %v", err)
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		d.Duration = time.Duration(n) * 24 * time.Hour
+		return nil
+	}
+
+	var err error
+	d.Duration, err = time.ParseDuration(s)
+	return err
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// LoadConfig reads and checks a jobs config file
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %v", path, err)
+	}
+	if c.Output == "" {
+		c.Output = "harvests"
+	}
+
+	names := map[string]bool{}
+	for _, j := range c.Jobs {
+		if j.Name == "" {
+			return nil, fmt.Errorf("every job needs a name")
+		}
+		if names[j.Name] {
+			return nil, fmt.Errorf("there is more than one job called %q", j.Name)
+		}
+		names[j.Name] = true
+		if j.Interval.Duration <= 0 {
+			return nil, fmt.Errorf("job %q needs an interval", j.Name)
+		}
+		if j.Since != "" {
+			if _, err := time.Parse(time.DateOnly, j.Since); err != nil {
+				return nil, fmt.Errorf("job %q has an invalid since date: %v", j.Name, err)
+			}
+		}
+
+		switch j.Type {
+		case Arxiv:
+			if j.Query == "" {
+				return nil, fmt.Errorf("arxiv job %q needs a query", j.Name)
+			}
+		case Orcid:
+			if len(j.ORCIDs) == 0 {
+				return nil, fmt.Errorf("orcid job %q needs orcids", j.Name)
+			}
+		case Crossref:
+			if j.Affiliation == "" && len(j.Filters) == 0 {
+				return nil, fmt.Errorf("crossref job %q needs an affiliation or filters", j.Name)
+			}
+		default:
+			return nil, fmt.Errorf("job %q has an unknown type %q (expected arxiv, orcid or crossref)", j.Name, j.Type)
+		}
+	}
+
+	return &c, nil
+}
+
+// JobState is what we remember about a job between runs
+type JobState struct {
+	LastRun     time.Time `json:"last_run"`
+	LastSuccess time.Time `json:"last_success"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastOutput  string    `json:"last_output,omitempty"`
+}
+
+// State is the JobState of every job keyed by its name
+type State map[string]JobState
+
+// LoadState reads the state file, which doesn't exist before the first run
+func LoadState(path string) (State, error) {
+	s := State{}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("could not unmarshal %s: %v", path, err)
+	}
+
+	return s, nil
+}
+
+// Save writes the state file, replacing it only once it's fully written
+func (s State) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Due reports whether a job should run now
+func (j Job) Due(s JobState, now time.Time) bool {
+	return s.LastRun.IsZero() || !now.Before(s.LastRun.Add(j.Interval.Duration))
+}
+
+// From is the start of the date range the next harvest of a job covers:
+// the start of its last successful harvest, its since date or, for a full harvest, zero
+func (j Job) From(s JobState) time.Time {
+	if !s.LastSuccess.IsZero() {
+		return s.LastSuccess
+	}
+	since, err := time.Parse(time.DateOnly, j.Since)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return since
+}
+
+// ArxivQuery limits an arXiv search query to papers submitted between from and to
+func ArxivQuery(query string, from, to time.Time) string {
+	if from.IsZero() {
+		return query
+	}
+	const arxivDate = "200601021504"
+
+	return fmt.Sprintf("(%s) AND submittedDate:[%s TO %s]", query, from.UTC().Format(arxivDate), to.UTC().Format(arxivDate))
+}
+
+// CrossrefQueries are the Crossref works searches for an orcid or crossref job
+// limited to works indexed since from
+func (j Job) CrossrefQueries(from time.Time, mailto string) []crossref.WorksQuery {
+	filters := func() map[string]string {
+		f := map[string]string{}
+		for k, v := range j.Filters {
+			f[k] = v
+		}
+		if !from.IsZero() {
+			f["from-index-date"] = from.UTC().Format(time.DateOnly)
+		}
+		return f
+	}
+
+	if j.Type == Orcid {
+		queries := []crossref.WorksQuery{}
+		for _, orcid := range j.ORCIDs {
+			f := filters()
+			f["orcid"] = strings.TrimPrefix(strings.TrimPrefix(orcid, "https://orcid.org/"), "http://orcid.org/")
+			queries = append(queries, crossref.WorksQuery{Filters: f, Mailto: mailto})
+		}
+		return queries
+	}
+
+	return []crossref.WorksQuery{{Filters: filters(), Affiliation: j.Affiliation, Mailto: mailto}}
+}
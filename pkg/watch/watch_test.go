@@ -0,0 +1,140 @@
+package watch_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/watch"
+)
+
+func writeConfig(t *testing.T, config string) string {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"mailto": "library@example.edu",
+		"jobs": [
+			{"name": "physics", "type": "arxiv", "query": "au:Smith_J", "interval": "7d", "args": ["--with-source"]},
+			{"name": "faculty", "type": "orcid", "orcids": ["https://orcid.org/0000-0002-1825-0097"], "interval": "24h", "since": "2024-01-01"},
+			{"name": "lehigh", "type": "crossref", "affiliation": "Lehigh University", "filters": {"type": "journal-article"}, "interval": "12h"}
+		]
+	}`)
+	c, err := watch.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Output != "harvests" || len(c.Jobs) != 3 {
+		t.Fatalf("unexpected config %+v", c)
+	}
+	if c.Jobs[0].Interval.Duration != 7*24*time.Hour || c.Jobs[2].Interval.Duration != 12*time.Hour {
+		t.Errorf("unexpected intervals %v %v", c.Jobs[0].Interval, c.Jobs[2].Interval)
+	}
+
+	invalid := map[string]string{
+		"no interval":  `{"jobs": [{"name": "a", "type": "arxiv", "query": "q"}]}`,
+		"no query":     `{"jobs": [{"name": "a", "type": "arxiv", "interval": "1h"}]}`,
+		"unknown type": `{"jobs": [{"name": "a", "type": "pubmed", "interval": "1h"}]}`,
+		"duplicate":    `{"jobs": [{"name": "a", "type": "arxiv", "query": "q", "interval": "1h"}, {"name": "a", "type": "arxiv", "query": "q", "interval": "1h"}]}`,
+		"bad since":    `{"jobs": [{"name": "a", "type": "arxiv", "query": "q", "interval": "1h", "since": "January"}]}`,
+		"bad interval": `{"jobs": [{"name": "a", "type": "arxiv", "query": "q", "interval": "often"}]}`,
+	}
+	for name, config := range invalid {
+		if _, err := watch.LoadConfig(writeConfig(t, config)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	job := watch.Job{Name: "a", Type: watch.Arxiv, Query: "au:Smith_J", Interval: watch.Duration{Duration: 24 * time.Hour}, Since: "2024-01-01"}
+
+	if !job.Due(watch.JobState{}, now) {
+		t.Error("expected a job that has never run to be due")
+	}
+	if job.Due(watch.JobState{LastRun: now.Add(-23 * time.Hour)}, now) {
+		t.Error("expected a job run 23 hours ago not to be due")
+	}
+	if !job.Due(watch.JobState{LastRun: now.Add(-24 * time.Hour)}, now) {
+		t.Error("expected a job run 24 hours ago to be due")
+	}
+
+	if from := job.From(watch.JobState{}); !from.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the first harvest to start from the since date, got %v", from)
+	}
+	last := now.Add(-24 * time.Hour)
+	if from := job.From(watch.JobState{LastSuccess: last}); !from.Equal(last) {
+		t.Errorf("expected the harvest to start from the last success, got %v", from)
+	}
+
+	expected := "(au:Smith_J) AND submittedDate:[202405311200 TO 202406011200]"
+	if q := watch.ArxivQuery(job.Query, last, now); q != expected {
+		t.Errorf("expected %q, got %q", expected, q)
+	}
+	if q := watch.ArxivQuery(job.Query, time.Time{}, now); q != job.Query {
+		t.Errorf("expected a full harvest to use the query as is, got %q", q)
+	}
+}
+
+func TestCrossrefQueries(t *testing.T) {
+	from := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	job := watch.Job{Type: watch.Orcid, ORCIDs: []string{"https://orcid.org/0000-0002-1825-0097", "0000-0001-5109-3700"}}
+	queries := job.CrossrefQueries(from, "library@example.edu")
+	if len(queries) != 2 {
+		t.Fatalf("expected a query per ORCID, got %d", len(queries))
+	}
+	filter := queries[0].Params("*").Get("filter")
+	if filter != "from-index-date:2024-05-31,orcid:0000-0002-1825-0097" {
+		t.Errorf("unexpected filter %q", filter)
+	}
+	if queries[1].Filters["orcid"] != "0000-0001-5109-3700" || queries[1].Mailto != "library@example.edu" {
+		t.Errorf("unexpected query %+v", queries[1])
+	}
+
+	job = watch.Job{Type: watch.Crossref, Affiliation: "Lehigh University", Filters: map[string]string{"type": "journal-article"}}
+	queries = job.CrossrefQueries(time.Time{}, "")
+	params := queries[0].Params("*")
+	if params.Get("filter") != "type:journal-article" || params.Get("query.affiliation") != "Lehigh University" {
+		t.Errorf("unexpected params %v", params)
+	}
+	if job.Filters["from-index-date"] != "" {
+		t.Error("the job's filters were modified")
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watch-state.json")
+	s, err := watch.LoadState(path)
+	if err != nil || len(s) != 0 {
+		t.Fatalf("expected an empty state before the first run, got %v %v", s, err)
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s["physics"] = watch.JobState{LastRun: now, LastSuccess: now, LastOutput: "harvests/physics.csv"}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err = watch.LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s["physics"].LastSuccess.Equal(now) || s["physics"].LastOutput != "harvests/physics.csv" {
+		t.Errorf("unexpected state %+v", s)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	for _, f := range files {
+		if strings.Contains(filepath.Base(f), ".json.") {
+			t.Errorf("temporary state file %s was left behind", f)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package zenodo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// URL is the Zenodo REST API
+var URL = "https://zenodo.org/api"
+
+// SandboxURL is Zenodo's sandbox, where deposits can be tried out without making real records
+const SandboxURL = "https://sandbox.zenodo.org/api"
+
+// Metadata is the metadata of a Zenodo deposition
+// see https://developers.zenodo.org/#representation
+type Metadata struct {
+	UploadType      string    `json:"upload_type"`
+	PublicationType string    `json:"publication_type,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Creators        []Creator `json:"creators"`
+	// PublicationDate is YYYY-MM-DD
+	PublicationDate string `json:"publication_date,omitempty"`
+	// AccessRight is open, embargoed, restricted or closed
+	AccessRight        string              `json:"access_right"`
+	License            string              `json:"license,omitempty"`
+	Keywords           []string            `json:"keywords,omitempty"`
+	JournalTitle       string              `json:"journal_title,omitempty"`
+	JournalVolume      string              `json:"journal_volume,omitempty"`
+	JournalIssue       string              `json:"journal_issue,omitempty"`
+	JournalPages       string              `json:"journal_pages,omitempty"`
+	RelatedIdentifiers []RelatedIdentifier `json:"related_identifiers,omitempty"`
+}
+
+type Creator struct {
+	// Name is Family, Given
+	Name        string `json:"name"`
+	Affiliation string `json:"affiliation,omitempty"`
+}
+
+type RelatedIdentifier struct {
+	Identifier string `json:"identifier"`
+	// Relation is how the deposit relates to the identifier e.g. isVersionOf
+	Relation string `json:"relation"`
+}
+
+// Deposition is Zenodo's record of a deposit
+type Deposition struct {
+	ID        int    `json:"id"`
+	DOI       string `json:"doi"`
+	DOIURL    string `json:"doi_url"`
+	State     string `json:"state"`
+	Submitted bool   `json:"submitted"`
+	Links     struct {
+		// Bucket is where the deposit's files are uploaded
+		Bucket  string `json:"bucket"`
+		HTML    string `json:"html"`
+		Publish string `json:"publish"`
+	} `json:"links"`
+}
+
+// Create starts a deposit with its metadata, which stays a draft until it's published
+func Create(m Metadata, token string) (*Deposition, error) {
+	body, err := json.Marshal(map[string]Metadata{"metadata": m})
+	if err != nil {
+		return nil, err
+	}
+
+	var d Deposition
+	err = do("POST", URL+"/deposit/depositions", token, "application/json", bytes.NewReader(body), &d)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Upload adds a file to a deposit, named after the file's base name
+func Upload(d *Deposition, path, token string) error {
+	if d.Links.Bucket == "" {
+		return fmt.Errorf("deposition %d has no bucket to upload files to", d.ID)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	u := fmt.Sprintf("%s/%s", d.Links.Bucket, url.PathEscape(filepath.Base(path)))
+	return do("PUT", u, token, "application/octet-stream", f, nil)
+}
+
+// Publish publishes a deposit, which mints its DOI and can't be undone
+func Publish(d *Deposition, token string) (*Deposition, error) {
+	u := d.Links.Publish
+	if u == "" {
+		u = fmt.Sprintf("%s/deposit/depositions/%d/actions/publish", URL, d.ID)
+	}
+
+	var published Deposition
+	if err := do("POST", u, token, "", nil, &published); err != nil {
+		return nil, err
+	}
+
+	return &published, nil
+}
+
+// DepositFile creates a deposition with a file, publishing it if publish is true
+// when the file can't be uploaded or the deposition published the draft is returned along with the error
+// so it can be finished or deleted in Zenodo instead of being deposited again
+func DepositFile(m Metadata, path string, publish bool, token string) (*Deposition, error) {
+	d, err := Create(m, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := Upload(d, path, token); err != nil {
+		return d, fmt.Errorf("deposition %d was created but the file couldn't be uploaded: %v", d.ID, err)
+	}
+	if !publish {
+		return d, nil
+	}
+	published, err := Publish(d, token)
+	if err != nil {
+		return d, fmt.Errorf("deposition %d was created but couldn't be published: %v", d.ID, err)
+	}
+
+	return published, nil
+}
+
+// LicenseID turns a Creative Commons URI into Zenodo's ID for the license
+// e.g. https://creativecommons.org/licenses/by-nc/4.0/ is cc-by-nc-4.0
+// returning "" for anything that isn't a Creative Commons license
+func LicenseID(uri string) string {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil || !strings.HasSuffix(u.Host, "creativecommons.org") {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 3 {
+		return ""
+	}
+	switch parts[0] {
+	case "licenses":
+		return fmt.Sprintf("cc-%s-%s", parts[1], parts[2])
+	case "publicdomain":
+		if parts[1] == "zero" {
+			return "cc0-" + parts[2]
+		}
+	}
+
+	return ""
+}
+
+func do(method, u, token, contentType string, body io.Reader, v any) error {
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode > 299 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(b, &e) == nil && e.Message != "" {
+			return fmt.Errorf("%s %s returned %d: %s", method, u, resp.StatusCode, e.Message)
+		}
+		return fmt.Errorf("%s %s returned a non-200 status code: %d", method, u, resp.StatusCode)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not unmarshal the Zenodo response from %s: %v", u, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package zenodo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lehigh-university-libraries/papercut/pkg/zenodo"
+)
+
+func TestDeposit(t *testing.T) {
+	uploaded := ""
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintln(w, `{"message":"The server could not verify that you are authorized to access the URL requested."}`)
+			return
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/deposit/depositions":
+			var body struct {
+				Metadata zenodo.Metadata `json:"metadata"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Metadata.Title != "Ranking" || body.Metadata.License != "cc-by-4.0" {
+				t.Errorf("unexpected metadata %+v %v", body.Metadata, err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":42,"state":"unsubmitted","links":{"bucket":"%s/files/abc","html":"https://zenodo.org/deposit/42","publish":"%s/deposit/depositions/42/actions/publish"}}`, ts.URL, ts.URL)
+		case r.Method == "PUT" && r.URL.Path == "/files/abc/paper.pdf":
+			b, _ := io.ReadAll(r.Body)
+			uploaded = string(b)
+			fmt.Fprintln(w, `{"key":"paper.pdf"}`)
+		case r.Method == "POST" && r.URL.Path == "/deposit/depositions/42/actions/publish":
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprintln(w, `{"id":42,"state":"done","submitted":true,"doi":"10.5281/zenodo.42","doi_url":"https://doi.org/10.5281/zenodo.42"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	zenodo.URL = ts.URL
+
+	pdf := filepath.Join(t.TempDir(), "paper.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := zenodo.Metadata{UploadType: "publication", Title: "Ranking", License: "cc-by-4.0"}
+	if _, err := zenodo.Create(m, "wrong"); err == nil {
+		t.Error("expected an error with the wrong token")
+	}
+	d, err := zenodo.Create(m, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ID != 42 || d.Links.HTML == "" {
+		t.Errorf("unexpected deposition %+v", d)
+	}
+	if err := zenodo.Upload(d, pdf, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != "%PDF-1.4" {
+		t.Errorf("expected the PDF to be uploaded, got %q", uploaded)
+	}
+	d, err = zenodo.Publish(d, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Submitted || d.DOI != "10.5281/zenodo.42" {
+		t.Errorf("expected a published deposition, got %+v", d)
+	}
+}
+
+func TestDepositFileUploadFails(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/deposit/depositions":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id":43,"state":"unsubmitted","links":{"bucket":"%s/files/def","html":"https://zenodo.org/deposit/43"}}`, ts.URL)
+		case r.Method == "PUT" && r.URL.Path == "/files/def/paper.pdf":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	zenodo.URL = ts.URL
+
+	pdf := filepath.Join(t.TempDir(), "paper.pdf")
+	if err := os.WriteFile(pdf, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := zenodo.DepositFile(zenodo.Metadata{Title: "Ranking"}, pdf, true, "secret")
+	if err == nil {
+		t.Fatal("expected an error when the upload fails")
+	}
+	if d == nil || d.ID != 43 || d.Links.HTML != "https://zenodo.org/deposit/43" {
+		t.Errorf("expected the draft deposition to be returned, got %+v", d)
+	}
+}
+
+func TestLicenseID(t *testing.T) {
+	tests := map[string]string{
+		"https://creativecommons.org/licenses/by/4.0/":       "cc-by-4.0",
+		"http://creativecommons.org/licenses/by-nc-nd/3.0":   "cc-by-nc-nd-3.0",
+		"https://creativecommons.org/publicdomain/zero/1.0/": "cc0-1.0",
+		"https://v2.sherpa.ac.uk/id/publisher_policy/1":      "",
+		"": "",
+	}
+	for uri, expected := range tests {
+		if id := zenodo.LicenseID(uri); id != expected {
+			t.Errorf("expected %s to be %q, got %q", uri, expected, id)
+		}
+	}
+}